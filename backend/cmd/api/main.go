@@ -4,18 +4,29 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
 	"github.com/Pavlushechko/itcube-education/internal/config"
 	"github.com/Pavlushechko/itcube-education/internal/db"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/events"
 	"github.com/Pavlushechko/itcube-education/internal/httpapi"
+	"github.com/Pavlushechko/itcube-education/internal/notify"
 	"github.com/Pavlushechko/itcube-education/internal/outbox"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
+	"github.com/Pavlushechko/itcube-education/internal/rules"
 	"github.com/Pavlushechko/itcube-education/internal/service"
+	"github.com/Pavlushechko/itcube-education/internal/storage"
+	"github.com/Pavlushechko/itcube-education/internal/webhook"
 )
 
 // go run .\cmd\api
@@ -28,6 +39,41 @@ func main() {
 		os.Exit(1)
 	}
 
+	verifier, err := auth.NewVerifier(auth.VerifierConfig{
+		Secret:        cfg.JWTSecret,
+		PublicKeyFile: cfg.JWTPublicKeyFile,
+		JWKSFile:      cfg.JWTJWKSFile,
+		Issuer:        cfg.JWTIssuer,
+		Audience:      cfg.JWTAudience,
+	})
+	if err != nil {
+		slog.Error("jwt config", "err", err)
+		os.Exit(1)
+	}
+	if !verifier.HasKeys() && !cfg.AuthDevHeaders {
+		slog.Error("no auth configured: set JWT_SECRET / JWT_PUBLIC_KEY_FILE / JWT_JWKS_FILE (or AUTH_DEV_HEADERS=true for local dev)")
+		os.Exit(1)
+	}
+	if cfg.AuthDevHeaders {
+		slog.Warn("AUTH_DEV_HEADERS is on: X-User-Id/X-Role headers are trusted, do not use in production")
+	}
+
+	issuer, err := auth.NewIssuer(auth.IssuerConfig{
+		Secret:         cfg.JWTSecret,
+		PrivateKeyFile: cfg.JWTPrivateKeyFile,
+		KeyID:          cfg.JWTKeyID,
+		Issuer:         cfg.JWTIssuer,
+		Audience:       cfg.JWTAudience,
+		TTL:            cfg.AccessTokenTTL,
+	})
+	if err != nil {
+		slog.Error("jwt issuer config", "err", err)
+		os.Exit(1)
+	}
+	if !issuer.Enabled() {
+		slog.Warn("no jwt signing key: /auth/login and /auth/refresh are disabled")
+	}
+
 	ctx := context.Background()
 
 	pool, err := db.NewPool(ctx, cfg.DatabaseURL)
@@ -37,45 +83,172 @@ func main() {
 	}
 	defer pool.Close()
 
+	userRepo := repo.NewUserRepo(pool)
+	authSvc := service.NewAuthService(userRepo, issuer, cfg.RefreshTokenTTL)
+	authHandler := httpapi.NewAuthHandler(authSvc)
+
+	profileRepo := repo.NewProfileRepo(pool)
+	profileHandler := httpapi.NewProfileHandler(profileRepo)
+
 	catalogRepo := repo.NewCatalogRepo(pool)
 	interviewRepo := repo.NewInterviewRepo(pool)
 
 	appRepo := repo.NewApplicationRepo(pool)
 	outboxRepo := outbox.New(pool)
 
-	appSvc := service.NewApplicationService(appRepo, catalogRepo, interviewRepo, outboxRepo)
-	invSvc := service.NewInterviewService(appRepo, catalogRepo, interviewRepo, outboxRepo)
+	guardianRepo := repo.NewGuardianRepo(pool)
+	formRepo := repo.NewFormRepo(pool)
+	eligibilityRepo := repo.NewEligibilityRepo(pool)
+
+	az := authz.New(catalogRepo, appRepo)
+
+	txm := db.NewTxManager(pool)
 
-	appHandler := httpapi.NewApplicationHandler(appSvc, appRepo, catalogRepo)
-	catalogHandler := httpapi.NewCatalogHandler(catalogRepo)
+	eligibilitySvc := service.NewEligibilityService(eligibilityRepo, catalogRepo)
+	appSvc := service.NewApplicationService(appRepo, catalogRepo, interviewRepo, guardianRepo, formRepo, eligibilitySvc, outboxRepo, txm)
+	invSvc := service.NewInterviewService(appRepo, interviewRepo, outboxRepo, txm, az)
+
+	appHandler := httpapi.NewApplicationHandler(appSvc, appRepo, az)
+	catalogHandler := httpapi.NewCatalogHandler(catalogRepo, appSvc)
+	go service.RunApplicationWindows(ctx, catalogRepo, cfg.ApplicationWindowInterval)
 	programHandler := httpapi.NewProgramHandler(catalogRepo)
-	teacherHandler := httpapi.NewTeacherHandler(catalogRepo, appRepo, invSvc)
+	formHandler := httpapi.NewFormHandler(formRepo, catalogRepo)
+	eligibilityHandler := httpapi.NewEligibilityHandler(eligibilityRepo, catalogRepo, eligibilitySvc)
+	teacherHandler := httpapi.NewTeacherHandler(catalogRepo, appRepo, invSvc, az)
+
+	slotRepo := repo.NewInterviewSlotRepo(pool)
+	slotSvc := service.NewInterviewSlotService(appRepo, slotRepo, interviewRepo, guardianRepo, outboxRepo, txm, az)
+	slotHandler := httpapi.NewInterviewSlotHandler(slotSvc)
+	go service.RunInterviewReminders(ctx, slotSvc, cfg.InterviewReminderInterval, cfg.InterviewReminderBefore)
+
+	messageRepo := repo.NewApplicationMessageRepo(pool)
+	messageSvc := service.NewApplicationMessageService(appRepo, messageRepo, guardianRepo, outboxRepo, txm, az)
+	messageHandler := httpapi.NewApplicationMessageHandler(messageSvc)
+
+	blobStore, err := newAttachmentStorage(cfg)
+	if err != nil {
+		slog.Error("attachment storage", "err", err)
+		os.Exit(1)
+	}
+	var scanner storage.Scanner = storage.NoopScanner{}
+	if cfg.ClamdAddr != "" {
+		scanner = storage.NewClamdScanner(cfg.ClamdAddr, 0)
+	} else {
+		slog.Warn("CLAMD_ADDR is empty: attachments are not scanned for viruses")
+	}
+	attachmentRepo := repo.NewAttachmentRepo(pool)
+	attachmentSvc := service.NewAttachmentService(appRepo, attachmentRepo, guardianRepo, blobStore, scanner, service.AttachmentLimits{
+		MaxSize:           cfg.AttachmentMaxSize,
+		AllowedTypes:      strings.Split(cfg.AttachmentAllowedTypes, ","),
+		MaxPerApplication: 10,
+	}, az, txm)
+	attachmentHandler := httpapi.NewAttachmentHandler(attachmentSvc)
+
+	admissionRepo := repo.NewAdmissionRepo(pool)
+	admissionSvc := service.NewAdmissionService(appRepo, catalogRepo, admissionRepo, outboxRepo, txm, az)
+	admissionHandler := httpapi.NewAdmissionHandler(admissionSvc)
+
+	enrollmentRepo := repo.NewEnrollmentRepo(pool)
+	enrollmentSvc := service.NewEnrollmentService(enrollmentRepo, appRepo, catalogRepo, appSvc, guardianRepo, outboxRepo, txm)
+	enrollmentHandler := httpapi.NewEnrollmentHandler(enrollmentSvc)
+
+	guardianSvc := service.NewGuardianService(guardianRepo, profileRepo, appRepo, txm)
+	guardianHandler := httpapi.NewGuardianHandler(guardianSvc)
 
 	matRepo := repo.NewMaterialRepo(pool)
-	matSvc := service.NewMaterialService(matRepo, appRepo, catalogRepo)
+	matSvc := service.NewMaterialService(matRepo, outboxRepo, txm, az)
 	matHandler := httpapi.NewMaterialHandler(matSvc)
 
 	progressRepo := repo.NewProgressRepo(pool)
 	asgRepo := repo.NewAssignmentRepo(pool)
 	subRepo := repo.NewSubmissionRepo(pool)
 
-	progressSvc := service.NewProgressService(progressRepo, matRepo, appRepo)
-	asgSvc := service.NewAssignmentService(catalogRepo, appRepo, asgRepo)
-	subSvc := service.NewSubmissionService(catalogRepo, appRepo, asgRepo, subRepo)
+	progressSvc := service.NewProgressService(progressRepo, matRepo, az)
+	asgSvc := service.NewAssignmentService(asgRepo, outboxRepo, txm, az)
+	subSvc := service.NewSubmissionService(asgRepo, subRepo, outboxRepo, txm, az)
 
 	progressHandler := httpapi.NewProgressHandler(progressSvc)
 	asgHandler := httpapi.NewAssignmentHandler(asgSvc)
 	subHandler := httpapi.NewSubmissionHandler(subSvc)
 
+	outboxHandler := httpapi.NewOutboxHandler(outboxRepo)
+
+	ruleRepo := repo.NewRuleRepo(pool)
+	ruleHandler := httpapi.NewRuleHandler(ruleRepo)
+	ruleEngine := rules.NewEngine(ruleRepo, map[domain.RuleAction]rules.Action{
+		domain.ActionNotify:               rules.NewNotifyAction(outboxRepo),
+		domain.ActionSetApplicationStatus: rules.NewSetApplicationStatusAction(appSvc),
+		domain.ActionWebhook:              rules.NewWebhookAction(),
+	})
+
+	webhookRepo := repo.NewWebhookRepo(pool)
+	deliverer := webhook.NewDeliverer(webhookRepo, txm, webhook.DelivererConfig{
+		PollInterval: cfg.OutboxPollInterval,
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+		Timeout:      cfg.WebhookTimeout,
+	})
+	webhookHandler := httpapi.NewWebhookHandler(webhookRepo, deliverer)
+	go deliverer.Run(ctx)
+
+	notificationRepo := repo.NewNotificationRepo(pool)
+	notificationHandler := httpapi.NewNotificationHandler(notificationRepo)
+	templates, err := notify.LoadTemplates(cfg.NotifyDefaultLocale)
+	if err != nil {
+		slog.Error("notification templates", "err", err)
+		os.Exit(1)
+	}
+	var sender notify.Sender = notify.LogSender{}
+	if cfg.SMTPHost != "" {
+		sender = notify.NewSMTPSender(notify.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+	} else {
+		slog.Warn("SMTP_HOST is empty: emails are only logged")
+	}
+	notifier := notify.NewNotifier(notificationRepo, guardianRepo, appRepo, catalogRepo, templates, sender)
+	go notify.RunRetention(ctx, notificationRepo, cfg.NotificationsRetention, time.Hour)
+
+	hub := events.NewHub(outboxRepo, cfg.OutboxPollInterval)
+	go hub.Run(ctx)
+	eventsHandler := httpapi.NewEventsHandler(hub, outboxRepo, catalogRepo, appRepo, guardianRepo)
+
+	dispatcher := outbox.NewDispatcher(outboxRepo, outbox.DispatcherConfig{
+		PollInterval: cfg.OutboxPollInterval,
+		BatchSize:    cfg.OutboxBatchSize,
+		MaxAttempts:  cfg.OutboxMaxAttempts,
+	}, outbox.LogSink{}, ruleEngine, webhook.NewFanout(webhookRepo), notifier)
+	go dispatcher.Run(ctx)
+
 	router := httpapi.NewRouter(httpapi.Deps{
-		ApplicationHandler: appHandler,
-		CatalogHandler:     catalogHandler,
-		ProgramHandler:     programHandler,
-		TeacherHandler:     teacherHandler,
-		MaterialHandler:    matHandler,
-		ProgressHandler:    progressHandler,
-		AssignmentHandler:  asgHandler,
-		SubmissionHandler:  subHandler,
+		Auth: auth.Middleware(verifier, cfg.AuthDevHeaders),
+
+		AuthHandler:         authHandler,
+		ProfileHandler:      profileHandler,
+		GuardianHandler:     guardianHandler,
+		ApplicationHandler:  appHandler,
+		CatalogHandler:      catalogHandler,
+		ProgramHandler:      programHandler,
+		TeacherHandler:      teacherHandler,
+		MaterialHandler:     matHandler,
+		ProgressHandler:     progressHandler,
+		AssignmentHandler:   asgHandler,
+		SubmissionHandler:   subHandler,
+		OutboxHandler:       outboxHandler,
+		RuleHandler:         ruleHandler,
+		WebhookHandler:      webhookHandler,
+		NotificationHandler: notificationHandler,
+		EventsHandler:       eventsHandler,
+		EnrollmentHandler:   enrollmentHandler,
+		FormHandler:         formHandler,
+		EligibilityHandler:  eligibilityHandler,
+		MessageHandler:      messageHandler,
+		AttachmentHandler:   attachmentHandler,
+		AdmissionHandler:    admissionHandler,
+		SlotHandler:         slotHandler,
 	})
 
 	addr := ":" + cfg.AppPort
@@ -86,3 +259,21 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newAttachmentStorage: драйвер хранилища вложений по STORAGE_DRIVER
+func newAttachmentStorage(cfg config.Config) (storage.Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		return storage.NewLocal(cfg.StorageLocalDir)
+	case "s3":
+		return storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q (local|s3)", cfg.StorageDriver)
+	}
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -12,32 +13,101 @@ type ctxKey string
 const (
 	ctxUserID ctxKey = "user_id"
 	ctxRole   ctxKey = "role"
+	ctxRoles  ctxKey = "roles"
+
+	ctxLearnerID ctxKey = "learner_id"
 )
 
-// MVP: читаем из заголовков. Потом заменишь на JWT/интроспекцию ядра.
-func Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		uidStr := r.Header.Get("X-User-Id")
-		role := r.Header.Get("X-Role") // user|moderator|admin
+// SystemRole: действия правил автоматизации и фоновых задач. Ставится только в процессе
+// (WithIdentity); из токена и X-Role не принимается.
+const SystemRole = "system"
+
+// StreamPath: SSE-ручка, единственная, где токен принимается из ?access_token=
+const StreamPath = "/events/stream"
+
+// порядок важен: первая найденная роль из токена становится основной (auth.Role)
+var rolePriority = []string{"admin", "moderator", "user"}
+
+// Middleware: Authorization: Bearer <jwt>.
+// Без токена запрос идёт дальше анонимно (публичный каталог).
+// devHeaders=true дополнительно разрешает старый режим X-User-Id/X-Role — только для локальной разработки.
+func Middleware(v *Verifier, devHeaders bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := r.Header.Get("Authorization")
+			// EventSource (SSE) не умеет заголовки — только для GET /events/stream токен берём из query
+			// (в лог access_token не попадает, см. httpapi.redactAccessToken)
+			if h == "" && r.Method == http.MethodGet && r.URL.Path == StreamPath {
+				if t := r.URL.Query().Get("access_token"); t != "" {
+					h = "Bearer " + t
+				}
+			}
+			if h != "" {
+				raw, ok := strings.CutPrefix(h, "Bearer ")
+				if !ok || raw == "" {
+					http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+					return
+				}
+				uid, roles, err := v.Verify(raw)
+				if err != nil {
+					// причину не раскрываем клиенту
+					http.Error(w, "invalid token", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(withIdentity(r.Context(), uid, roles)))
+				return
+			}
+
+			if devHeaders {
+				if uidStr := r.Header.Get("X-User-Id"); uidStr != "" {
+					uid, err := uuid.Parse(uidStr)
+					if err != nil {
+						http.Error(w, "invalid X-User-Id", http.StatusBadRequest)
+						return
+					}
+					role := r.Header.Get("X-Role") // user|moderator|admin
+					if role == "" {
+						role = "user"
+					}
+					if role == SystemRole {
+						http.Error(w, "invalid X-Role", http.StatusBadRequest)
+						return
+					}
+					next.ServeHTTP(w, r.WithContext(withIdentity(r.Context(), uid, []string{role})))
+					return
+				}
+			}
 
-		if uidStr == "" {
 			// публичные ручки разрешим без user_id (каталог)
 			next.ServeHTTP(w, r)
-			return
-		}
-		uid, err := uuid.Parse(uidStr)
-		if err != nil {
-			http.Error(w, "invalid X-User-Id", http.StatusBadRequest)
-			return
-		}
-		if role == "" {
-			role = "user"
-		}
+		})
+	}
+}
+
+// WithIdentity кладёт пользователя в контекст в обход middleware (фоновые задачи, системные действия).
+func WithIdentity(ctx context.Context, uid uuid.UUID, role string) context.Context {
+	return withIdentity(ctx, uid, []string{role})
+}
 
-		ctx := context.WithValue(r.Context(), ctxUserID, uid)
-		ctx = context.WithValue(ctx, ctxRole, role)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+func withIdentity(ctx context.Context, uid uuid.UUID, roles []string) context.Context {
+	ctx = context.WithValue(ctx, ctxUserID, uid)
+	ctx = context.WithValue(ctx, ctxRole, primaryRole(roles))
+	ctx = context.WithValue(ctx, ctxRoles, roles)
+	return ctx
+}
+
+func primaryRole(roles []string) string {
+	for _, p := range rolePriority {
+		for _, r := range roles {
+			if r == p {
+				return r
+			}
+		}
+	}
+	if len(roles) > 0 && roles[0] != "" {
+		return roles[0]
+	}
+	return "user"
 }
 
 func UserID(ctx context.Context) (uuid.UUID, bool) {
@@ -53,3 +123,22 @@ func Role(ctx context.Context) string {
 	}
 	return "anonymous"
 }
+
+// Roles: все роли из токена (Role возвращает только основную)
+func Roles(ctx context.Context) []string {
+	v, _ := ctx.Value(ctxRoles).([]string)
+	return v
+}
+
+// WithLearner: guardian смотрит /learn от имени ребёнка (связь проверяется до вызова)
+func WithLearner(ctx context.Context, childID uuid.UUID) context.Context {
+	return context.WithValue(ctx, ctxLearnerID, childID)
+}
+
+// LearnerID: чьи учебные данные читаем — ребёнка (если задан) или самого пользователя
+func LearnerID(ctx context.Context) (uuid.UUID, bool) {
+	if id, ok := ctx.Value(ctxLearnerID).(uuid.UUID); ok {
+		return id, true
+	}
+	return UserID(ctx)
+}
@@ -0,0 +1,101 @@
+// internal/auth/context_test.go
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testSecret = "test-secret"
+
+func token(t *testing.T, c Claims) string {
+	t.Helper()
+	if c.Subject == "" {
+		c.Subject = uuid.NewString()
+	}
+	if c.ExpiresAt == nil {
+		c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Minute))
+	}
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// serve прогоняет запрос через Middleware; role — роль, которую увидел обработчик ("" — не дошёл)
+func serve(t *testing.T, r *http.Request, devHeaders bool) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+	v, err := NewVerifier(VerifierConfig{Secret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var role string
+	h := Middleware(v, devHeaders)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role = Role(r.Context())
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w, role
+}
+
+func TestMiddlewareBearer(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token(t, Claims{Roles: []string{"user", "moderator"}}))
+	if w, role := serve(t, r, false); w.Code != http.StatusOK || role != "moderator" {
+		t.Errorf("code = %d, role = %q; want 200 and moderator", w.Code, role)
+	}
+}
+
+func TestMiddlewareInvalidTokenIsGeneric(t *testing.T) {
+	expired := token(t, Claims{RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour))}})
+	for _, raw := range []string{expired, "not-a-jwt"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+raw)
+		w, role := serve(t, r, false)
+		if w.Code != http.StatusUnauthorized || role != "" {
+			t.Errorf("code = %d, role = %q; want 401", w.Code, role)
+		}
+		// причина (истёк, подпись, формат) клиенту не отдаётся
+		if body := strings.TrimSpace(w.Body.String()); body != "invalid token" {
+			t.Errorf("body = %q, want \"invalid token\"", body)
+		}
+	}
+}
+
+func TestSystemRoleIsNotAcceptedFromOutside(t *testing.T) {
+	for _, c := range []Claims{{Role: SystemRole}, {Roles: []string{"user", SystemRole}}} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token(t, c))
+		if w, role := serve(t, r, true); w.Code != http.StatusUnauthorized || role != "" {
+			t.Errorf("token %+v: code = %d, role = %q; want 401", c, w.Code, role)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-User-Id", uuid.NewString())
+	r.Header.Set("X-Role", SystemRole)
+	if w, role := serve(t, r, true); w.Code != http.StatusBadRequest || role != "" {
+		t.Errorf("X-Role: system: code = %d, role = %q; want 400", w.Code, role)
+	}
+}
+
+func TestDevHeadersOnlyWhenEnabled(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-User-Id", uuid.NewString())
+	r.Header.Set("X-Role", "admin")
+
+	if _, role := serve(t, r, false); role != "anonymous" {
+		t.Errorf("dev headers off: role = %q, want anonymous", role)
+	}
+	if _, role := serve(t, r, true); role != "admin" {
+		t.Errorf("dev headers on: role = %q, want admin", role)
+	}
+}
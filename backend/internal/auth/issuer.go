@@ -0,0 +1,101 @@
+// internal/auth/issuer.go
+
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var ErrNoSigningKey = errors.New("jwt signing key is not configured")
+
+type IssuerConfig struct {
+	Secret         string // HS256 (тот же, что у Verifier)
+	PrivateKeyFile string // RS256, PEM; публичный ключ отдаётся Verifier через JWT_PUBLIC_KEY_FILE/JWKS
+	KeyID          string
+	Issuer         string
+	Audience       string
+	TTL            time.Duration
+}
+
+// Issuer выпускает access-токены для локальных аккаунтов (/auth/login, /auth/refresh).
+type Issuer struct {
+	secret   []byte
+	rsaKey   *rsa.PrivateKey
+	kid      string
+	issuer   string
+	audience string
+	ttl      time.Duration
+}
+
+func NewIssuer(cfg IssuerConfig) (*Issuer, error) {
+	is := &Issuer{kid: cfg.KeyID, issuer: cfg.Issuer, audience: cfg.Audience, ttl: cfg.TTL}
+	if is.ttl <= 0 {
+		is.ttl = 15 * time.Minute
+	}
+
+	if cfg.Secret != "" {
+		is.secret = []byte(cfg.Secret)
+	}
+	if cfg.PrivateKeyFile != "" {
+		b, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(b)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", cfg.PrivateKeyFile, err)
+		}
+		is.rsaKey = key
+	}
+	return is, nil
+}
+
+func (is *Issuer) Enabled() bool { return is.rsaKey != nil || is.secret != nil }
+
+// Issue: RS256 если задан приватный ключ, иначе HS256
+func (is *Issuer) Issue(uid uuid.UUID, roles []string) (string, time.Time, error) {
+	now := time.Now()
+	exp := now.Add(is.ttl)
+
+	c := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   uid.String(),
+			Issuer:    is.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
+			ID:        uuid.NewString(),
+		},
+		Roles: roles,
+	}
+	if is.audience != "" {
+		c.Audience = jwt.ClaimStrings{is.audience}
+	}
+
+	var (
+		s   string
+		err error
+	)
+	switch {
+	case is.rsaKey != nil:
+		t := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+		if is.kid != "" {
+			t.Header["kid"] = is.kid
+		}
+		s, err = t.SignedString(is.rsaKey)
+	case is.secret != nil:
+		s, err = jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(is.secret)
+	default:
+		return "", time.Time{}, ErrNoSigningKey
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return s, exp, nil
+}
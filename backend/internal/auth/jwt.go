@@ -0,0 +1,189 @@
+// internal/auth/jwt.go
+
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrNoKeys       = errors.New("no jwt verification keys configured")
+)
+
+// Claims: sub = user id, роли либо массивом "roles", либо строкой "role".
+type Claims struct {
+	jwt.RegisteredClaims
+	Role  string   `json:"role,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+type VerifierConfig struct {
+	Secret        string // HS256
+	PublicKeyFile string // RS256, PEM
+	JWKSFile      string // RS256, {"keys":[...]}
+	Issuer        string
+	Audience      string
+}
+
+type Verifier struct {
+	secret   []byte
+	rsaKey   *rsa.PublicKey
+	jwks     map[string]*rsa.PublicKey
+	issuer   string
+	audience string
+}
+
+func NewVerifier(cfg VerifierConfig) (*Verifier, error) {
+	v := &Verifier{issuer: cfg.Issuer, audience: cfg.Audience}
+
+	if cfg.Secret != "" {
+		v.secret = []byte(cfg.Secret)
+	}
+	if cfg.PublicKeyFile != "" {
+		b, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(b)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", cfg.PublicKeyFile, err)
+		}
+		v.rsaKey = key
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.jwks = keys
+	}
+	return v, nil
+}
+
+// HasKeys: false -> ни один токен не пройдёт проверку
+func (v *Verifier) HasKeys() bool {
+	return v.secret != nil || v.rsaKey != nil || len(v.jwks) > 0
+}
+
+func (v *Verifier) Verify(raw string) (uuid.UUID, []string, error) {
+	if !v.HasKeys() {
+		return uuid.Nil, nil, ErrNoKeys
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "RS256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if v.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		opts = append(opts, jwt.WithAudience(v.audience))
+	}
+
+	var c Claims
+	if _, err := jwt.ParseWithClaims(raw, &c, v.keyFor, opts...); err != nil {
+		return uuid.Nil, nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	uid, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("%w: sub is not uuid", ErrInvalidToken)
+	}
+
+	roles := c.Roles
+	if len(roles) == 0 && c.Role != "" {
+		roles = []string{c.Role}
+	}
+	for _, r := range roles {
+		if r == SystemRole {
+			return uuid.Nil, nil, fmt.Errorf("%w: role %q is reserved", ErrInvalidToken, r)
+		}
+	}
+	return uid, roles, nil
+}
+
+func (v *Verifier) keyFor(t *jwt.Token) (any, error) {
+	switch t.Method.Alg() {
+	case "HS256":
+		if v.secret == nil {
+			return nil, errors.New("HS256 is not configured")
+		}
+		return v.secret, nil
+	case "RS256":
+		if kid, _ := t.Header["kid"].(string); kid != "" && v.jwks != nil {
+			if k, ok := v.jwks[kid]; ok {
+				return k, nil
+			}
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		if v.rsaKey != nil {
+			return v.rsaKey, nil
+		}
+		// без kid: если в JWKS ровно один ключ — берём его
+		if len(v.jwks) == 1 {
+			for _, k := range v.jwks {
+				return k, nil
+			}
+		}
+		return nil, errors.New("RS256 key not found")
+	default:
+		return nil, fmt.Errorf("unexpected alg %s", t.Method.Alg())
+	}
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks %s: %w", path, err)
+	}
+
+	res := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %q: bad n: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %q: bad e: %w", k.Kid, err)
+		}
+		res[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("jwks %s: no RSA signing keys", path)
+	}
+	return res, nil
+}
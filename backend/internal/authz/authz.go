@@ -0,0 +1,124 @@
+// internal/authz/authz.go
+
+package authz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+// тексты совпадают с прежними "unauthorized"/"forbidden", на них завязаны хендлеры и фронт
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+// Can: только глобальные права ролей пользователя
+func Can(ctx context.Context, p Permission) bool {
+	roles := auth.Roles(ctx)
+	if len(roles) == 0 {
+		roles = []string{auth.Role(ctx)}
+	}
+	for _, r := range roles {
+		if contains(rolePermissions[r], p) {
+			return true
+		}
+	}
+	return false
+}
+
+func Require(ctx context.Context, p Permission) error {
+	if _, ok := auth.UserID(ctx); !ok {
+		return ErrUnauthorized
+	}
+	if !Can(ctx, p) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// RequirePermission — chi middleware для ручек с глобальным правом
+func RequirePermission(p Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := Require(r.Context(), p); err != nil {
+				WriteError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func WriteError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrUnauthorized) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusForbidden)
+}
+
+// Authorizer: проверки в пределах ресурса (группа / программа)
+type Authorizer struct {
+	catalog *repo.CatalogRepo
+	apps    *repo.ApplicationRepo
+}
+
+func New(catalog *repo.CatalogRepo, apps *repo.ApplicationRepo) *Authorizer {
+	return &Authorizer{catalog: catalog, apps: apps}
+}
+
+func (a *Authorizer) CanInGroup(ctx context.Context, p Permission, groupID uuid.UUID) (bool, error) {
+	uid, ok := auth.UserID(ctx)
+	if !ok {
+		return false, nil
+	}
+	if Can(ctx, p) {
+		return true, nil
+	}
+	if contains(groupTeacherPermissions, p) {
+		assigned, err := a.catalog.IsTeacherInGroup(ctx, groupID, uid)
+		if err != nil || assigned {
+			return assigned, err
+		}
+	}
+	if contains(learnerPermissions, p) {
+		learnerID, _ := auth.LearnerID(ctx)
+		return a.apps.HasEnrollment(ctx, learnerID, groupID)
+	}
+	return false, nil
+}
+
+func (a *Authorizer) RequireInGroup(ctx context.Context, p Permission, groupID uuid.UUID) error {
+	if _, ok := auth.UserID(ctx); !ok {
+		return ErrUnauthorized
+	}
+	ok, err := a.CanInGroup(ctx, p, groupID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (a *Authorizer) CanInProgram(ctx context.Context, p Permission, programID uuid.UUID) (bool, error) {
+	uid, ok := auth.UserID(ctx)
+	if !ok {
+		return false, nil
+	}
+	if Can(ctx, p) {
+		return true, nil
+	}
+	if contains(programTeacherPermissions, p) {
+		return a.catalog.IsTeacherInProgram(ctx, uid, programID)
+	}
+	return false, nil
+}
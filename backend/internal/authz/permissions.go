@@ -0,0 +1,96 @@
+// internal/authz/permissions.go
+
+package authz
+
+// Permission — именованное действие. Роль даёт права глобально,
+// назначение преподавателем / зачисление дают их только в пределах группы или программы.
+type Permission string
+
+const (
+	// catalog
+	CatalogManage  Permission = "catalog.manage"   // программы, потоки, группы, преподаватели, публикация
+	CatalogReadAll Permission = "catalog.read_all" // черновики, закрытые группы, список преподавателей
+
+	// applications
+	ApplicationList   Permission = "application.list"
+	ApplicationReview Permission = "application.review" // смена статуса заявки
+	EnrollmentManage  Permission = "enrollment.manage"  // отчисление, завершение курса, просмотр зачислений группы
+
+	// guardians
+	GuardianManage Permission = "guardian.manage" // связывать существующие аккаунты
+
+	// ops
+	OutboxManage  Permission = "outbox.manage"  // dead letters: просмотр и повторная отправка
+	RuleManage    Permission = "rule.manage"    // правила автоматизации /admin/rules
+	WebhookManage Permission = "webhook.manage" // исходящие вебхуки /admin/webhooks
+
+	// group work (staff глобально, преподаватель — в своей группе)
+	InterviewRecord   Permission = "interview.record"
+	MaterialCreate    Permission = "material.create"
+	AssignmentCreate  Permission = "assignment.create"
+	SubmissionList    Permission = "submission.list"
+	SubmissionReview  Permission = "submission.review"
+	GroupStudentsRead Permission = "group.students.read"
+
+	// learning (только зачисленный ученик / guardian через auth.LearnerID)
+	LearnAccess Permission = "learn.access"
+)
+
+var staffPermissions = []Permission{
+	CatalogReadAll,
+	ApplicationList,
+	ApplicationReview,
+	EnrollmentManage,
+	InterviewRecord,
+	SubmissionList,
+	GroupStudentsRead,
+}
+
+// rolePermissions: глобальные права роли
+var rolePermissions = map[string][]Permission{
+	"admin": append([]Permission{
+		CatalogManage,
+		GuardianManage,
+		OutboxManage,
+		RuleManage,
+		WebhookManage,
+		MaterialCreate,
+		AssignmentCreate,
+		SubmissionReview,
+	}, staffPermissions...),
+	"moderator": staffPermissions,
+	"user":      nil,
+	// system: действия правил автоматизации, только в процессе (auth.SystemRole не принимается из токена и X-Role)
+	"system": {ApplicationReview},
+}
+
+// groupTeacherPermissions: права назначенного преподавателя внутри его группы
+var groupTeacherPermissions = []Permission{
+	ApplicationList,
+	InterviewRecord,
+	MaterialCreate,
+	AssignmentCreate,
+	SubmissionList,
+	SubmissionReview,
+	GroupStudentsRead,
+}
+
+// programTeacherPermissions: преподаватель хотя бы одной группы программы
+var programTeacherPermissions = []Permission{
+	CatalogReadAll,
+	ApplicationList,
+}
+
+// learnerPermissions: ученик с зачислением в группу
+var learnerPermissions = []Permission{
+	LearnAccess,
+}
+
+func contains(ps []Permission, p Permission) bool {
+	for _, x := range ps {
+		if x == p {
+			return true
+		}
+	}
+	return false
+}
@@ -2,17 +2,122 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
 	AppPort     string
 	DatabaseURL string
+
+	// JWT: достаточно одного из источников ключей (HS256 секрет, RS256 PEM или JWKS файл)
+	JWTSecret        string
+	JWTPublicKeyFile string
+	JWTJWKSFile      string
+	JWTIssuer        string
+	JWTAudience      string
+
+	// выпуск токенов для локальных аккаунтов (/auth/*)
+	JWTPrivateKeyFile string
+	JWTKeyID          string
+	AccessTokenTTL    time.Duration
+	RefreshTokenTTL   time.Duration
+
+	// dev-режим: доверять X-User-Id/X-Role (только для локальной разработки!)
+	AuthDevHeaders bool
+
+	// outbox dispatcher
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+	OutboxMaxAttempts  int
+
+	// исходящие вебхуки
+	WebhookMaxAttempts int
+	WebhookTimeout     time.Duration
+
+	// email-уведомления; SMTP_HOST пуст — письма только пишутся в лог
+	SMTPHost            string
+	SMTPPort            string
+	SMTPUsername        string
+	SMTPPassword        string
+	SMTPFrom            string
+	NotifyDefaultLocale string
+
+	// входящие старше — удаляются
+	NotificationsRetention time.Duration
+
+	// как часто проверять окна приёма заявок (opens_at/closes_at)
+	ApplicationWindowInterval time.Duration
+
+	// напоминания о собеседованиях: как часто проверять и за сколько до начала напоминать
+	InterviewReminderInterval time.Duration
+	InterviewReminderBefore   time.Duration
+
+	// вложения заявок: драйвер хранилища local|s3
+	StorageDriver   string
+	StorageLocalDir string
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+
+	AttachmentMaxSize      int64
+	AttachmentAllowedTypes string // через запятую
+	// адрес clamd (host:3310); пусто — проверка на вирусы отключена
+	ClamdAddr string
 }
 
 func Load() Config {
 	return Config{
 		AppPort:     getenv("APP_PORT", "8080"),
 		DatabaseURL: getenv("DATABASE_URL", ""),
+
+		JWTSecret:        getenv("JWT_SECRET", ""),
+		JWTPublicKeyFile: getenv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTJWKSFile:      getenv("JWT_JWKS_FILE", ""),
+		JWTIssuer:        getenv("JWT_ISSUER", ""),
+		JWTAudience:      getenv("JWT_AUDIENCE", ""),
+
+		JWTPrivateKeyFile: getenv("JWT_PRIVATE_KEY_FILE", ""),
+		JWTKeyID:          getenv("JWT_KEY_ID", ""),
+		AccessTokenTTL:    getenvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:   getenvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		AuthDevHeaders: getenvBool("AUTH_DEV_HEADERS", false),
+
+		OutboxPollInterval: getenvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:    getenvInt("OUTBOX_BATCH_SIZE", 50),
+		OutboxMaxAttempts:  getenvInt("OUTBOX_MAX_ATTEMPTS", 10),
+
+		WebhookMaxAttempts: getenvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookTimeout:     getenvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+
+		SMTPHost:            getenv("SMTP_HOST", ""),
+		SMTPPort:            getenv("SMTP_PORT", "587"),
+		SMTPUsername:        getenv("SMTP_USERNAME", ""),
+		SMTPPassword:        getenv("SMTP_PASSWORD", ""),
+		SMTPFrom:            getenv("SMTP_FROM", "noreply@itcube.local"),
+		NotifyDefaultLocale: getenv("NOTIFY_DEFAULT_LOCALE", "ru"),
+
+		NotificationsRetention: getenvDuration("NOTIFICATIONS_RETENTION", 90*24*time.Hour),
+
+		ApplicationWindowInterval: getenvDuration("APPLICATION_WINDOW_INTERVAL", time.Minute),
+
+		InterviewReminderInterval: getenvDuration("INTERVIEW_REMINDER_INTERVAL", time.Minute),
+		InterviewReminderBefore:   getenvDuration("INTERVIEW_REMINDER_BEFORE", 24*time.Hour),
+
+		StorageDriver:   getenv("STORAGE_DRIVER", "local"),
+		StorageLocalDir: getenv("STORAGE_LOCAL_DIR", "./data/attachments"),
+		S3Endpoint:      getenv("S3_ENDPOINT", ""),
+		S3Region:        getenv("S3_REGION", "us-east-1"),
+		S3Bucket:        getenv("S3_BUCKET", ""),
+		S3AccessKey:     getenv("S3_ACCESS_KEY", ""),
+		S3SecretKey:     getenv("S3_SECRET_KEY", ""),
+
+		AttachmentMaxSize:      int64(getenvInt("ATTACHMENT_MAX_SIZE", 10<<20)),
+		AttachmentAllowedTypes: getenv("ATTACHMENT_ALLOWED_TYPES", "application/pdf,image/jpeg,image/png"),
+		ClamdAddr:              getenv("CLAMD_ADDR", ""),
 	}
 }
 
@@ -22,3 +127,39 @@ func getenv(k, def string) string {
 	}
 	return def
 }
+
+func getenvInt(k string, def int) int {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func getenvBool(k string, def bool) bool {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+func getenvDuration(k string, def time.Duration) time.Duration {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Querier — общее у *pgxpool.Pool и pgx.Tx; репозитории работают через него.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// Conn: транзакция из контекста (если мы внутри TxManager.Do), иначе пул.
+func Conn(ctx context.Context, pool *pgxpool.Pool) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+// TxManager — unit of work: всё, что репозитории делают с ctx внутри Do, идёт одной транзакцией.
+type TxManager struct{ pool *pgxpool.Pool }
+
+func NewTxManager(pool *pgxpool.Pool) *TxManager { return &TxManager{pool: pool} }
+
+// Do: commit если fn вернула nil, иначе rollback.
+// Вложенный Do переиспользует внешнюю транзакцию.
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // после Commit — no-op
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
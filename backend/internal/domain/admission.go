@@ -0,0 +1,192 @@
+// internal/domain/admission.go
+
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdmissionMode string
+
+const (
+	AdmissionFirstCome   AdmissionMode = "first_come"  // одобряет модератор по одной заявке (по умолчанию)
+	AdmissionCompetitive AdmissionMode = "competitive" // конкурс: рейтинг по баллам и зачисление одной операцией
+)
+
+func (m AdmissionMode) Valid() bool {
+	return m == AdmissionFirstCome || m == AdmissionCompetitive
+}
+
+// ScoringRules — как считаются баллы заявки в конкурсе (к ним прибавляются ручные баллы staff)
+type ScoringRules struct {
+	Interview map[InterviewResult]float64 `json:",omitempty"` // баллы за результат собеседования
+	Answers   []AnswerScore               `json:",omitempty"`
+}
+
+// AnswerScore — баллы за ответ анкеты с ключом Key:
+// Values — за выбранный вариант (single_choice/multi_choice — сумма, text — точное совпадение),
+// Weight — множитель числового ответа (number).
+type AnswerScore struct {
+	Key    string
+	Values map[string]float64 `json:",omitempty"`
+	Weight float64            `json:",omitempty"`
+}
+
+// GroupAdmission — режим приёма группы
+type GroupAdmission struct {
+	GroupID     uuid.UUID
+	Mode        AdmissionMode
+	Scoring     ScoringRules
+	FinalizedAt *time.Time // конкурс подведён (повторно не подводится)
+}
+
+// ApplicationScore — ручные баллы заявки
+type ApplicationScore struct {
+	ApplicationID uuid.UUID
+	ManualPoints  float64
+	Comment       string
+	UpdatedBy     uuid.UUID
+	UpdatedAt     time.Time
+}
+
+// причины, по которым заявку нельзя одобрить по итогам конкурса
+const (
+	BlockerInterviewRequired = "interview_required" // собеседования нет или результат не окончательный
+	BlockerInterviewFailed   = "interview_failed"
+)
+
+// RankedApplication — строка рейтинга
+type RankedApplication struct {
+	Rank            int // с 1; 0 — не участвует (Blocker)
+	ApplicationID   uuid.UUID
+	UserID          uuid.UUID
+	Status          ApplicationStatus
+	CreatedAt       time.Time
+	ApplicantName   *string
+	InterviewResult *InterviewResult
+	Answers         json.RawMessage
+
+	InterviewPoints float64
+	AnswerPoints    float64
+	ManualPoints    float64
+	Score           float64
+
+	Blocker string `json:",omitempty"`
+}
+
+var ErrInvalidScoring = errors.New("invalid scoring rules")
+
+const maxScoringAnswers = 100
+
+func (r ScoringRules) Validate() error {
+	for res, p := range r.Interview {
+		switch res {
+		case InterviewRecommended, InterviewNotRecommended, InterviewNeedsMore, InterviewPending:
+		default:
+			return fmt.Errorf("%w: unknown interview result %q", ErrInvalidScoring, res)
+		}
+		if !finite(p) {
+			return fmt.Errorf("%w: interview points must be finite", ErrInvalidScoring)
+		}
+	}
+	if len(r.Answers) > maxScoringAnswers {
+		return fmt.Errorf("%w: too many answers", ErrInvalidScoring)
+	}
+	seen := map[string]bool{}
+	for _, a := range r.Answers {
+		if !questionKeyRe.MatchString(a.Key) {
+			return fmt.Errorf("%w: bad key %q", ErrInvalidScoring, a.Key)
+		}
+		if seen[a.Key] {
+			return fmt.Errorf("%w: duplicate key %q", ErrInvalidScoring, a.Key)
+		}
+		seen[a.Key] = true
+		if len(a.Values) == 0 && a.Weight == 0 {
+			return fmt.Errorf("%w: %s: values or weight is required", ErrInvalidScoring, a.Key)
+		}
+		if !finite(a.Weight) {
+			return fmt.Errorf("%w: %s: weight must be finite", ErrInvalidScoring, a.Key)
+		}
+		for _, p := range a.Values {
+			if !finite(p) {
+				return fmt.Errorf("%w: %s: points must be finite", ErrInvalidScoring, a.Key)
+			}
+		}
+	}
+	return nil
+}
+
+func finite(f float64) bool { return !math.IsNaN(f) && !math.IsInf(f, 0) }
+
+// Score: баллы за собеседование и анкету, итог с ручными баллами
+func (r ScoringRules) Score(a *RankedApplication) {
+	a.InterviewPoints, a.AnswerPoints = 0, 0
+	if a.InterviewResult != nil {
+		a.InterviewPoints = r.Interview[*a.InterviewResult]
+	}
+
+	var answers map[string]any
+	_ = json.Unmarshal(a.Answers, &answers)
+	for _, rule := range r.Answers {
+		switch v := answers[rule.Key].(type) {
+		case string:
+			a.AnswerPoints += rule.Values[v]
+		case []any:
+			for _, x := range v {
+				if s, ok := x.(string); ok {
+					a.AnswerPoints += rule.Values[s]
+				}
+			}
+		case float64:
+			a.AnswerPoints += rule.Weight * v
+		}
+	}
+	a.Score = a.InterviewPoints + a.AnswerPoints + a.ManualPoints
+}
+
+// RankApplications: по убыванию баллов, при равенстве — кто раньше подал.
+// Заявки с Blocker идут в конце без места в рейтинге.
+func RankApplications(items []RankedApplication) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if (a.Blocker == "") != (b.Blocker == "") {
+			return a.Blocker == ""
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ApplicationID.String() < b.ApplicationID.String()
+	})
+	rank := 0
+	for i := range items {
+		items[i].Rank = 0
+		if items[i].Blocker == "" {
+			rank++
+			items[i].Rank = rank
+		}
+	}
+}
+
+// InterviewBlocker: можно ли одобрить заявку с таким собеседованием
+func InterviewBlocker(required bool, result *InterviewResult) string {
+	if !required {
+		return ""
+	}
+	switch {
+	case result == nil, *result == InterviewPending, *result == InterviewNeedsMore:
+		return BlockerInterviewRequired
+	case *result != InterviewRecommended:
+		return BlockerInterviewFailed
+	}
+	return ""
+}
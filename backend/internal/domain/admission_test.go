@@ -0,0 +1,111 @@
+// internal/domain/admission_test.go
+
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestScoringRulesValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rules ScoringRules
+		ok    bool
+	}{
+		"empty": {ScoringRules{}, true},
+		"full": {ScoringRules{
+			Interview: map[InterviewResult]float64{InterviewRecommended: 10},
+			Answers: []AnswerScore{
+				{Key: "olympiad", Values: map[string]float64{"yes": 5}},
+				{Key: "grade", Weight: 0.5},
+			},
+		}, true},
+		"unknown interview result": {ScoringRules{Interview: map[InterviewResult]float64{"great": 1}}, false},
+		"nan interview points":     {ScoringRules{Interview: map[InterviewResult]float64{InterviewRecommended: math.NaN()}}, false},
+		"bad key":                  {ScoringRules{Answers: []AnswerScore{{Key: "Bad Key", Weight: 1}}}, false},
+		"duplicate key":            {ScoringRules{Answers: []AnswerScore{{Key: "a", Weight: 1}, {Key: "a", Weight: 2}}}, false},
+		"no values or weight":      {ScoringRules{Answers: []AnswerScore{{Key: "a"}}}, false},
+		"inf weight":               {ScoringRules{Answers: []AnswerScore{{Key: "a", Weight: math.Inf(1)}}}, false},
+	} {
+		err := tc.rules.Validate()
+		if tc.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if !tc.ok && !errors.Is(err, ErrInvalidScoring) {
+			t.Errorf("%s: got %v, want ErrInvalidScoring", name, err)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	rec := InterviewRecommended
+	rules := ScoringRules{
+		Interview: map[InterviewResult]float64{InterviewRecommended: 10},
+		Answers: []AnswerScore{
+			{Key: "langs", Values: map[string]float64{"go": 3, "python": 2}},
+			{Key: "olympiad", Values: map[string]float64{"yes": 5}},
+			{Key: "grade", Weight: 0.5},
+		},
+	}
+	a := RankedApplication{
+		InterviewResult: &rec,
+		Answers:         json.RawMessage(`{"langs":["go","python","c"],"olympiad":"no","grade":8}`),
+		ManualPoints:    1.5,
+	}
+	rules.Score(&a)
+	if a.InterviewPoints != 10 || a.AnswerPoints != 9 || a.Score != 20.5 {
+		t.Fatalf("got interview=%v answers=%v score=%v", a.InterviewPoints, a.AnswerPoints, a.Score)
+	}
+}
+
+func TestRankApplications(t *testing.T) {
+	t0 := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	blocked := RankedApplication{ApplicationID: uuid.New(), Score: 100, CreatedAt: t0, Blocker: BlockerInterviewFailed}
+	top := RankedApplication{ApplicationID: uuid.New(), Score: 50, CreatedAt: t0.Add(time.Hour)}
+	earlier := RankedApplication{ApplicationID: uuid.New(), Score: 30, CreatedAt: t0}
+	later := RankedApplication{ApplicationID: uuid.New(), Score: 30, CreatedAt: t0.Add(time.Minute)}
+
+	items := []RankedApplication{later, blocked, earlier, top}
+	RankApplications(items)
+
+	want := []struct {
+		id   uuid.UUID
+		rank int
+	}{
+		{top.ApplicationID, 1},
+		{earlier.ApplicationID, 2}, // при равных баллах — кто раньше подал
+		{later.ApplicationID, 3},
+		{blocked.ApplicationID, 0}, // не участвует, несмотря на баллы
+	}
+	for i, w := range want {
+		if items[i].ApplicationID != w.id || items[i].Rank != w.rank {
+			t.Errorf("#%d: got %s rank %d, want %s rank %d", i, items[i].ApplicationID, items[i].Rank, w.id, w.rank)
+		}
+	}
+}
+
+func TestInterviewBlocker(t *testing.T) {
+	res := func(r InterviewResult) *InterviewResult { return &r }
+	for _, tc := range []struct {
+		required bool
+		result   *InterviewResult
+		want     string
+	}{
+		{false, nil, ""},
+		{false, res(InterviewNotRecommended), ""},
+		{true, nil, BlockerInterviewRequired},
+		{true, res(InterviewPending), BlockerInterviewRequired},
+		{true, res(InterviewNeedsMore), BlockerInterviewRequired},
+		{true, res(InterviewNotRecommended), BlockerInterviewFailed},
+		{true, res(InterviewRecommended), ""},
+	} {
+		if got := InterviewBlocker(tc.required, tc.result); got != tc.want {
+			t.Errorf("InterviewBlocker(%v, %v) = %q, want %q", tc.required, tc.result, got, tc.want)
+		}
+	}
+}
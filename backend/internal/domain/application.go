@@ -3,6 +3,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -12,11 +13,12 @@ import (
 type ApplicationStatus string
 
 const (
-	AppSubmitted ApplicationStatus = "submitted" // Отправлена
-	AppInReview  ApplicationStatus = "in_review" // На рассмотрении
-	AppApproved  ApplicationStatus = "approved"  // Одобрена (финал)
-	AppRejected  ApplicationStatus = "rejected"  // Отклонена (финал)
-	AppCancelled ApplicationStatus = "cancelled" // Отменена (финал)
+	AppSubmitted  ApplicationStatus = "submitted"  // Отправлена
+	AppInReview   ApplicationStatus = "in_review"  // На рассмотрении
+	AppWaitlisted ApplicationStatus = "waitlisted" // В листе ожидания (мест нет)
+	AppApproved   ApplicationStatus = "approved"   // Одобрена (финал)
+	AppRejected   ApplicationStatus = "rejected"   // Отклонена (финал)
+	AppCancelled  ApplicationStatus = "cancelled"  // Отменена (финал)
 )
 
 func (s ApplicationStatus) IsFinal() bool {
@@ -31,6 +33,30 @@ type EnrollmentApplication struct {
 	Comment   string // комментарий пользователя (опционально)
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	SubmittedBy uuid.UUID // кто подал: сам пользователь или guardian
+
+	Answers json.RawMessage // ответы на анкету программы/группы ({"key": value})
+
+	LastReason string // причина последней смены статуса (заполняется в списке заявок пользователя)
+}
+
+// ApplicationAudit — запись application_status_audit (From пуст у подачи заявки)
+type ApplicationAudit struct {
+	ID          uuid.UUID
+	ActorUserID *uuid.UUID // nil — скрыт от заявителя (действие staff)
+	ActorRole   string
+	From        ApplicationStatus
+	To          ApplicationStatus
+	Reason      string
+	CreatedAt   time.Time
+}
+
+// ApplicationHistory — заявка, её переходы по времени и собеседование (если было)
+type ApplicationHistory struct {
+	Application EnrollmentApplication
+	Timeline    []ApplicationAudit
+	Interview   *Interview
 }
 
 var (
@@ -44,19 +70,24 @@ func CanTransition(from, to ApplicationStatus, actorRole string) error {
 	}
 
 	// MVP правила:
-	// Пользователь: submitted -> cancelled (и только свою)
+	// Пользователь: submitted/waitlisted -> cancelled (и только свою)
 	// Модератор/Админ: submitted -> in_review -> approved/rejected
+	// мест нет: in_review -> waitlisted -> approved (авто, когда место освободится) / rejected
+	// system (правила автоматизации) — как модератор
 	switch actorRole {
 	case "user":
-		if from == AppSubmitted && to == AppCancelled {
+		if (from == AppSubmitted || from == AppWaitlisted) && to == AppCancelled {
 			return nil
 		}
 		return ErrInvalidTransition
-	case "moderator", "admin":
+	case "moderator", "admin", "system":
 		if from == AppSubmitted && to == AppInReview {
 			return nil
 		}
-		if from == AppInReview && (to == AppApproved || to == AppRejected) {
+		if from == AppInReview && (to == AppApproved || to == AppRejected || to == AppWaitlisted) {
+			return nil
+		}
+		if from == AppWaitlisted && (to == AppApproved || to == AppRejected) {
 			return nil
 		}
 		return ErrInvalidTransition
@@ -0,0 +1,24 @@
+// internal/domain/application_attachment.go
+
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApplicationAttachment — документ, приложенный к заявке.
+// Сам файл лежит в хранилище по StorageKey, наружу ключ не отдаётся.
+type ApplicationAttachment struct {
+	ID            uuid.UUID
+	ApplicationID uuid.UUID
+	UploadedBy    uuid.UUID
+	FileName      string
+	ContentType   string
+	SizeBytes     int64
+	StorageKey    string `json:"-"` // applications/{application_id}/{id}
+	SHA256        string
+	ScanStatus    string // clean|skipped (антивирус не настроен)
+	CreatedAt     time.Time
+}
@@ -0,0 +1,22 @@
+// internal/domain/application_message.go
+
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApplicationMessage — сообщение в переписке по заявке.
+// Internal — заметка staff/преподавателей, заявитель и guardian её не видят.
+type ApplicationMessage struct {
+	ID            uuid.UUID
+	ApplicationID uuid.UUID
+	AuthorUserID  uuid.UUID
+	AuthorRole    string // user|guardian|moderator|admin|teacher
+	FromApplicant bool   // написал заявитель или его guardian
+	Body          string
+	Internal      bool
+	CreatedAt     time.Time
+}
@@ -0,0 +1,46 @@
+// internal/domain/application_test.go
+
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	for _, tc := range []struct {
+		from, to ApplicationStatus
+		role     string
+		want     error
+	}{
+		{AppSubmitted, AppCancelled, "user", nil},
+		{AppWaitlisted, AppCancelled, "user", nil},
+		{AppInReview, AppCancelled, "user", ErrInvalidTransition},
+		{AppSubmitted, AppApproved, "user", ErrInvalidTransition},
+
+		{AppSubmitted, AppInReview, "moderator", nil},
+		{AppInReview, AppApproved, "moderator", nil},
+		{AppInReview, AppRejected, "admin", nil},
+		{AppSubmitted, AppApproved, "admin", ErrInvalidTransition},
+
+		// лист ожидания: только из in_review, выход — одобрение (в т.ч. system) или отказ
+		{AppInReview, AppWaitlisted, "moderator", nil},
+		{AppSubmitted, AppWaitlisted, "moderator", ErrInvalidTransition},
+		{AppWaitlisted, AppApproved, "system", nil},
+		{AppWaitlisted, AppRejected, "admin", nil},
+		{AppWaitlisted, AppInReview, "admin", ErrInvalidTransition},
+		{AppWaitlisted, AppWaitlisted, "admin", ErrInvalidTransition},
+
+		{AppApproved, AppCancelled, "user", ErrFinalStatus},
+		{AppRejected, AppInReview, "admin", ErrFinalStatus},
+		{AppCancelled, AppApproved, "system", ErrFinalStatus},
+	} {
+		if err := CanTransition(tc.from, tc.to, tc.role); !errors.Is(err, tc.want) {
+			t.Errorf("%s: %s -> %s = %v, want %v", tc.role, tc.from, tc.to, err, tc.want)
+		}
+	}
+
+	if err := CanTransition(AppSubmitted, AppInReview, "teacher"); err == nil {
+		t.Error("unknown role passed")
+	}
+}
@@ -32,6 +32,58 @@ type Group struct {
 	IsOpen            bool
 	RequiresInterview bool
 	CreatedAt         time.Time
+
+	// окно приёма заявок: OwnOpensAt/OwnClosesAt — заданные у группы,
+	// OpensAt/ClosesAt — действующие (группы, иначе потока)
+	OwnOpensAt  *time.Time
+	OwnClosesAt *time.Time
+	OpensAt     *time.Time
+	ClosesAt    *time.Time
+
+	ApplyStatus ApplyStatus // для каталога: open | upcoming | closed
+	OpensInDays *int        // только для upcoming
+
+	AdmissionMode        AdmissionMode // first_come | competitive
+	AdmissionFinalizedAt *time.Time    // конкурс подведён
+}
+
+type ApplyStatus string
+
+const (
+	ApplyOpen     ApplyStatus = "open"
+	ApplyUpcoming ApplyStatus = "upcoming"
+	ApplyClosed   ApplyStatus = "closed"
+)
+
+// WindowState: состояние окна приёма на момент now ("" — окна нет)
+func WindowState(opensAt, closesAt *time.Time, now time.Time) ApplyStatus {
+	switch {
+	case opensAt == nil && closesAt == nil:
+		return ""
+	case opensAt != nil && now.Before(*opensAt):
+		return ApplyUpcoming
+	case closesAt != nil && !now.Before(*closesAt):
+		return ApplyClosed
+	}
+	return ApplyOpen
+}
+
+// SetApplyStatus: заполняет ApplyStatus/OpensInDays (дни округляются вверх: через 3 часа — "через 1 день")
+func (g *Group) SetApplyStatus(now time.Time) {
+	g.OpensInDays = nil
+	switch WindowState(g.OpensAt, g.ClosesAt, now) {
+	case ApplyUpcoming:
+		days := int((g.OpensAt.Sub(now) + 24*time.Hour - 1) / (24 * time.Hour))
+		g.ApplyStatus, g.OpensInDays = ApplyUpcoming, &days
+	case ApplyClosed:
+		g.ApplyStatus = ApplyClosed
+	default:
+		if g.IsOpen {
+			g.ApplyStatus = ApplyOpen
+		} else {
+			g.ApplyStatus = ApplyClosed
+		}
+	}
 }
 
 type Cohort struct {
@@ -39,4 +91,8 @@ type Cohort struct {
 	ProgramID uuid.UUID
 	Year      int
 	CreatedAt time.Time
+
+	// окно приёма по умолчанию для групп потока
+	OpensAt  *time.Time
+	ClosesAt *time.Time
 }
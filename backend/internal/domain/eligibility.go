@@ -0,0 +1,159 @@
+// internal/domain/eligibility.go
+
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EligibilityRule — условия допуска к программе или группе (правило группы важнее правила программы).
+// Пустые поля не проверяются.
+type EligibilityRule struct {
+	ID        uuid.UUID
+	ProgramID *uuid.UUID
+	GroupID   *uuid.UUID
+
+	MinAge               *int        // полных лет на дату подачи
+	MaxAge               *int        // включительно
+	RequiredProgramIDs   []uuid.UUID // программы, которые нужно завершить (enrollment completed)
+	MaxActiveEnrollments *int        // сколько активных зачислений может быть у ученика одновременно
+
+	UpdatedBy uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// коды причин недопуска (для фронта)
+const (
+	ReasonBirthDateMissing  = "birth_date_missing"
+	ReasonTooYoung          = "too_young"
+	ReasonTooOld            = "too_old"
+	ReasonPrerequisite      = "prerequisite_missing"
+	ReasonTooManyEnrollment = "too_many_enrollments"
+)
+
+type IneligibilityReason struct {
+	Code      string
+	Message   string
+	Limit     *int       `json:",omitempty"` // возраст / число зачислений из правила
+	ProgramID *uuid.UUID `json:",omitempty"` // незавершённая программа
+}
+
+// EligibilityFacts — данные об ученике, по которым проверяется правило
+type EligibilityFacts struct {
+	BirthDate           *time.Time
+	CompletedProgramIDs []uuid.UUID
+	ActiveEnrollments   int
+}
+
+var (
+	ErrNotEligible = errors.New("not eligible")
+	ErrInvalidRule = errors.New("invalid eligibility rule")
+)
+
+const (
+	maxRulePrograms    = 20
+	maxReasonableAge   = 100
+	maxEnrollmentLimit = 50
+)
+
+// EligibilityError: недопуск с причинами (errors.Is(err, ErrNotEligible))
+type EligibilityError struct {
+	Reasons []IneligibilityReason
+}
+
+func (e *EligibilityError) Error() string { return ErrNotEligible.Error() }
+
+func (e *EligibilityError) Is(target error) bool { return target == ErrNotEligible }
+
+func (r EligibilityRule) Validate() error {
+	for _, v := range []*int{r.MinAge, r.MaxAge} {
+		if v != nil && (*v < 0 || *v > maxReasonableAge) {
+			return fmt.Errorf("%w: age must be 0..%d", ErrInvalidRule, maxReasonableAge)
+		}
+	}
+	if r.MinAge != nil && r.MaxAge != nil && *r.MinAge > *r.MaxAge {
+		return fmt.Errorf("%w: min_age > max_age", ErrInvalidRule)
+	}
+	if r.MaxActiveEnrollments != nil && (*r.MaxActiveEnrollments < 1 || *r.MaxActiveEnrollments > maxEnrollmentLimit) {
+		return fmt.Errorf("%w: max_active_enrollments must be 1..%d", ErrInvalidRule, maxEnrollmentLimit)
+	}
+	if len(r.RequiredProgramIDs) > maxRulePrograms {
+		return fmt.Errorf("%w: too many required programs", ErrInvalidRule)
+	}
+	if r.ProgramID != nil {
+		for _, id := range r.RequiredProgramIDs {
+			if id == *r.ProgramID {
+				return fmt.Errorf("%w: program cannot require itself", ErrInvalidRule)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate: причины недопуска (пусто — можно подавать заявку)
+func (r EligibilityRule) Evaluate(f EligibilityFacts, now time.Time) []IneligibilityReason {
+	var res []IneligibilityReason
+
+	if r.MinAge != nil || r.MaxAge != nil {
+		if f.BirthDate == nil {
+			res = append(res, IneligibilityReason{
+				Code:    ReasonBirthDateMissing,
+				Message: "birth date is required in the profile",
+			})
+		} else {
+			age := AgeOn(*f.BirthDate, now)
+			if r.MinAge != nil && age < *r.MinAge {
+				res = append(res, IneligibilityReason{
+					Code:    ReasonTooYoung,
+					Message: fmt.Sprintf("minimum age is %d", *r.MinAge),
+					Limit:   r.MinAge,
+				})
+			}
+			if r.MaxAge != nil && age > *r.MaxAge {
+				res = append(res, IneligibilityReason{
+					Code:    ReasonTooOld,
+					Message: fmt.Sprintf("maximum age is %d", *r.MaxAge),
+					Limit:   r.MaxAge,
+				})
+			}
+		}
+	}
+
+	done := map[uuid.UUID]bool{}
+	for _, id := range f.CompletedProgramIDs {
+		done[id] = true
+	}
+	for _, id := range r.RequiredProgramIDs {
+		if !done[id] {
+			pid := id
+			res = append(res, IneligibilityReason{
+				Code:      ReasonPrerequisite,
+				Message:   "required program is not completed",
+				ProgramID: &pid,
+			})
+		}
+	}
+
+	if r.MaxActiveEnrollments != nil && f.ActiveEnrollments >= *r.MaxActiveEnrollments {
+		res = append(res, IneligibilityReason{
+			Code:    ReasonTooManyEnrollment,
+			Message: fmt.Sprintf("at most %d active enrollments", *r.MaxActiveEnrollments),
+			Limit:   r.MaxActiveEnrollments,
+		})
+	}
+	return res
+}
+
+// AgeOn: полных лет на дату
+func AgeOn(birth, on time.Time) int {
+	age := on.Year() - birth.Year()
+	if on.Month() < birth.Month() || (on.Month() == birth.Month() && on.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
@@ -3,14 +3,108 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type EnrollmentStatus string
+
+const (
+	EnrollmentActive      EnrollmentStatus = "active"      // учится
+	EnrollmentWithdrawn   EnrollmentStatus = "withdrawn"   // ушёл сам (финал)
+	EnrollmentExpelled    EnrollmentStatus = "expelled"    // отчислен (финал)
+	EnrollmentCompleted   EnrollmentStatus = "completed"   // окончил курс (финал)
+	EnrollmentTransferred EnrollmentStatus = "transferred" // переведён в другую группу (финал)
+)
+
+func (s EnrollmentStatus) IsFinal() bool {
+	return s == EnrollmentWithdrawn || s == EnrollmentExpelled || s == EnrollmentCompleted || s == EnrollmentTransferred
+}
+
 type Enrollment struct {
 	ID        uuid.UUID
 	UserID    uuid.UUID
 	GroupID   uuid.UUID
+	Status    EnrollmentStatus
+	Reason    string     // причина последней смены статуса
+	EndedAt   *time.Time // когда перестал быть active
 	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type EnrollmentAudit struct {
+	ID          uuid.UUID
+	ActorUserID uuid.UUID
+	ActorRole   string
+	From        EnrollmentStatus
+	To          EnrollmentStatus
+	Reason      string
+	CreatedAt   time.Time
+}
+
+// EnrollmentTransfer: перевод ученика между группами одной программы
+type EnrollmentTransfer struct {
+	ID                 uuid.UUID
+	UserID             uuid.UUID
+	FromEnrollmentID   uuid.UUID
+	ToEnrollmentID     uuid.UUID
+	FromGroupID        uuid.UUID
+	ToGroupID          uuid.UUID
+	ActorUserID        uuid.UUID
+	ActorRole          string
+	Reason             string
+	CarryProgress      bool
+	CarriedReads       int // отметки о прочтении, перенесённые на такие же материалы целевой группы
+	CarriedSubmissions int // сданные работы, перенесённые на такие же задания
+	CreatedAt          time.Time
+}
+
+var (
+	ErrReasonRequired = errors.New("reason is required")
+	ErrExpelled       = errors.New("student was expelled from this group: readmission must be explicit")
+)
+
+// CanReadmit: прежнее зачисление в целевой группе снова становится active при переводе.
+// Ушедшего или переведённого раньше возвращаем; отчисленного — только явным решением staff (readmit).
+func CanReadmit(prev EnrollmentStatus, readmit bool) error {
+	if prev == EnrollmentExpelled && !readmit {
+		return ErrExpelled
+	}
+	return nil
+}
+
+// CanTransitionEnrollment:
+// ученик/guardian: active -> withdrawn
+// модератор/админ: active -> withdrawn/expelled/completed (отчисление — только с причиной)
+// active -> transferred — только через перевод (EnrollmentService.Transfer)
+func CanTransitionEnrollment(from, to EnrollmentStatus, actorRole, reason string) error {
+	if from.IsFinal() {
+		return ErrFinalStatus
+	}
+	if from != EnrollmentActive {
+		return ErrInvalidTransition
+	}
+
+	switch actorRole {
+	case "user", "guardian":
+		if to == EnrollmentWithdrawn {
+			return nil
+		}
+		return ErrInvalidTransition
+	case "moderator", "admin":
+		switch to {
+		case EnrollmentWithdrawn, EnrollmentCompleted:
+			return nil
+		case EnrollmentExpelled:
+			if reason == "" {
+				return ErrReasonRequired
+			}
+			return nil
+		}
+		return ErrInvalidTransition
+	default:
+		return errors.New("unknown role")
+	}
 }
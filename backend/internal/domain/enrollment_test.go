@@ -0,0 +1,61 @@
+// internal/domain/enrollment_test.go
+
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanTransitionEnrollment(t *testing.T) {
+	for _, tc := range []struct {
+		from, to EnrollmentStatus
+		role     string
+		reason   string
+		want     error
+	}{
+		{EnrollmentActive, EnrollmentWithdrawn, "user", "", nil},
+		{EnrollmentActive, EnrollmentWithdrawn, "guardian", "переезд", nil},
+		{EnrollmentActive, EnrollmentExpelled, "user", "", ErrInvalidTransition},
+		{EnrollmentActive, EnrollmentCompleted, "guardian", "", ErrInvalidTransition},
+
+		{EnrollmentActive, EnrollmentWithdrawn, "moderator", "", nil},
+		{EnrollmentActive, EnrollmentCompleted, "admin", "", nil},
+		{EnrollmentActive, EnrollmentExpelled, "moderator", "пропуски", nil},
+		{EnrollmentActive, EnrollmentExpelled, "admin", "", ErrReasonRequired},
+		// перевод — только через EnrollmentService.Transfer
+		{EnrollmentActive, EnrollmentTransferred, "admin", "", ErrInvalidTransition},
+		{EnrollmentActive, EnrollmentActive, "admin", "", ErrInvalidTransition},
+
+		{EnrollmentWithdrawn, EnrollmentActive, "admin", "", ErrFinalStatus},
+		{EnrollmentExpelled, EnrollmentWithdrawn, "user", "", ErrFinalStatus},
+		{EnrollmentCompleted, EnrollmentExpelled, "moderator", "x", ErrFinalStatus},
+		{EnrollmentTransferred, EnrollmentWithdrawn, "user", "", ErrFinalStatus},
+	} {
+		if err := CanTransitionEnrollment(tc.from, tc.to, tc.role, tc.reason); !errors.Is(err, tc.want) {
+			t.Errorf("%s: %s -> %s = %v, want %v", tc.role, tc.from, tc.to, err, tc.want)
+		}
+	}
+
+	if err := CanTransitionEnrollment(EnrollmentActive, EnrollmentWithdrawn, "teacher", ""); err == nil {
+		t.Error("unknown role passed")
+	}
+}
+
+func TestCanReadmit(t *testing.T) {
+	for _, tc := range []struct {
+		prev    EnrollmentStatus
+		readmit bool
+		want    error
+	}{
+		{EnrollmentWithdrawn, false, nil},
+		{EnrollmentTransferred, false, nil},
+		{EnrollmentCompleted, false, nil},
+		{EnrollmentExpelled, false, ErrExpelled},
+		{EnrollmentExpelled, true, nil},
+	} {
+		if err := CanReadmit(tc.prev, tc.readmit); !errors.Is(err, tc.want) {
+			t.Errorf("CanReadmit(%s, %v) = %v, want %v", tc.prev, tc.readmit, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+// internal/domain/form.go
+
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type QuestionType string
+
+const (
+	QuestionText   QuestionType = "text"
+	QuestionSingle QuestionType = "single_choice"
+	QuestionMulti  QuestionType = "multi_choice"
+	QuestionNumber QuestionType = "number"
+	QuestionDate   QuestionType = "date" // YYYY-MM-DD
+	QuestionFile   QuestionType = "file" // ссылка на файл (http/https)
+)
+
+const maxQuestionsPerForm = 100
+
+// Question — вопрос анкеты; Key — ключ в answers заявки
+type Question struct {
+	Key      string
+	Label    string
+	Type     QuestionType
+	Required bool
+	Options  []string `json:",omitempty"` // single_choice / multi_choice
+
+	// правила проверки (необязательные)
+	MinLength *int     `json:",omitempty"` // text
+	MaxLength *int     `json:",omitempty"` // text
+	Pattern   string   `json:",omitempty"` // text, регулярное выражение Go
+	Min       *float64 `json:",omitempty"` // number; multi_choice — минимум выбранных
+	Max       *float64 `json:",omitempty"` // number; multi_choice — максимум выбранных
+	Integer   bool     `json:",omitempty"` // number: только целые
+	MinDate   string   `json:",omitempty"` // date
+	MaxDate   string   `json:",omitempty"` // date
+}
+
+// ApplicationForm — анкета программы или группы (анкета группы важнее анкеты программы)
+type ApplicationForm struct {
+	ID        uuid.UUID
+	ProgramID *uuid.UUID
+	GroupID   *uuid.UUID
+	Questions []Question
+	UpdatedBy uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+var (
+	ErrInvalidForm    = errors.New("invalid application form")
+	ErrInvalidAnswers = errors.New("invalid answers")
+)
+
+var questionKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+const dateLayout = "2006-01-02"
+
+// Validate: проверка анкеты при сохранении
+func (f ApplicationForm) Validate() error {
+	if len(f.Questions) > maxQuestionsPerForm {
+		return fmt.Errorf("%w: too many questions", ErrInvalidForm)
+	}
+	seen := map[string]bool{}
+	for _, q := range f.Questions {
+		if !questionKeyRe.MatchString(q.Key) {
+			return fmt.Errorf("%w: bad key %q", ErrInvalidForm, q.Key)
+		}
+		if seen[q.Key] {
+			return fmt.Errorf("%w: duplicate key %q", ErrInvalidForm, q.Key)
+		}
+		seen[q.Key] = true
+		if strings.TrimSpace(q.Label) == "" {
+			return fmt.Errorf("%w: %s: label is required", ErrInvalidForm, q.Key)
+		}
+
+		switch q.Type {
+		case QuestionSingle, QuestionMulti:
+			if len(q.Options) == 0 {
+				return fmt.Errorf("%w: %s: options are required", ErrInvalidForm, q.Key)
+			}
+			opts := map[string]bool{}
+			for _, o := range q.Options {
+				if o == "" || opts[o] {
+					return fmt.Errorf("%w: %s: empty or duplicate option", ErrInvalidForm, q.Key)
+				}
+				opts[o] = true
+			}
+		case QuestionText:
+			if q.Pattern != "" {
+				if _, err := regexp.Compile(q.Pattern); err != nil {
+					return fmt.Errorf("%w: %s: bad pattern", ErrInvalidForm, q.Key)
+				}
+			}
+		case QuestionDate:
+			for _, d := range []string{q.MinDate, q.MaxDate} {
+				if d == "" {
+					continue
+				}
+				if _, err := time.Parse(dateLayout, d); err != nil {
+					return fmt.Errorf("%w: %s: dates must be YYYY-MM-DD", ErrInvalidForm, q.Key)
+				}
+			}
+		case QuestionNumber, QuestionFile:
+		default:
+			return fmt.Errorf("%w: %s: unknown type %q", ErrInvalidForm, q.Key, q.Type)
+		}
+		if q.Min != nil && q.Max != nil && *q.Min > *q.Max {
+			return fmt.Errorf("%w: %s: min > max", ErrInvalidForm, q.Key)
+		}
+	}
+	return nil
+}
+
+// ValidateAnswers: ответы заявителя по анкете. Возвращает нормализованные ответы
+// (только известные ключи; multi_choice — массив строк, number — число, date — YYYY-MM-DD).
+func (f ApplicationForm) ValidateAnswers(answers map[string]any) (map[string]any, error) {
+	known := map[string]bool{}
+	out := map[string]any{}
+	for _, q := range f.Questions {
+		known[q.Key] = true
+
+		v, ok := answers[q.Key]
+		if !ok || isEmptyAnswer(v) {
+			if q.Required {
+				return nil, fmt.Errorf("%w: %s is required", ErrInvalidAnswers, q.Key)
+			}
+			continue
+		}
+		nv, err := q.normalize(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidAnswers, q.Key, err)
+		}
+		out[q.Key] = nv
+	}
+	for k := range answers {
+		if !known[k] {
+			return nil, fmt.Errorf("%w: unknown question %q", ErrInvalidAnswers, k)
+		}
+	}
+	return out, nil
+}
+
+func isEmptyAnswer(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(x) == ""
+	case []any:
+		return len(x) == 0
+	}
+	return false
+}
+
+func (q Question) normalize(v any) (any, error) {
+	switch q.Type {
+	case QuestionText:
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be a string")
+		}
+		s = strings.TrimSpace(s)
+		n := utf8.RuneCountInString(s)
+		if q.MinLength != nil && n < *q.MinLength {
+			return nil, fmt.Errorf("at least %d characters", *q.MinLength)
+		}
+		if q.MaxLength != nil && n > *q.MaxLength {
+			return nil, fmt.Errorf("at most %d characters", *q.MaxLength)
+		}
+		if q.Pattern != "" {
+			re, err := regexp.Compile(q.Pattern)
+			if err != nil || !re.MatchString(s) {
+				return nil, errors.New("does not match the pattern")
+			}
+		}
+		return s, nil
+
+	case QuestionSingle:
+		s, ok := v.(string)
+		if !ok || !q.hasOption(s) {
+			return nil, errors.New("must be one of the options")
+		}
+		return s, nil
+
+	case QuestionMulti:
+		arr, ok := v.([]any)
+		if !ok {
+			return nil, errors.New("must be an array of options")
+		}
+		res := make([]string, 0, len(arr))
+		seen := map[string]bool{}
+		for _, x := range arr {
+			s, ok := x.(string)
+			if !ok || !q.hasOption(s) {
+				return nil, errors.New("must be an array of options")
+			}
+			if !seen[s] {
+				seen[s] = true
+				res = append(res, s)
+			}
+		}
+		if q.Min != nil && float64(len(res)) < *q.Min {
+			return nil, fmt.Errorf("choose at least %v", *q.Min)
+		}
+		if q.Max != nil && float64(len(res)) > *q.Max {
+			return nil, fmt.Errorf("choose at most %v", *q.Max)
+		}
+		return res, nil
+
+	case QuestionNumber:
+		n, ok := v.(float64) // encoding/json
+		if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, errors.New("must be a number")
+		}
+		if q.Integer && n != math.Trunc(n) {
+			return nil, errors.New("must be an integer")
+		}
+		if q.Min != nil && n < *q.Min {
+			return nil, fmt.Errorf("must be >= %v", *q.Min)
+		}
+		if q.Max != nil && n > *q.Max {
+			return nil, fmt.Errorf("must be <= %v", *q.Max)
+		}
+		return n, nil
+
+	case QuestionDate:
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be a date YYYY-MM-DD")
+		}
+		d, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return nil, errors.New("must be a date YYYY-MM-DD")
+		}
+		if q.MinDate != "" {
+			if min, _ := time.Parse(dateLayout, q.MinDate); d.Before(min) {
+				return nil, fmt.Errorf("must be on or after %s", q.MinDate)
+			}
+		}
+		if q.MaxDate != "" {
+			if max, _ := time.Parse(dateLayout, q.MaxDate); d.After(max) {
+				return nil, fmt.Errorf("must be on or before %s", q.MaxDate)
+			}
+		}
+		return d.Format(dateLayout), nil
+
+	case QuestionFile:
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be a file link")
+		}
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, errors.New("must be an http(s) link")
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", q.Type)
+}
+
+func (q Question) hasOption(s string) bool {
+	for _, o := range q.Options {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+// internal/domain/interview_slot.go
+
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InterviewSlot — время собеседования, опубликованное преподавателем группы.
+// Слот занят, пока забронировавшая его заявка в статусе in_review.
+type InterviewSlot struct {
+	ID             uuid.UUID
+	GroupID        uuid.UUID
+	TeacherUserID  uuid.UUID
+	StartsAt       time.Time
+	EndsAt         time.Time
+	Location       string     // очно: адрес, кабинет
+	OnlineURL      string     // онлайн: ссылка на звонок
+	ApplicationID  *uuid.UUID // nil — свободен
+	BookedAt       *time.Time
+	ReminderSentAt *time.Time
+	CreatedAt      time.Time
+}
+
+var ErrInvalidSlot = errors.New("invalid interview slot")
+
+const MaxSlotDuration = 8 * time.Hour
+
+func (s InterviewSlot) Validate() error {
+	if !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidSlot)
+	}
+	if s.EndsAt.Sub(s.StartsAt) > MaxSlotDuration {
+		return fmt.Errorf("%w: slot is longer than %s", ErrInvalidSlot, MaxSlotDuration)
+	}
+	if strings.TrimSpace(s.Location) == "" && strings.TrimSpace(s.OnlineURL) == "" {
+		return fmt.Errorf("%w: location or online_url is required", ErrInvalidSlot)
+	}
+	if s.OnlineURL != "" {
+		u, err := url.Parse(s.OnlineURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: online_url must be an http(s) link", ErrInvalidSlot)
+		}
+	}
+	return nil
+}
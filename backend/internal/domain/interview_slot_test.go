@@ -0,0 +1,36 @@
+// internal/domain/interview_slot_test.go
+
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInterviewSlotValidate(t *testing.T) {
+	start := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name string
+		slot InterviewSlot
+		ok   bool
+	}{
+		{"offline", InterviewSlot{StartsAt: start, EndsAt: start.Add(30 * time.Minute), Location: "каб. 12"}, true},
+		{"online", InterviewSlot{StartsAt: start, EndsAt: start.Add(time.Hour), OnlineURL: "https://meet.example.com/abc"}, true},
+		{"max duration", InterviewSlot{StartsAt: start, EndsAt: start.Add(MaxSlotDuration), Location: "каб. 12"}, true},
+		{"ends before start", InterviewSlot{StartsAt: start, EndsAt: start.Add(-time.Minute), Location: "каб. 12"}, false},
+		{"zero length", InterviewSlot{StartsAt: start, EndsAt: start, Location: "каб. 12"}, false},
+		{"too long", InterviewSlot{StartsAt: start, EndsAt: start.Add(MaxSlotDuration + time.Minute), Location: "каб. 12"}, false},
+		{"no place", InterviewSlot{StartsAt: start, EndsAt: start.Add(time.Hour), Location: "  "}, false},
+		{"not http", InterviewSlot{StartsAt: start, EndsAt: start.Add(time.Hour), OnlineURL: "javascript:alert(1)"}, false},
+		{"no host", InterviewSlot{StartsAt: start, EndsAt: start.Add(time.Hour), OnlineURL: "https://"}, false},
+	} {
+		err := tc.slot.Validate()
+		if tc.ok && err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+		if !tc.ok && !errors.Is(err, ErrInvalidSlot) {
+			t.Errorf("%s: err = %v, want ErrInvalidSlot", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// internal/domain/notification.go
+
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LocaleRU = "ru"
+	LocaleEN = "en"
+)
+
+type NotificationPreferences struct {
+	UserID       uuid.UUID
+	Locale       string
+	EmailEnabled bool
+	MutedKinds   []string // event_type, например material.created
+	UpdatedAt    time.Time
+}
+
+func DefaultNotificationPreferences(userID uuid.UUID) NotificationPreferences {
+	return NotificationPreferences{UserID: userID, Locale: LocaleRU, EmailEnabled: true, MutedKinds: []string{}}
+}
+
+func (p NotificationPreferences) Muted(kind string) bool {
+	for _, k := range p.MutedKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification — запись во входящих (in-app)
+type Notification struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	EventID   uuid.UUID
+	Kind      string // event_type
+	Title     string
+	Body      string
+	Data      json.RawMessage // payload события (id заявки, группы и т.п. для ссылок на фронте)
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
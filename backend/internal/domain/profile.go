@@ -0,0 +1,21 @@
+// internal/domain/profile.go
+
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Profile struct {
+	UserID       uuid.UUID
+	FullName     string
+	BirthDate    *time.Time // только дата
+	School       string
+	Grade        *int // класс 1..11
+	ContactEmail string
+	ContactPhone string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
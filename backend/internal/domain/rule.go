@@ -0,0 +1,58 @@
+// internal/domain/rule.go
+
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event -> Rule -> Action: правило срабатывает на событие outbox с подходящим payload
+type RuleAction string
+
+const (
+	ActionNotify               RuleAction = "notify"                 // notification.requested в outbox
+	ActionSetApplicationStatus RuleAction = "set_application_status" // сменить статус заявки от имени system
+	ActionWebhook              RuleAction = "webhook"                // POST payload на url
+)
+
+// RuleCondition: поле payload (через точку для вложенных), оператор и значение
+// op: eq | ne | in | exists
+type RuleCondition struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value any    `json:"value,omitempty"`
+}
+
+type Rule struct {
+	ID           uuid.UUID
+	Name         string
+	EventType    string
+	Conditions   []RuleCondition // все должны выполниться (AND)
+	Action       RuleAction
+	ActionParams map[string]any
+	IsEnabled    bool
+	CreatedBy    uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type RuleExecutionStatus string
+
+const (
+	RuleExecSuccess RuleExecutionStatus = "success"
+	RuleExecFailed  RuleExecutionStatus = "failed"
+)
+
+type RuleExecution struct {
+	ID        uuid.UUID
+	RuleID    uuid.UUID
+	EventID   uuid.UUID
+	EventType string
+	Status    RuleExecutionStatus
+	Attempts  int
+	Error     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
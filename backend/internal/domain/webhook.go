@@ -0,0 +1,57 @@
+// internal/domain/webhook.go
+
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSubscription: куда слать события outbox (например, CRM школы)
+type WebhookSubscription struct {
+	ID          uuid.UUID
+	URL         string
+	EventTypes  []string // пусто — все события
+	Secret      string   `json:"-"` // ключ HMAC, отдаётся только при создании
+	Description string
+	IsEnabled   bool
+	CreatedBy   uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryFailed    WebhookDeliveryStatus = "failed" // попытки исчерпаны
+)
+
+// WebhookDelivery: одно событие для одной подписки
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      string
+	Payload        json.RawMessage
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+type WebhookAttempt struct {
+	ID           uuid.UUID
+	DeliveryID   uuid.UUID
+	AttemptNo    int
+	StatusCode   *int
+	Error        *string
+	ResponseBody string // первые байты ответа
+	DurationMs   int
+	CreatedAt    time.Time
+}
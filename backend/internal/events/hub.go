@@ -0,0 +1,157 @@
+// internal/events/hub.go
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+)
+
+// Message — событие outbox с разобранными полями для фильтрации
+type Message struct {
+	outbox.Event
+	UserID   uuid.UUID // чьё событие (payload.user_id)
+	GroupID  uuid.UUID // payload.group_id
+	Internal bool      // payload.internal: только staff и преподаватели группы
+}
+
+func NewMessage(e outbox.Event) Message {
+	var p struct {
+		UserID   string `json:"user_id"`
+		GroupID  string `json:"group_id"`
+		Internal bool   `json:"internal"`
+	}
+	_ = json.Unmarshal(e.Payload, &p)
+	m := Message{Event: e}
+	m.UserID, _ = uuid.Parse(p.UserID)
+	m.GroupID, _ = uuid.Parse(p.GroupID)
+	m.Internal = p.Internal
+	return m
+}
+
+type Subscriber struct {
+	C    chan Message
+	once sync.Once
+}
+
+// Hub: один опрос outbox_events на процесс, раздача всем SSE-подписчикам.
+// Медленный подписчик отключается (канал закрывается) и догоняет через Last-Event-ID.
+type Hub struct {
+	outbox   *outbox.Repo
+	interval time.Duration
+	grace    time.Duration // сколько ждать "дырку" в seq (транзакция ещё не закоммичена)
+
+	mu   sync.Mutex
+	subs map[*Subscriber]struct{}
+}
+
+func NewHub(outbox *outbox.Repo, interval time.Duration) *Hub {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Hub{outbox: outbox, interval: interval, grace: 10 * time.Second, subs: map[*Subscriber]struct{}{}}
+}
+
+func (h *Hub) Subscribe() *Subscriber {
+	s := &Subscriber{C: make(chan Message, 256)}
+	h.mu.Lock()
+	h.subs[s] = struct{}{}
+	h.mu.Unlock()
+	return s
+}
+
+func (h *Hub) Unsubscribe(s *Subscriber) {
+	h.mu.Lock()
+	delete(h.subs, s)
+	h.mu.Unlock()
+	s.once.Do(func() { close(s.C) })
+}
+
+func (h *Hub) broadcast(m Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subs {
+		select {
+		case s.C <- m:
+		default:
+			delete(h.subs, s)
+			s.once.Do(func() { close(s.C) })
+		}
+	}
+}
+
+// Run опрашивает outbox_events до отмены ctx.
+// seq выдаётся при insert, а коммит может прийти позже — поэтому курсор двигается только
+// по непрерывному префиксу, а пропуски ждём grace (откатившаяся транзакция оставляет дырку навсегда).
+func (h *Hub) Run(ctx context.Context) {
+	cursor, err := h.outbox.LatestSeq(ctx)
+	for err != nil {
+		slog.Error("events hub", "err", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(h.interval):
+		}
+		cursor, err = h.outbox.LatestSeq(ctx)
+	}
+
+	seen := map[int64]bool{}
+	var gapSince time.Time
+
+	t := time.NewTicker(h.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		evs, err := h.outbox.ListSince(ctx, cursor, 500)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("events hub", "err", err)
+			}
+			continue
+		}
+		for _, e := range evs {
+			if seen[e.Seq] {
+				continue
+			}
+			seen[e.Seq] = true
+			h.broadcast(NewMessage(e))
+		}
+
+		// двигаем курсор по непрерывному префиксу
+		for seen[cursor+1] {
+			delete(seen, cursor+1)
+			cursor++
+			gapSince = time.Time{}
+		}
+		if len(seen) == 0 {
+			gapSince = time.Time{}
+			continue
+		}
+		if gapSince.IsZero() {
+			gapSince = time.Now()
+			continue
+		}
+		if time.Since(gapSince) > h.grace {
+			// дырка так и не заполнилась — перескакиваем до следующего увиденного
+			next := cursor + 1
+			for !seen[next] {
+				next++
+			}
+			cursor = next - 1
+			gapSince = time.Time{}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// internal/events/scope.go
+
+package events
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+// групповые события, которые видят все ученики группы
+var groupWide = map[string]bool{
+	outbox.EventMaterialCreated:   true,
+	outbox.EventAssignmentCreated: true,
+}
+
+// Scope: что видит подключившийся. Собирается один раз при подключении.
+type Scope struct {
+	all           bool               // staff
+	users         map[uuid.UUID]bool // сам + дети (guardian)
+	teacherGroups map[uuid.UUID]bool
+	learnerGroups map[uuid.UUID]bool
+}
+
+func NewScope(ctx context.Context, catalog *repo.CatalogRepo, apps *repo.ApplicationRepo, guardians *repo.GuardianRepo) (Scope, error) {
+	uid, ok := auth.UserID(ctx)
+	if !ok {
+		return Scope{}, authz.ErrUnauthorized
+	}
+	if authz.Can(ctx, authz.ApplicationList) {
+		return Scope{all: true}, nil
+	}
+
+	s := Scope{
+		users:         map[uuid.UUID]bool{uid: true},
+		teacherGroups: map[uuid.UUID]bool{},
+		learnerGroups: map[uuid.UUID]bool{},
+	}
+
+	gs, err := catalog.ListTeacherGroups(ctx, uid)
+	if err != nil {
+		return Scope{}, err
+	}
+	for _, g := range gs {
+		s.teacherGroups[g.ID] = true
+	}
+
+	children, err := guardians.ListChildren(ctx, uid)
+	if err != nil {
+		return Scope{}, err
+	}
+	for _, c := range children {
+		s.users[c.UserID] = true
+	}
+
+	for u := range s.users {
+		ids, err := apps.ListEnrolledGroupIDs(ctx, u)
+		if err != nil {
+			return Scope{}, err
+		}
+		for _, id := range ids {
+			s.learnerGroups[id] = true
+		}
+	}
+	return s, nil
+}
+
+func (s Scope) Allows(m Message) bool {
+	// запросы уведомлений от правил — служебные, в поток не отдаём
+	if m.EventType == outbox.EventNotificationRequested {
+		return false
+	}
+	if s.all {
+		return true
+	}
+	// внутренние заметки по заявке заявителю не отдаём
+	if m.Internal {
+		return m.GroupID != uuid.Nil && s.teacherGroups[m.GroupID]
+	}
+	if m.UserID != uuid.Nil && s.users[m.UserID] {
+		return true
+	}
+	if m.GroupID != uuid.Nil {
+		if s.teacherGroups[m.GroupID] {
+			return true
+		}
+		if groupWide[m.EventType] && s.learnerGroups[m.GroupID] {
+			return true
+		}
+	}
+	return false
+}
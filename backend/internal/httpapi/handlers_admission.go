@@ -0,0 +1,162 @@
+// internal/httpapi/handlers_admission.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/service"
+)
+
+// AdmissionHandler: конкурсный приём (режим группы, баллы, рейтинг, подведение)
+type AdmissionHandler struct {
+	v   *validator.Validate
+	svc *service.AdmissionService
+}
+
+func NewAdmissionHandler(svc *service.AdmissionService) *AdmissionHandler {
+	return &AdmissionHandler{v: validator.New(), svc: svc}
+}
+
+// GET /admin/groups/{id}/admission
+func (h *AdmissionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	adm, err := h.svc.Get(r.Context(), gid)
+	if err != nil {
+		writeAdmissionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, adm)
+}
+
+type admissionReq struct {
+	Mode    string              `json:"mode" validate:"required,oneof=first_come competitive"`
+	Scoring domain.ScoringRules `json:"scoring"`
+}
+
+// PUT /admin/groups/{id}/admission — полная замена режима и правил баллов
+func (h *AdmissionHandler) Put(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var req admissionReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Update(r.Context(), gid, domain.AdmissionMode(req.Mode), req.Scoring); err != nil {
+		writeAdmissionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /admin/groups/{id}/ranking — staff или преподаватель группы
+func (h *AdmissionHandler) Ranking(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.Ranking(r.Context(), gid)
+	if err != nil {
+		writeAdmissionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+type scoreReq struct {
+	Points  *float64 `json:"points" validate:"required"`
+	Comment string   `json:"comment" validate:"max=1000"`
+}
+
+// PUT /admin/applications/{id}/score — ручные баллы
+func (h *AdmissionHandler) SetScore(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	var req scoreReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sc, err := h.svc.SetScore(r.Context(), appID, *req.Points, req.Comment)
+	if err != nil {
+		writeAdmissionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sc)
+}
+
+type finalizeReq struct {
+	Rest   string `json:"rest" validate:"required,oneof=waitlisted rejected"`
+	Reason string `json:"reason"`
+}
+
+// POST /admin/groups/{id}/admission/finalize — подвести конкурс
+func (h *AdmissionHandler) Finalize(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var req finalizeReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.Finalize(r.Context(), gid, domain.ApplicationStatus(req.Rest), req.Reason)
+	if err != nil {
+		writeAdmissionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func writeAdmissionError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "unauthorized":
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, authz.ErrForbidden), errors.Is(err, authz.ErrUnauthorized):
+		authz.WriteError(w, err)
+	case errors.Is(err, domain.ErrInvalidScoring), errors.Is(err, service.ErrInvalidRest), errors.Is(err, service.ErrInvalidPoints):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrNotCompetitive), errors.Is(err, service.ErrAdmissionFinalized),
+		errors.Is(err, service.ErrInterviewsPending), errors.Is(err, domain.ErrFinalStatus):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -4,14 +4,17 @@ package httpapi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
 	"github.com/Pavlushechko/itcube-education/internal/domain"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
 	"github.com/Pavlushechko/itcube-education/internal/service"
@@ -21,21 +24,23 @@ type ApplicationHandler struct {
 	validate *validator.Validate
 	svc      *service.ApplicationService
 	appRepo  *repo.ApplicationRepo
-	catalog  *repo.CatalogRepo
+	az       *authz.Authorizer
 }
 
-func NewApplicationHandler(svc *service.ApplicationService, appRepo *repo.ApplicationRepo, catalog *repo.CatalogRepo) *ApplicationHandler {
+func NewApplicationHandler(svc *service.ApplicationService, appRepo *repo.ApplicationRepo, az *authz.Authorizer) *ApplicationHandler {
 	return &ApplicationHandler{
 		validate: validator.New(),
 		svc:      svc,
 		appRepo:  appRepo,
-		catalog:  catalog,
+		az:       az,
 	}
 }
 
 type createAppReq struct {
-	GroupID string `json:"group_id" validate:"required,uuid"`
-	Comment string `json:"comment"`
+	GroupID string         `json:"group_id" validate:"required,uuid"`
+	Comment string         `json:"comment"`
+	ChildID string         `json:"child_id" validate:"omitempty,uuid"` // guardian подаёт за ребёнка
+	Answers map[string]any `json:"answers"`                            // ответы на анкету программы/группы
 }
 
 func (h *ApplicationHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -50,7 +55,25 @@ func (h *ApplicationHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	gid, _ := uuid.Parse(req.GroupID)
 
-	id, err := h.svc.Create(r.Context(), gid, req.Comment)
+	var (
+		id  uuid.UUID
+		err error
+	)
+	if req.ChildID != "" {
+		cid, _ := uuid.Parse(req.ChildID)
+		id, err = h.svc.CreateForChild(r.Context(), cid, gid, req.Comment, req.Answers)
+	} else {
+		id, err = h.svc.Create(r.Context(), gid, req.Comment, req.Answers)
+	}
+	if errors.Is(err, service.ErrNotGuardian) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	var notEligible *domain.EligibilityError
+	if errors.As(err, &notEligible) {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": err.Error(), "reasons": notEligible.Reasons})
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -78,12 +101,6 @@ type changeStatusReq struct {
 }
 
 func (h *ApplicationHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
-	role := auth.Role(r.Context())
-	if role != "admin" && role != "moderator" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
 	idStr := chi.URLParam(r, "id")
 	appID, err := uuid.Parse(idStr)
 	if err != nil {
@@ -99,12 +116,174 @@ func (h *ApplicationHandler) ChangeStatus(w http.ResponseWriter, r *http.Request
 
 	to := domain.ApplicationStatus(req.Status)
 	if err := h.svc.ChangeStatus(r.Context(), appID, to, req.Reason); err != nil {
+		if errors.Is(err, authz.ErrForbidden) || errors.Is(err, authz.ErrUnauthorized) {
+			authz.WriteError(w, err)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+type bulkStatusReq struct {
+	IDs    []string `json:"ids" validate:"omitempty,max=500,dive,uuid"`
+	Filter *struct {
+		ProgramID string `json:"program_id" validate:"omitempty,uuid"`
+		Status    string `json:"status"`
+		Year      *int   `json:"year"`
+	} `json:"filter"`
+	Status string `json:"status" validate:"required,oneof=in_review approved rejected waitlisted"`
+	Reason string `json:"reason"`
+	Mode   string `json:"mode" validate:"omitempty,oneof=all_or_nothing best_effort"`
+}
+
+// POST /admin/applications/bulk-status — ids или filter (как у списка заявок), отчёт по каждой заявке
+func (h *ApplicationHandler) BulkChangeStatus(w http.ResponseWriter, r *http.Request) {
+	var req bulkStatusReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if (len(req.IDs) > 0) == (req.Filter != nil) {
+		http.Error(w, "either ids or filter is required", http.StatusBadRequest)
+		return
+	}
+	mode := service.BulkAllOrNothing
+	if req.Mode != "" {
+		mode = service.BulkMode(req.Mode)
+	}
+
+	var ids []uuid.UUID
+	if req.Filter != nil {
+		var f service.BulkStatusFilter
+		if req.Filter.ProgramID != "" {
+			pid, _ := uuid.Parse(req.Filter.ProgramID)
+			f.ProgramID = &pid
+		}
+		if req.Filter.Status != "" {
+			f.Status = &req.Filter.Status
+		}
+		f.Year = req.Filter.Year
+
+		var err error
+		if ids, err = h.svc.BulkIDsByFilter(r.Context(), f); err != nil {
+			writeBulkError(w, err)
+			return
+		}
+	} else {
+		for _, v := range req.IDs {
+			id, _ := uuid.Parse(v)
+			ids = append(ids, id)
+		}
+	}
+
+	rep, err := h.svc.BulkChangeStatus(r.Context(), ids, domain.ApplicationStatus(req.Status), req.Reason, mode)
+	if err != nil {
+		writeBulkError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rep)
+}
+
+func writeBulkError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "unauthorized":
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, authz.ErrForbidden), errors.Is(err, authz.ErrUnauthorized):
+		authz.WriteError(w, err)
+	case errors.Is(err, service.ErrBulkEmpty), errors.Is(err, service.ErrBulkTooLarge):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GET /enrollments/applications/{id}/waitlist — место в листе ожидания (заявитель или guardian)
+func (h *ApplicationHandler) WaitlistPosition(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+
+	pos, err := h.svc.WaitlistPosition(r.Context(), appID)
+	if err != nil {
+		switch {
+		case err.Error() == "unauthorized":
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, service.ErrNotGuardian):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, service.ErrNotWaitlisted), errors.Is(err, pgx.ErrNoRows):
+			http.Error(w, "not found", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, pos)
+}
+
+// GET /admin/applications/{id}/history — staff или преподаватель группы заявки
+func (h *ApplicationHandler) History(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+
+	app, err := h.appRepo.Get(r.Context(), appID)
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+	if err := h.az.RequireInGroup(r.Context(), authz.ApplicationList, app.GroupID); err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	hist, err := h.svc.History(r.Context(), appID)
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, hist)
+}
+
+// GET /enrollments/applications/{id}/history — заявитель или его guardian
+func (h *ApplicationHandler) MyHistory(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	hist, err := h.svc.MyHistory(r.Context(), appID)
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, hist)
+}
+
+func writeHistoryError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "unauthorized":
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, authz.ErrForbidden), errors.Is(err, authz.ErrUnauthorized):
+		authz.WriteError(w, err)
+	case errors.Is(err, service.ErrNotGuardian):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func writeJSON(w http.ResponseWriter, code int, v any) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(code)
@@ -117,8 +296,7 @@ func (h *ApplicationHandler) List(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
-	role := auth.Role(r.Context())
-	isStaff := role == "admin" || role == "moderator"
+	isStaff := authz.Can(r.Context(), authz.ApplicationList)
 
 	// filters
 	var groupID *uuid.UUID
@@ -157,6 +335,17 @@ func (h *ApplicationHandler) List(w http.ResponseWriter, r *http.Request) {
 		year = &n
 	}
 
+	// answers={"key":"value"} — заявки, ответы которых содержат указанные (jsonb @>)
+	var answers []byte
+	if v := r.URL.Query().Get("answers"); v != "" {
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(v), &obj); err != nil {
+			http.Error(w, "invalid answers filter", http.StatusBadRequest)
+			return
+		}
+		answers = []byte(v)
+	}
+
 	// Для не-staff обязательно нужен фильтр program_id или group_id
 	if !isStaff && groupID == nil && programID == nil {
 		http.Error(w, "group_id or program_id is required", http.StatusBadRequest)
@@ -166,19 +355,18 @@ func (h *ApplicationHandler) List(w http.ResponseWriter, r *http.Request) {
 	// Case 1: group_id filter
 	if groupID != nil {
 		if !isStaff {
-			assigned, err := h.catalog.IsTeacherInGroup(r.Context(), *groupID, actorID)
-			if err != nil {
+			if err := h.az.RequireInGroup(r.Context(), authz.ApplicationList, *groupID); err != nil {
+				if errors.Is(err, authz.ErrForbidden) {
+					authz.WriteError(w, err)
+					return
+				}
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
-			if !assigned {
-				http.Error(w, "forbidden", http.StatusForbidden)
-				return
-			}
 		}
 
 		// VIEW с year
-		apps, err := h.appRepo.ListByFilterView(r.Context(), groupID, status, year)
+		apps, err := h.appRepo.ListByFilterView(r.Context(), groupID, status, year, answers)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -195,9 +383,9 @@ func (h *ApplicationHandler) List(w http.ResponseWriter, r *http.Request) {
 		)
 
 		if isStaff {
-			apps, err = h.appRepo.ListByProgramView(r.Context(), *programID, status, year)
+			apps, err = h.appRepo.ListByProgramView(r.Context(), *programID, status, year, answers)
 		} else {
-			apps, err = h.appRepo.ListForTeacherByProgramView(r.Context(), actorID, *programID, status, year)
+			apps, err = h.appRepo.ListForTeacherByProgramView(r.Context(), actorID, *programID, status, year, answers)
 		}
 
 		if err != nil {
@@ -210,7 +398,7 @@ func (h *ApplicationHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	// Case 3: staff без фильтров — показать все
 	if isStaff {
-		apps, err := h.appRepo.ListAllView(r.Context(), status, year)
+		apps, err := h.appRepo.ListAllView(r.Context(), status, year, answers)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -235,6 +423,10 @@ func (h *ApplicationHandler) CancelMyApplication(w http.ResponseWriter, r *http.
 			http.Error(w, msg, http.StatusUnauthorized)
 			return
 		}
+		if errors.Is(err, service.ErrNotGuardian) {
+			http.Error(w, msg, http.StatusForbidden)
+			return
+		}
 		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
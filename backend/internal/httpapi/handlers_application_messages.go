@@ -0,0 +1,128 @@
+// internal/httpapi/handlers_application_messages.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/service"
+)
+
+type ApplicationMessageHandler struct {
+	v   *validator.Validate
+	svc *service.ApplicationMessageService
+}
+
+func NewApplicationMessageHandler(svc *service.ApplicationMessageService) *ApplicationMessageHandler {
+	return &ApplicationMessageHandler{v: validator.New(), svc: svc}
+}
+
+type postMessageReq struct {
+	Body     string `json:"body" validate:"required,max=4000"`
+	Internal bool   `json:"internal"` // только для /admin: заметка, которую заявитель не видит
+}
+
+func (h *ApplicationMessageHandler) decode(w http.ResponseWriter, r *http.Request) (uuid.UUID, postMessageReq, bool) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return uuid.Nil, postMessageReq{}, false
+	}
+	var req postMessageReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return uuid.Nil, postMessageReq{}, false
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return uuid.Nil, postMessageReq{}, false
+	}
+	return appID, req, true
+}
+
+// GET /enrollments/applications/{id}/messages — заявитель или guardian
+func (h *ApplicationMessageHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	items, err := h.svc.ListForApplicant(r.Context(), appID)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// POST /enrollments/applications/{id}/messages
+func (h *ApplicationMessageHandler) PostMine(w http.ResponseWriter, r *http.Request) {
+	appID, req, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	if req.Internal {
+		http.Error(w, "internal notes are staff-only", http.StatusBadRequest)
+		return
+	}
+	m, err := h.svc.PostAsApplicant(r.Context(), appID, req.Body)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, m)
+}
+
+// GET /admin/applications/{id}/messages — staff или преподаватель группы (с internal)
+func (h *ApplicationMessageHandler) ListStaff(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	items, err := h.svc.ListForStaff(r.Context(), appID)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// POST /admin/applications/{id}/messages
+func (h *ApplicationMessageHandler) PostStaff(w http.ResponseWriter, r *http.Request) {
+	appID, req, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	m, err := h.svc.PostAsStaff(r.Context(), appID, req.Body, req.Internal)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, m)
+}
+
+func writeMessageError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "unauthorized":
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, authz.ErrForbidden), errors.Is(err, authz.ErrUnauthorized):
+		authz.WriteError(w, err)
+	case errors.Is(err, service.ErrNotGuardian):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrEmptyMessage):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
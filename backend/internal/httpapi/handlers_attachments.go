@@ -0,0 +1,175 @@
+// internal/httpapi/handlers_attachments.go
+
+package httpapi
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/service"
+	"github.com/Pavlushechko/itcube-education/internal/storage"
+)
+
+type AttachmentHandler struct {
+	svc *service.AttachmentService
+}
+
+func NewAttachmentHandler(svc *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{svc: svc}
+}
+
+func attachmentIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	attID, err := uuid.Parse(chi.URLParam(r, "attID"))
+	if err != nil {
+		http.Error(w, "invalid attachment id", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return appID, attID, true
+}
+
+// POST /enrollments/applications/{id}/attachments — multipart/form-data, поле "file"
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	// запас на заголовки multipart; точный лимит файла проверяет сервис
+	r.Body = http.MaxBytesReader(w, r.Body, h.svc.MaxSize()+64<<10)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "multipart/form-data expected", http.StatusBadRequest)
+		return
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			http.Error(w, "file is required", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeAttachmentError(w, err)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+		a, err := h.svc.Upload(r.Context(), appID, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			writeAttachmentError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, a)
+		return
+	}
+}
+
+// GET /enrollments/applications/{id}/attachments — заявитель или guardian
+func (h *AttachmentHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	items, err := h.svc.ListForApplicant(r.Context(), appID)
+	if err != nil {
+		writeAttachmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// DELETE /enrollments/applications/{id}/attachments/{attID}
+func (h *AttachmentHandler) DeleteMine(w http.ResponseWriter, r *http.Request) {
+	appID, attID, ok := attachmentIDs(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteAsApplicant(r.Context(), appID, attID); err != nil {
+		writeAttachmentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /admin/applications/{id}/attachments
+func (h *AttachmentHandler) ListStaff(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	items, err := h.svc.ListForStaff(r.Context(), appID)
+	if err != nil {
+		writeAttachmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// GET /admin/applications/{id}/attachments/{attID}/download
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	appID, attID, ok := attachmentIDs(w, r)
+	if !ok {
+		return
+	}
+	a, body, err := h.svc.Download(r.Context(), appID, attID)
+	if err != nil {
+		writeAttachmentError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		slog.Warn("attachment download", "attachment_id", attID, "err", err)
+	}
+}
+
+func writeAttachmentError(w http.ResponseWriter, err error) {
+	var tooBig *http.MaxBytesError
+	switch {
+	case err.Error() == "unauthorized":
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, authz.ErrForbidden), errors.Is(err, authz.ErrUnauthorized):
+		authz.WriteError(w, err)
+	case errors.Is(err, service.ErrNotGuardian):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrFileTooLarge), errors.As(err, &tooBig):
+		http.Error(w, service.ErrFileTooLarge.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, service.ErrFileTypeNotAllowed):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, service.ErrFileEmpty):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrFileInfected):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, service.ErrAttachmentsClosed), errors.Is(err, service.ErrTooManyAttachments):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, pgx.ErrNoRows), errors.Is(err, storage.ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
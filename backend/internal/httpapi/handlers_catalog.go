@@ -4,20 +4,24 @@ package httpapi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
-	"github.com/Pavlushechko/itcube-education/internal/auth"
 	"github.com/Pavlushechko/itcube-education/internal/domain"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
+	"github.com/Pavlushechko/itcube-education/internal/service"
 )
 
 type CatalogHandler struct {
 	v       *validator.Validate
 	catalog *repo.CatalogRepo
+	apps    *service.ApplicationService
 }
 
 type ProgramAdminView struct {
@@ -26,8 +30,8 @@ type ProgramAdminView struct {
 	Groups  []domain.Group  `json:"Groups"`
 }
 
-func NewCatalogHandler(catalog *repo.CatalogRepo) *CatalogHandler {
-	return &CatalogHandler{v: validator.New(), catalog: catalog}
+func NewCatalogHandler(catalog *repo.CatalogRepo, apps *service.ApplicationService) *CatalogHandler {
+	return &CatalogHandler{v: validator.New(), catalog: catalog, apps: apps}
 }
 
 // Public: list published programs
@@ -63,10 +67,6 @@ type createProgramReq struct {
 }
 
 func (h *CatalogHandler) CreateProgram(w http.ResponseWriter, r *http.Request) {
-	if auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
 	var req createProgramReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "bad json", http.StatusBadRequest)
@@ -85,10 +85,6 @@ func (h *CatalogHandler) CreateProgram(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *CatalogHandler) PublishProgram(w http.ResponseWriter, r *http.Request) {
-	if auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
 	pid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
@@ -107,11 +103,6 @@ type createCohortReq struct {
 }
 
 func (h *CatalogHandler) CreateCohort(w http.ResponseWriter, r *http.Request) {
-	if auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
 	var req createCohortReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "bad json", http.StatusBadRequest)
@@ -147,13 +138,13 @@ type createGroupReq struct {
 	Capacity          int    `json:"capacity" validate:"required"`
 	RequiresInterview bool   `json:"requires_interview"`
 	IsOpen            bool   `json:"is_open"`
+
+	// окно приёма (RFC3339); не задано — действует окно потока
+	OpensAt  *time.Time `json:"opens_at"`
+	ClosesAt *time.Time `json:"closes_at"`
 }
 
 func (h *CatalogHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
-	if auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
 	var req createGroupReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "bad json", http.StatusBadRequest)
@@ -163,9 +154,13 @@ func (h *CatalogHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if !validWindow(req.OpensAt, req.ClosesAt) {
+		http.Error(w, "opens_at must be before closes_at", http.StatusBadRequest)
+		return
+	}
 	pid, _ := uuid.Parse(req.ProgramID)
 	cid, _ := uuid.Parse(req.CohortID)
-	id, err := h.catalog.CreateGroup(r.Context(), pid, cid, req.Title, req.Capacity, req.RequiresInterview, req.IsOpen)
+	id, err := h.catalog.CreateGroup(r.Context(), pid, cid, req.Title, req.Capacity, req.RequiresInterview, req.IsOpen, req.OpensAt, req.ClosesAt)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -174,10 +169,6 @@ func (h *CatalogHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *CatalogHandler) AssignTeacher(w http.ResponseWriter, r *http.Request) {
-	if auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
 	gid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid group id", http.StatusBadRequest)
@@ -201,11 +192,6 @@ func (h *CatalogHandler) AssignTeacher(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *CatalogHandler) CloseGroup(w http.ResponseWriter, r *http.Request) {
-	if auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
 	gid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid group id", http.StatusBadRequest)
@@ -220,12 +206,6 @@ func (h *CatalogHandler) CloseGroup(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *CatalogHandler) ListProgramsAdmin(w http.ResponseWriter, r *http.Request) {
-	role := auth.Role(r.Context())
-	if role != "admin" && role != "moderator" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
 	ps, err := h.catalog.ListAllPrograms(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -235,12 +215,6 @@ func (h *CatalogHandler) ListProgramsAdmin(w http.ResponseWriter, r *http.Reques
 }
 
 func (h *CatalogHandler) GetProgramAdmin(w http.ResponseWriter, r *http.Request) {
-	role := auth.Role(r.Context())
-	if role != "admin" && role != "moderator" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
 	pid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
@@ -267,12 +241,6 @@ func (h *CatalogHandler) GetProgramAdmin(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *CatalogHandler) GetGroupTeachers(w http.ResponseWriter, r *http.Request) {
-	role := auth.Role(r.Context())
-	if role != "admin" && role != "moderator" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
 	gid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid group id", http.StatusBadRequest)
@@ -292,11 +260,6 @@ func (h *CatalogHandler) GetGroupTeachers(w http.ResponseWriter, r *http.Request
 }
 
 func (h *CatalogHandler) RemoveTeacher(w http.ResponseWriter, r *http.Request) {
-	if auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
 	gid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid group id", http.StatusBadRequest)
@@ -326,14 +289,13 @@ type updateGroupReq struct {
 	Capacity          *int    `json:"capacity"`
 	IsOpen            *bool   `json:"is_open"`
 	RequiresInterview *bool   `json:"requires_interview"`
+
+	OpensAt     *time.Time `json:"opens_at"`
+	ClosesAt    *time.Time `json:"closes_at"`
+	ClearWindow bool       `json:"clear_window"` // убрать окно группы (останется окно потока)
 }
 
 func (h *CatalogHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
-	if auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
 	gid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid group id", http.StatusBadRequest)
@@ -346,24 +308,112 @@ func (h *CatalogHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.ClearWindow || req.OpensAt != nil || req.ClosesAt != nil {
+		opensAt, closesAt, err := h.catalog.GetGroupWindow(r.Context(), gid)
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "group not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		opensAt, closesAt = mergeWindow(opensAt, closesAt, req.OpensAt, req.ClosesAt, req.ClearWindow)
+		if !validWindow(opensAt, closesAt) {
+			http.Error(w, "opens_at must be before closes_at", http.StatusBadRequest)
+			return
+		}
+		if err := h.catalog.SetGroupWindow(r.Context(), gid, opensAt, closesAt); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
 	if err := h.catalog.UpdateGroup(r.Context(), gid, req.Title, req.Capacity, req.IsOpen, req.RequiresInterview); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	// мест стало больше — зачисляем из листа ожидания
+	if req.Capacity != nil {
+		if _, err := h.apps.PromoteGroup(r.Context(), gid); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+type updateCohortReq struct {
+	OpensAt     *time.Time `json:"opens_at"`
+	ClosesAt    *time.Time `json:"closes_at"`
+	ClearWindow bool       `json:"clear_window"`
+}
+
+// PATCH /admin/cohorts/{id} — окно приёма по умолчанию для групп потока
+func (h *CatalogHandler) UpdateCohort(w http.ResponseWriter, r *http.Request) {
+	cid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	var req updateCohortReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if !req.ClearWindow && req.OpensAt == nil && req.ClosesAt == nil {
+		http.Error(w, "nothing to update", http.StatusBadRequest)
+		return
+	}
+
+	opensAt, closesAt, err := h.catalog.GetCohortWindow(r.Context(), cid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "cohort not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	opensAt, closesAt = mergeWindow(opensAt, closesAt, req.OpensAt, req.ClosesAt, req.ClearWindow)
+	if !validWindow(opensAt, closesAt) {
+		http.Error(w, "opens_at must be before closes_at", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.catalog.SetCohortWindow(r.Context(), cid, opensAt, closesAt); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// mergeWindow: clear сбрасывает окно, затем применяются переданные границы
+func mergeWindow(opensAt, closesAt, newOpens, newCloses *time.Time, clear bool) (*time.Time, *time.Time) {
+	if clear {
+		opensAt, closesAt = nil, nil
+	}
+	if newOpens != nil {
+		opensAt = newOpens
+	}
+	if newCloses != nil {
+		closesAt = newCloses
+	}
+	return opensAt, closesAt
+}
+
+func validWindow(opensAt, closesAt *time.Time) bool {
+	return opensAt == nil || closesAt == nil || opensAt.Before(*closesAt)
+}
+
 type updateProgramReq struct {
 	Title       *string `json:"title"`
 	Description *string `json:"description"`
 }
 
 func (h *CatalogHandler) UpdateProgram(w http.ResponseWriter, r *http.Request) {
-	if auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
 	pid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid id", http.StatusBadRequest)
@@ -0,0 +1,230 @@
+// internal/httpapi/handlers_eligibility.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+	"github.com/Pavlushechko/itcube-education/internal/service"
+)
+
+// EligibilityHandler: условия допуска (возраст, пройденные программы, лимит зачислений)
+type EligibilityHandler struct {
+	v       *validator.Validate
+	rules   *repo.EligibilityRepo
+	catalog *repo.CatalogRepo
+	svc     *service.EligibilityService
+}
+
+func NewEligibilityHandler(rules *repo.EligibilityRepo, catalog *repo.CatalogRepo, svc *service.EligibilityService) *EligibilityHandler {
+	return &EligibilityHandler{v: validator.New(), rules: rules, catalog: catalog, svc: svc}
+}
+
+type eligibilityReq struct {
+	MinAge               *int     `json:"min_age"`
+	MaxAge               *int     `json:"max_age"`
+	RequiredProgramIDs   []string `json:"required_program_ids" validate:"dive,uuid"`
+	MaxActiveEnrollments *int     `json:"max_active_enrollments"`
+}
+
+func (h *EligibilityHandler) decode(w http.ResponseWriter, r *http.Request) (domain.EligibilityRule, bool) {
+	uid, ok := auth.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return domain.EligibilityRule{}, false
+	}
+	var req eligibilityReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return domain.EligibilityRule{}, false
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return domain.EligibilityRule{}, false
+	}
+
+	rule := domain.EligibilityRule{
+		MinAge:               req.MinAge,
+		MaxAge:               req.MaxAge,
+		MaxActiveEnrollments: req.MaxActiveEnrollments,
+		UpdatedBy:            uid,
+	}
+	for _, v := range req.RequiredProgramIDs {
+		id, _ := uuid.Parse(v)
+		rule.RequiredProgramIDs = append(rule.RequiredProgramIDs, id)
+	}
+	return rule, true
+}
+
+// GET /catalog/programs/{id}/eligibility (?child_id=... — guardian)
+func (h *EligibilityHandler) ForProgram(w http.ResponseWriter, r *http.Request) {
+	pid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.ForProgram(r.Context(), pid)
+	if err != nil {
+		writeEligibilityError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// GET /admin/programs/{id}/eligibility
+func (h *EligibilityHandler) GetForProgram(w http.ResponseWriter, r *http.Request) {
+	pid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	rule, ok, err := h.rules.GetByProgram(r.Context(), pid)
+	if err != nil {
+		writeEligibilityError(w, err)
+		return
+	}
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// PUT /admin/programs/{id}/eligibility — полная замена правила
+func (h *EligibilityHandler) PutForProgram(w http.ResponseWriter, r *http.Request) {
+	pid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	rule, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	rule.ProgramID = &pid
+	if err := rule.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := h.catalog.GetProgram(r.Context(), pid); err != nil {
+		writeEligibilityError(w, err)
+		return
+	}
+
+	if err := h.rules.UpsertForProgram(r.Context(), pid, rule); err != nil {
+		writeEligibilityError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /admin/programs/{id}/eligibility
+func (h *EligibilityHandler) DeleteForProgram(w http.ResponseWriter, r *http.Request) {
+	pid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	found, err := h.rules.DeleteForProgram(r.Context(), pid)
+	if err != nil {
+		writeEligibilityError(w, err)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /admin/groups/{id}/eligibility — только своё правило группы
+func (h *EligibilityHandler) GetForGroup(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	rule, ok, err := h.rules.GetByGroup(r.Context(), gid)
+	if err != nil {
+		writeEligibilityError(w, err)
+		return
+	}
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// PUT /admin/groups/{id}/eligibility — правило группы заменяет правило программы
+func (h *EligibilityHandler) PutForGroup(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	rule, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	pid, err := h.catalog.GetGroupProgramID(r.Context(), gid)
+	if err != nil {
+		writeEligibilityError(w, err)
+		return
+	}
+	rule.GroupID = &gid
+	// программа группы не может быть собственным требованием
+	rule.ProgramID = &pid
+	err = rule.Validate()
+	rule.ProgramID = nil
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.rules.UpsertForGroup(r.Context(), gid, rule); err != nil {
+		writeEligibilityError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /admin/groups/{id}/eligibility
+func (h *EligibilityHandler) DeleteForGroup(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	found, err := h.rules.DeleteForGroup(r.Context(), gid)
+	if err != nil {
+		writeEligibilityError(w, err)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeEligibilityError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "unauthorized":
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,217 @@
+// internal/httpapi/handlers_enrollments.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/service"
+)
+
+type EnrollmentHandler struct {
+	v   *validator.Validate
+	svc *service.EnrollmentService
+}
+
+func NewEnrollmentHandler(svc *service.EnrollmentService) *EnrollmentHandler {
+	return &EnrollmentHandler{v: validator.New(), svc: svc}
+}
+
+// GET /me/enrollments (?child_id=... — guardian)
+func (h *EnrollmentHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.ListMine(r.Context())
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	if items == nil {
+		items = []domain.Enrollment{}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+type withdrawReq struct {
+	Reason string `json:"reason" validate:"max=1000"`
+}
+
+// POST /me/enrollments/{id}/withdraw — ученик или guardian
+func (h *EnrollmentHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var req withdrawReq
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad json", http.StatusBadRequest)
+			return
+		}
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.Withdraw(r.Context(), id, req.Reason); err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type enrollmentStatusReq struct {
+	Status string `json:"status" validate:"required,oneof=withdrawn expelled completed"`
+	Reason string `json:"reason" validate:"max=1000"`
+}
+
+// POST /admin/enrollments/{id}/status
+func (h *EnrollmentHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var req enrollmentStatusReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.ChangeStatus(r.Context(), id, domain.EnrollmentStatus(req.Status), req.Reason); err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type transferReq struct {
+	ToGroupID     string `json:"to_group_id" validate:"required,uuid"`
+	CarryProgress bool   `json:"carry_progress"`
+	Reason        string `json:"reason" validate:"max=1000"`
+	Readmit       bool   `json:"readmit"`
+}
+
+// POST /admin/enrollments/{id}/transfer
+func (h *EnrollmentHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	var req transferReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	gid, _ := uuid.Parse(req.ToGroupID)
+
+	t, err := h.svc.Transfer(r.Context(), id, service.TransferInput{
+		ToGroupID:     gid,
+		CarryProgress: req.CarryProgress,
+		Reason:        req.Reason,
+		Readmit:       req.Readmit,
+	})
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, t)
+}
+
+// GET /admin/enrollments/{id}/transfers
+func (h *EnrollmentHandler) Transfers(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	items, err := h.svc.Transfers(r.Context(), id)
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	if items == nil {
+		items = []domain.EnrollmentTransfer{}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// GET /admin/enrollments/{id}/history
+func (h *EnrollmentHandler) History(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	items, err := h.svc.History(r.Context(), id)
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	if items == nil {
+		items = []domain.EnrollmentAudit{}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// GET /admin/groups/{id}/enrollments?status=active|withdrawn|expelled|completed|transferred
+func (h *EnrollmentHandler) ListByGroup(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+	var status *string
+	if v := r.URL.Query().Get("status"); v != "" {
+		status = &v
+	}
+
+	items, err := h.svc.ListByGroup(r.Context(), gid, status)
+	if err != nil {
+		writeEnrollmentError(w, err)
+		return
+	}
+	if items == nil {
+		items = []domain.Enrollment{}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func writeEnrollmentError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "unauthorized":
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, authz.ErrForbidden), errors.Is(err, authz.ErrUnauthorized):
+		authz.WriteError(w, err)
+	case errors.Is(err, service.ErrEnrollmentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrFinalStatus), errors.Is(err, domain.ErrReasonRequired),
+		errors.Is(err, service.ErrSameGroup), errors.Is(err, service.ErrOtherProgram):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrNoSeats), errors.Is(err, service.ErrGroupClosed), errors.Is(err, service.ErrAlreadyEnrolled),
+		errors.Is(err, domain.ErrExpelled):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
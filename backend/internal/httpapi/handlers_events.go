@@ -0,0 +1,143 @@
+// internal/httpapi/handlers_events.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/events"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+type EventsHandler struct {
+	hub       *events.Hub
+	outbox    *outbox.Repo
+	catalog   *repo.CatalogRepo
+	apps      *repo.ApplicationRepo
+	guardians *repo.GuardianRepo
+}
+
+func NewEventsHandler(hub *events.Hub, outbox *outbox.Repo, catalog *repo.CatalogRepo, apps *repo.ApplicationRepo, guardians *repo.GuardianRepo) *EventsHandler {
+	return &EventsHandler{hub: hub, outbox: outbox, catalog: catalog, apps: apps, guardians: guardians}
+}
+
+// то, что уходит в data: события
+type streamEvent struct {
+	ID            string          `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Version       int             `json:"version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+const backlogLimit = 1000
+
+// GET /events/stream — Server-Sent Events; id: = seq, переподключение с Last-Event-ID досылает пропущенное.
+// EventSource не умеет заголовки — токен можно передать в ?access_token= (см. auth.Middleware).
+func (h *EventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	scope, err := events.NewScope(ctx, h.catalog, h.apps, h.guardians)
+	if err != nil {
+		if errors.Is(err, authz.ErrUnauthorized) {
+			authz.WriteError(w, err)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	var lastID int64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		lastID = n
+	}
+
+	// подписываемся до чтения backlog, чтобы ничего не потерять между ними
+	sub := h.hub.Subscribe()
+	defer h.hub.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	// backlog страницами, пока не догоним: иначе при отставании больше страницы середина потеряется.
+	// lastID после цикла — последний отданный seq, живые события с меньшим seq уже были в backlog.
+	for lastID > 0 {
+		page, err := h.outbox.ListSince(ctx, lastID, backlogLimit)
+		if err != nil {
+			// заголовки уже ушли — обрываем поток, клиент переподключится с Last-Event-ID
+			return
+		}
+		for _, e := range page {
+			m := events.NewMessage(e)
+			lastID = e.Seq
+			if scope.Allows(m) {
+				writeSSE(w, m)
+			}
+		}
+		flusher.Flush()
+		if len(page) < backlogLimit {
+			break
+		}
+	}
+	flusher.Flush()
+
+	ping := time.NewTicker(15 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case m, ok := <-sub.C:
+			if !ok {
+				// отстали — клиент переподключится с Last-Event-ID
+				return
+			}
+			if m.Seq <= lastID || !scope.Allows(m) {
+				continue
+			}
+			writeSSE(w, m)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, m events.Message) {
+	b, _ := json.Marshal(streamEvent{
+		ID:            m.ID.String(),
+		AggregateType: m.AggregateType,
+		AggregateID:   m.AggregateID.String(),
+		EventType:     m.EventType,
+		Version:       m.Version,
+		CreatedAt:     m.CreatedAt,
+		Payload:       m.Payload,
+	})
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", m.Seq, m.EventType, b)
+}
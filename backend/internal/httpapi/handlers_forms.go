@@ -0,0 +1,259 @@
+// internal/httpapi/handlers_forms.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+// FormHandler: анкеты заявок (на программу или на конкретную группу)
+type FormHandler struct {
+	v       *validator.Validate
+	forms   *repo.FormRepo
+	catalog *repo.CatalogRepo
+}
+
+func NewFormHandler(forms *repo.FormRepo, catalog *repo.CatalogRepo) *FormHandler {
+	return &FormHandler{v: validator.New(), forms: forms, catalog: catalog}
+}
+
+type questionReq struct {
+	Key       string   `json:"key" validate:"required"`
+	Label     string   `json:"label" validate:"required"`
+	Type      string   `json:"type" validate:"required,oneof=text single_choice multi_choice number date file"`
+	Required  bool     `json:"required"`
+	Options   []string `json:"options"`
+	MinLength *int     `json:"min_length" validate:"omitempty,min=0"`
+	MaxLength *int     `json:"max_length" validate:"omitempty,min=1"`
+	Pattern   string   `json:"pattern"`
+	Min       *float64 `json:"min"`
+	Max       *float64 `json:"max"`
+	Integer   bool     `json:"integer"`
+	MinDate   string   `json:"min_date"`
+	MaxDate   string   `json:"max_date"`
+}
+
+type formReq struct {
+	Questions []questionReq `json:"questions" validate:"dive"`
+}
+
+func (req formReq) toQuestions() []domain.Question {
+	qs := make([]domain.Question, 0, len(req.Questions))
+	for _, q := range req.Questions {
+		qs = append(qs, domain.Question{
+			Key:       q.Key,
+			Label:     q.Label,
+			Type:      domain.QuestionType(q.Type),
+			Required:  q.Required,
+			Options:   q.Options,
+			MinLength: q.MinLength,
+			MaxLength: q.MaxLength,
+			Pattern:   q.Pattern,
+			Min:       q.Min,
+			Max:       q.Max,
+			Integer:   q.Integer,
+			MinDate:   q.MinDate,
+			MaxDate:   q.MaxDate,
+		})
+	}
+	return qs
+}
+
+func (h *FormHandler) decode(w http.ResponseWriter, r *http.Request) ([]domain.Question, bool) {
+	var req formReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	qs := req.toQuestions()
+	if err := (domain.ApplicationForm{Questions: qs}).Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return qs, true
+}
+
+// GET /catalog/groups/{id}/form — анкета, которую нужно заполнить при подаче заявки в группу
+func (h *FormHandler) GetEffective(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	published, _, err := h.catalog.IsGroupAvailableForApply(r.Context(), gid)
+	if err != nil {
+		writeFormError(w, err)
+		return
+	}
+	if !published {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	f, ok, err := h.forms.GetEffective(r.Context(), gid)
+	if err != nil {
+		writeFormError(w, err)
+		return
+	}
+	if !ok {
+		// анкеты нет — заявка подаётся без ответов
+		f = domain.ApplicationForm{Questions: []domain.Question{}}
+	}
+	writeJSON(w, http.StatusOK, f)
+}
+
+// GET /admin/programs/{id}/form
+func (h *FormHandler) GetForProgram(w http.ResponseWriter, r *http.Request) {
+	pid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	f, ok, err := h.forms.GetByProgram(r.Context(), pid)
+	if err != nil {
+		writeFormError(w, err)
+		return
+	}
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, f)
+}
+
+// PUT /admin/programs/{id}/form — полная замена вопросов
+func (h *FormHandler) PutForProgram(w http.ResponseWriter, r *http.Request) {
+	uid, ok := auth.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	pid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	qs, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	if _, err := h.catalog.GetProgram(r.Context(), pid); err != nil {
+		writeFormError(w, err)
+		return
+	}
+
+	if err := h.forms.UpsertForProgram(r.Context(), pid, qs, uid); err != nil {
+		writeFormError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /admin/programs/{id}/form
+func (h *FormHandler) DeleteForProgram(w http.ResponseWriter, r *http.Request) {
+	pid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	found, err := h.forms.DeleteForProgram(r.Context(), pid)
+	if err != nil {
+		writeFormError(w, err)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /admin/groups/{id}/form — только своя анкета группы (без анкеты программы)
+func (h *FormHandler) GetForGroup(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	f, ok, err := h.forms.GetByGroup(r.Context(), gid)
+	if err != nil {
+		writeFormError(w, err)
+		return
+	}
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, f)
+}
+
+// PUT /admin/groups/{id}/form — анкета группы заменяет анкету программы для этой группы
+func (h *FormHandler) PutForGroup(w http.ResponseWriter, r *http.Request) {
+	uid, ok := auth.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	qs, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	if _, err := h.catalog.GetGroupProgramID(r.Context(), gid); err != nil {
+		writeFormError(w, err)
+		return
+	}
+
+	if err := h.forms.UpsertForGroup(r.Context(), gid, qs, uid); err != nil {
+		writeFormError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /admin/groups/{id}/form
+func (h *FormHandler) DeleteForGroup(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	found, err := h.forms.DeleteForGroup(r.Context(), gid)
+	if err != nil {
+		writeFormError(w, err)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeFormError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
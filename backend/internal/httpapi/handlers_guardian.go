@@ -0,0 +1,149 @@
+// internal/httpapi/handlers_guardian.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/service"
+)
+
+type GuardianHandler struct {
+	v   *validator.Validate
+	svc *service.GuardianService
+}
+
+func NewGuardianHandler(svc *service.GuardianService) *GuardianHandler {
+	return &GuardianHandler{v: validator.New(), svc: svc}
+}
+
+func (h *GuardianHandler) ListChildren(w http.ResponseWriter, r *http.Request) {
+	cs, err := h.svc.ListChildren(r.Context())
+	if err != nil {
+		writeGuardianError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, cs)
+}
+
+// POST /me/children — тело как у /me/profile
+func (h *GuardianHandler) CreateChild(w http.ResponseWriter, r *http.Request) {
+	if _, ok := auth.UserID(r.Context()); !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req profileReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p, err := req.toProfile(uuid.Nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.svc.CreateChild(r.Context(), p)
+	if err != nil {
+		writeGuardianError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"id": id.String()})
+}
+
+func (h *GuardianHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	cid, err := uuid.Parse(chi.URLParam(r, "childID"))
+	if err != nil {
+		http.Error(w, "invalid child id", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Unlink(r.Context(), cid); err != nil {
+		writeGuardianError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GuardianHandler) ChildApplications(w http.ResponseWriter, r *http.Request) {
+	cid, err := uuid.Parse(chi.URLParam(r, "childID"))
+	if err != nil {
+		http.Error(w, "invalid child id", http.StatusBadRequest)
+		return
+	}
+	apps, err := h.svc.ChildApplications(r.Context(), cid)
+	if err != nil {
+		writeGuardianError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apps)
+}
+
+// POST /admin/guardians/{guardianID}/children/{childID}
+func (h *GuardianHandler) AdminLink(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "guardianID"))
+	if err != nil {
+		http.Error(w, "invalid guardian id", http.StatusBadRequest)
+		return
+	}
+	cid, err := uuid.Parse(chi.URLParam(r, "childID"))
+	if err != nil {
+		http.Error(w, "invalid child id", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.LinkExisting(r.Context(), gid, cid); err != nil {
+		writeGuardianError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LearnerScope: ?child_id=... на /learn — guardian читает данные ребёнка.
+// Только GET: сдавать задания и отмечать материалы за ребёнка нельзя.
+func (h *GuardianHandler) LearnerScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := r.URL.Query().Get("child_id")
+		if v == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cid, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "invalid child_id", http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "guardian access is read-only", http.StatusForbidden)
+			return
+		}
+		if err := h.svc.EnsureGuardian(r.Context(), cid); err != nil {
+			writeGuardianError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithLearner(r.Context(), cid)))
+	})
+}
+
+func writeGuardianError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "unauthorized":
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, service.ErrNotGuardian), err.Error() == "forbidden":
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrLastGuardian):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,178 @@
+// internal/httpapi/handlers_interview_slots.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/service"
+)
+
+// InterviewSlotHandler: слоты собеседований (преподаватель публикует, заявитель бронирует)
+type InterviewSlotHandler struct {
+	v   *validator.Validate
+	svc *service.InterviewSlotService
+}
+
+func NewInterviewSlotHandler(svc *service.InterviewSlotService) *InterviewSlotHandler {
+	return &InterviewSlotHandler{v: validator.New(), svc: svc}
+}
+
+type createSlotReq struct {
+	StartsAt  time.Time `json:"starts_at" validate:"required"`
+	EndsAt    time.Time `json:"ends_at" validate:"required"`
+	Location  string    `json:"location" validate:"max=500"`
+	OnlineURL string    `json:"online_url" validate:"max=1000"`
+}
+
+// POST /teacher/groups/{id}/interview-slots
+func (h *InterviewSlotHandler) Create(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+	var req createSlotReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	slot, err := h.svc.CreateSlot(r.Context(), gid, domain.InterviewSlot{
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		Location:  req.Location,
+		OnlineURL: req.OnlineURL,
+	})
+	if err != nil {
+		writeInterviewSlotError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, slot)
+}
+
+// GET /teacher/groups/{id}/interview-slots?from=RFC3339 — по умолчанию с текущего момента
+func (h *InterviewSlotHandler) ListForGroup(w http.ResponseWriter, r *http.Request) {
+	gid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid group id", http.StatusBadRequest)
+		return
+	}
+	from := time.Now()
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, "invalid from", http.StatusBadRequest)
+			return
+		}
+	}
+	slots, err := h.svc.ListForGroup(r.Context(), gid, from)
+	if err != nil {
+		writeInterviewSlotError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, slots)
+}
+
+// DELETE /teacher/interview-slots/{slotID}
+func (h *InterviewSlotHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	slotID, err := uuid.Parse(chi.URLParam(r, "slotID"))
+	if err != nil {
+		http.Error(w, "invalid slot id", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.DeleteSlot(r.Context(), slotID); err != nil {
+		writeInterviewSlotError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /enrollments/applications/{id}/interview-slots — своя бронь и свободные слоты
+func (h *InterviewSlotHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.ListForApplicant(r.Context(), appID)
+	if err != nil {
+		writeInterviewSlotError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+type bookSlotReq struct {
+	SlotID uuid.UUID `json:"slot_id" validate:"required"`
+}
+
+// PUT /enrollments/applications/{id}/interview-slot — бронь или перенос на другой слот
+func (h *InterviewSlotHandler) Book(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	var req bookSlotReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	slot, err := h.svc.Book(r.Context(), appID, req.SlotID)
+	if err != nil {
+		writeInterviewSlotError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, slot)
+}
+
+// DELETE /enrollments/applications/{id}/interview-slot
+func (h *InterviewSlotHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid app id", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.Cancel(r.Context(), appID); err != nil {
+		writeInterviewSlotError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeInterviewSlotError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "unauthorized":
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, authz.ErrForbidden), errors.Is(err, authz.ErrUnauthorized):
+		authz.WriteError(w, err)
+	case errors.Is(err, service.ErrNotGuardian):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrInvalidSlot), errors.Is(err, service.ErrSlotInPast):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrSlotOverlap), errors.Is(err, service.ErrSlotTaken), errors.Is(err, service.ErrSlotStarted),
+		errors.Is(err, service.ErrSlotBookingClosed), errors.Is(err, service.ErrInterviewDecided):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,61 @@
+// internal/httpapi/handlers_outbox.go
+
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+)
+
+type OutboxHandler struct {
+	outbox *outbox.Repo
+}
+
+func NewOutboxHandler(outbox *outbox.Repo) *OutboxHandler {
+	return &OutboxHandler{outbox: outbox}
+}
+
+// GET /admin/outbox/dead-letters?limit=100
+func (h *OutboxHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	items, err := h.outbox.ListDeadLetters(r.Context(), limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// POST /admin/outbox/dead-letters/{id}/replay — вернуть событие в очередь доставки
+func (h *OutboxHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	ok, err := h.outbox.Replay(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
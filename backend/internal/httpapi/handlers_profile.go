@@ -0,0 +1,108 @@
+// internal/httpapi/handlers_profile.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+var errInvalidBirthDate = errors.New("invalid birth_date, expected YYYY-MM-DD in the past")
+
+type ProfileHandler struct {
+	v        *validator.Validate
+	profiles *repo.ProfileRepo
+}
+
+func NewProfileHandler(profiles *repo.ProfileRepo) *ProfileHandler {
+	return &ProfileHandler{v: validator.New(), profiles: profiles}
+}
+
+// GET /me/profile — пустой профиль, если ещё не заполнен
+func (h *ProfileHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	uid, ok := auth.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	p, found, err := h.profiles.Get(r.Context(), uid)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		p = domain.Profile{UserID: uid}
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+type profileReq struct {
+	FullName     string  `json:"full_name" validate:"required,max=200"`
+	BirthDate    *string `json:"birth_date"` // YYYY-MM-DD
+	School       string  `json:"school" validate:"max=200"`
+	Grade        *int    `json:"grade" validate:"omitempty,min=1,max=11"`
+	ContactEmail string  `json:"contact_email" validate:"omitempty,email"`
+	ContactPhone string  `json:"contact_phone" validate:"max=32"`
+}
+
+// PUT /me/profile — полная замена
+func (h *ProfileHandler) PutMine(w http.ResponseWriter, r *http.Request) {
+	uid, ok := auth.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req profileReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p, err := req.toProfile(uid)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.profiles.Upsert(r.Context(), p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (req profileReq) toProfile(uid uuid.UUID) (domain.Profile, error) {
+	p := domain.Profile{
+		UserID:       uid,
+		FullName:     strings.TrimSpace(req.FullName),
+		School:       strings.TrimSpace(req.School),
+		Grade:        req.Grade,
+		ContactEmail: strings.TrimSpace(req.ContactEmail),
+		ContactPhone: strings.TrimSpace(req.ContactPhone),
+	}
+	if req.BirthDate != nil && *req.BirthDate != "" {
+		t, err := time.Parse(time.DateOnly, *req.BirthDate)
+		if err != nil {
+			return domain.Profile{}, errInvalidBirthDate
+		}
+		if t.After(time.Now()) {
+			return domain.Profile{}, errInvalidBirthDate
+		}
+		p.BirthDate = &t
+	}
+	return p, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
 	"github.com/Pavlushechko/itcube-education/internal/domain"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
 )
@@ -43,8 +44,7 @@ func (h *ProgramHandler) GetProgramPrivate(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	role := auth.Role(r.Context())
-	isStaff := role == "admin" || role == "moderator"
+	isStaff := authz.Can(r.Context(), authz.CatalogReadAll)
 
 	p, err := h.catalog.GetProgram(r.Context(), pid)
 	if err != nil {
@@ -0,0 +1,191 @@
+// internal/httpapi/handlers_rules.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+	"github.com/Pavlushechko/itcube-education/internal/rules"
+)
+
+type RuleHandler struct {
+	v     *validator.Validate
+	rules *repo.RuleRepo
+}
+
+func NewRuleHandler(rules *repo.RuleRepo) *RuleHandler {
+	return &RuleHandler{v: validator.New(), rules: rules}
+}
+
+// пример: {"name":"auto review","event_type":"application.created","action":"set_application_status","action_params":{"to":"in_review"}}
+type ruleReq struct {
+	Name         string                 `json:"name" validate:"required,max=200"`
+	EventType    string                 `json:"event_type" validate:"required,max=100"`
+	Conditions   []domain.RuleCondition `json:"conditions"`
+	Action       string                 `json:"action" validate:"required"`
+	ActionParams map[string]any         `json:"action_params"`
+	IsEnabled    *bool                  `json:"is_enabled"` // по умолчанию true
+}
+
+func (req ruleReq) toRule(id uuid.UUID) domain.Rule {
+	ru := domain.Rule{
+		ID:           id,
+		Name:         req.Name,
+		EventType:    req.EventType,
+		Conditions:   req.Conditions,
+		Action:       domain.RuleAction(req.Action),
+		ActionParams: req.ActionParams,
+		IsEnabled:    true,
+	}
+	if ru.Conditions == nil {
+		ru.Conditions = []domain.RuleCondition{}
+	}
+	if ru.ActionParams == nil {
+		ru.ActionParams = map[string]any{}
+	}
+	if req.IsEnabled != nil {
+		ru.IsEnabled = *req.IsEnabled
+	}
+	return ru
+}
+
+func (h *RuleHandler) decode(w http.ResponseWriter, r *http.Request, id uuid.UUID) (domain.Rule, bool) {
+	var req ruleReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return domain.Rule{}, false
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return domain.Rule{}, false
+	}
+	ru := req.toRule(id)
+	if err := rules.Validate(ru); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return domain.Rule{}, false
+	}
+	return ru, true
+}
+
+func (h *RuleHandler) List(w http.ResponseWriter, r *http.Request) {
+	rs, err := h.rules.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, rs)
+}
+
+func (h *RuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	uid, ok := auth.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ru, ok := h.decode(w, r, uuid.New())
+	if !ok {
+		return
+	}
+	ru.CreatedBy = uid
+
+	if err := h.rules.Create(r.Context(), ru); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"id": ru.ID.String()})
+}
+
+func (h *RuleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	ru, found, err := h.rules.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, ru)
+}
+
+// PUT /admin/rules/{id} — полная замена
+func (h *RuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	ru, ok := h.decode(w, r, id)
+	if !ok {
+		return
+	}
+
+	found, err := h.rules.Update(r.Context(), ru)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	found, err := h.rules.Delete(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /admin/rules/{id}/executions?limit=100
+func (h *RuleHandler) Executions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	items, err := h.rules.ListExecutions(r.Context(), id, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
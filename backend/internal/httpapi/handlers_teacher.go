@@ -4,14 +4,15 @@ package httpapi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
 	"github.com/Pavlushechko/itcube-education/internal/domain"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
 	"github.com/Pavlushechko/itcube-education/internal/service"
@@ -22,14 +23,16 @@ type TeacherHandler struct {
 	catalog    *repo.CatalogRepo
 	appRepo    *repo.ApplicationRepo
 	interviews *service.InterviewService
+	az         *authz.Authorizer
 }
 
-func NewTeacherHandler(catalog *repo.CatalogRepo, appRepo *repo.ApplicationRepo, invSvc *service.InterviewService) *TeacherHandler {
+func NewTeacherHandler(catalog *repo.CatalogRepo, appRepo *repo.ApplicationRepo, invSvc *service.InterviewService, az *authz.Authorizer) *TeacherHandler {
 	return &TeacherHandler{
 		v:          validator.New(),
 		catalog:    catalog,
 		appRepo:    appRepo,
 		interviews: invSvc,
+		az:         az,
 	}
 }
 
@@ -49,25 +52,13 @@ func (h *TeacherHandler) MyGroups(w http.ResponseWriter, r *http.Request) {
 
 // list applications for group (e.g. in_review)
 func (h *TeacherHandler) GroupApplications(w http.ResponseWriter, r *http.Request) {
-	teacherID, ok := auth.UserID(r.Context())
-	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	gid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid group id", http.StatusBadRequest)
 		return
 	}
 
-	assigned, err := h.catalog.IsTeacherInGroup(r.Context(), gid, teacherID)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if !assigned && auth.Role(r.Context()) != "admin" {
-		http.Error(w, "forbidden", http.StatusForbidden)
+	if !h.authorizeGroup(w, r, authz.ApplicationList, gid) {
 		return
 	}
 
@@ -79,7 +70,7 @@ func (h *TeacherHandler) GroupApplications(w http.ResponseWriter, r *http.Reques
 	status := &statusVal
 
 	// year фильтр для teacher-группы обычно не нужен, но сигнатура требует — передаём nil
-	apps, err := h.appRepo.ListByFilterView(r.Context(), &gid, status, nil)
+	apps, err := h.appRepo.ListByFilterView(r.Context(), &gid, status, nil, nil)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -114,7 +105,7 @@ func (h *TeacherHandler) RecordInterview(w http.ResponseWriter, r *http.Request)
 	res := domain.InterviewResult(req.Result)
 	if err := h.interviews.Record(r.Context(), appID, res, req.Comment); err != nil {
 		msg := err.Error()
-		if strings.Contains(msg, "teacher is not assigned") || strings.Contains(msg, "forbidden") {
+		if errors.Is(err, authz.ErrForbidden) {
 			http.Error(w, msg, http.StatusForbidden)
 			return
 		}
@@ -125,44 +116,26 @@ func (h *TeacherHandler) RecordInterview(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *TeacherHandler) GroupStudents(w http.ResponseWriter, r *http.Request) {
-	actorID, ok := auth.UserID(r.Context())
-	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
-		return
-	}
-	role := auth.Role(r.Context())
-
 	gid, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, "invalid group id", http.StatusBadRequest)
 		return
 	}
 
-	// Доступ:
-	// - admin всегда
-	// - иначе только если пользователь назначен преподавателем этой группы
-	if role != "admin" {
-		assigned, err := h.catalog.IsTeacherInGroup(r.Context(), gid, actorID)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		if !assigned {
-			http.Error(w, "forbidden", http.StatusForbidden)
-			return
-		}
+	// Доступ: staff или назначенный преподаватель этой группы
+	if !h.authorizeGroup(w, r, authz.GroupStudentsRead, gid) {
+		return
 	}
 
-	users, err := h.appRepo.ListEnrolledUsersByGroup(r.Context(), gid)
+	students, err := h.appRepo.ListEnrolledStudentsByGroup(r.Context(), gid)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// отдаём просто список uuid (можно и объектами, но так проще)
 	writeJSON(w, http.StatusOK, map[string]any{
 		"group_id": gid.String(),
-		"students": users,
+		"students": students,
 	})
 }
 
@@ -188,3 +161,17 @@ func (h *TeacherHandler) ProgramAccess(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, map[string]any{"ok": allowed})
 }
+
+// authorizeGroup пишет 401/403/500 сам; false -> ответ уже отправлен
+func (h *TeacherHandler) authorizeGroup(w http.ResponseWriter, r *http.Request, p authz.Permission, groupID uuid.UUID) bool {
+	err := h.az.RequireInGroup(r.Context(), p, groupID)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, authz.ErrUnauthorized), errors.Is(err, authz.ErrForbidden):
+		authz.WriteError(w, err)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+// internal/httpapi/handlers_webhooks.go
+
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+	"github.com/Pavlushechko/itcube-education/internal/webhook"
+)
+
+type WebhookHandler struct {
+	v         *validator.Validate
+	webhooks  *repo.WebhookRepo
+	deliverer *webhook.Deliverer
+}
+
+func NewWebhookHandler(webhooks *repo.WebhookRepo, deliverer *webhook.Deliverer) *WebhookHandler {
+	return &WebhookHandler{v: validator.New(), webhooks: webhooks, deliverer: deliverer}
+}
+
+type webhookReq struct {
+	URL         string   `json:"url" validate:"required,http_url,max=2000"`
+	EventTypes  []string `json:"event_types" validate:"dive,required,max=100"` // пусто — все события
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=200"`   // не задан — сгенерируем (PUT: оставить прежний)
+	Description string   `json:"description" validate:"max=500"`
+	IsEnabled   *bool    `json:"is_enabled"`
+}
+
+func (h *WebhookHandler) decode(w http.ResponseWriter, r *http.Request) (webhookReq, bool) {
+	var req webhookReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return req, false
+	}
+	if err := h.v.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return req, false
+	}
+	if req.EventTypes == nil {
+		req.EventTypes = []string{}
+	}
+	return req, true
+}
+
+func (req webhookReq) toSubscription(id uuid.UUID) domain.WebhookSubscription {
+	s := domain.WebhookSubscription{
+		ID:          id,
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Secret:      req.Secret,
+		Description: strings.TrimSpace(req.Description),
+		IsEnabled:   true,
+	}
+	if req.IsEnabled != nil {
+		s.IsEnabled = *req.IsEnabled
+	}
+	return s
+}
+
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.webhooks.ListSubscriptions(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, subs)
+}
+
+// POST /admin/webhooks — secret возвращается только здесь
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	uid, ok := auth.UserID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	req, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	s := req.toSubscription(uuid.New())
+	s.CreatedBy = uid
+	if s.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.Secret = secret
+	}
+
+	if err := h.webhooks.CreateSubscription(r.Context(), s); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"id": s.ID.String(), "secret": s.Secret})
+}
+
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	s, found, err := h.webhooks.GetSubscription(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, s)
+}
+
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	req, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	found, err := h.webhooks.UpdateSubscription(r.Context(), req.toSubscription(id))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	found, err := h.webhooks.DeleteSubscription(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /admin/webhooks/{id}/test — webhook.test отправляется сразу, в ответе результат попытки
+func (h *WebhookHandler) SendTest(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	s, found, err := h.webhooks.GetSubscription(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	eventID := uuid.New()
+	payload, _ := json.Marshal(map[string]any{
+		"subscription_id": s.ID.String(),
+		"message":         "test event",
+	})
+	d := domain.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: s.ID,
+		EventID:        eventID,
+		EventType:      "webhook.test",
+		Payload:        payload,
+	}
+	if err := h.webhooks.EnqueueLeased(r.Context(), d, h.deliverer.Lease()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	job, err := h.webhooks.GetJob(r.Context(), d.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	a, err := h.deliverer.Deliver(r.Context(), job)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+// GET /admin/webhooks/{id}/deliveries?limit=100
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	items, err := h.webhooks.ListDeliveries(r.Context(), id, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// GET /admin/webhooks/deliveries/{deliveryID}/attempts
+func (h *WebhookHandler) Attempts(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		http.Error(w, "invalid delivery id", http.StatusBadRequest)
+		return
+	}
+	items, err := h.webhooks.ListAttempts(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// POST /admin/webhooks/deliveries/{deliveryID}/retry — вернуть недоставленное в очередь
+func (h *WebhookHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		http.Error(w, "invalid delivery id", http.StatusBadRequest)
+		return
+	}
+	ok, err := h.webhooks.RetryDelivery(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, "not found or already delivered", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -9,34 +9,91 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
 )
 
 type Deps struct {
-	ApplicationHandler *ApplicationHandler
-	CatalogHandler     *CatalogHandler
-	ProgramHandler     *ProgramHandler
-	TeacherHandler     *TeacherHandler
-	MaterialHandler    *MaterialHandler
-	ProgressHandler    *ProgressHandler
-	AssignmentHandler  *AssignmentHandler
-	SubmissionHandler  *SubmissionHandler
+	Auth func(http.Handler) http.Handler
+
+	AuthHandler         *AuthHandler
+	ProfileHandler      *ProfileHandler
+	GuardianHandler     *GuardianHandler
+	ApplicationHandler  *ApplicationHandler
+	CatalogHandler      *CatalogHandler
+	ProgramHandler      *ProgramHandler
+	TeacherHandler      *TeacherHandler
+	MaterialHandler     *MaterialHandler
+	ProgressHandler     *ProgressHandler
+	AssignmentHandler   *AssignmentHandler
+	SubmissionHandler   *SubmissionHandler
+	OutboxHandler       *OutboxHandler
+	RuleHandler         *RuleHandler
+	WebhookHandler      *WebhookHandler
+	NotificationHandler *NotificationHandler
+	EventsHandler       *EventsHandler
+	EnrollmentHandler   *EnrollmentHandler
+	FormHandler         *FormHandler
+	EligibilityHandler  *EligibilityHandler
+	MessageHandler      *ApplicationMessageHandler
+	AttachmentHandler   *AttachmentHandler
+	AdmissionHandler    *AdmissionHandler
+	SlotHandler         *InterviewSlotHandler
 }
 
 func NewRouter(d Deps) http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
+	r.Use(redactAccessToken)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
-	r.Use(auth.Middleware)
+	r.Use(d.Auth)
 
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) })
 
+	// SSE: события outbox в реальном времени (фильтр по правам внутри)
+	r.Get(auth.StreamPath, d.EventsHandler.Stream)
+
+	// Local accounts
+	r.Route("/auth", func(r chi.Router) {
+		r.Post("/register", d.AuthHandler.Register)
+		r.Post("/login", d.AuthHandler.Login)
+		r.Post("/refresh", d.AuthHandler.Refresh)
+		r.Post("/logout", d.AuthHandler.Logout)
+	})
+
+	r.Route("/me", func(r chi.Router) {
+		r.Get("/profile", d.ProfileHandler.GetMine)
+		r.Put("/profile", d.ProfileHandler.PutMine)
+
+		r.Get("/notification-preferences", d.NotificationHandler.GetPreferences)
+		r.Put("/notification-preferences", d.NotificationHandler.PutPreferences)
+
+		// inbox
+		r.Get("/notifications", d.NotificationHandler.List)
+		r.Get("/notifications/unread-count", d.NotificationHandler.UnreadCount)
+		r.Post("/notifications/read-all", d.NotificationHandler.MarkAllRead)
+		r.Post("/notifications/{id}/read", d.NotificationHandler.MarkRead)
+
+		// зачисления: ?child_id=... — guardian смотрит зачисления ребёнка
+		r.With(d.GuardianHandler.LearnerScope).Get("/enrollments", d.EnrollmentHandler.ListMine)
+		r.Post("/enrollments/{id}/withdraw", d.EnrollmentHandler.Withdraw)
+
+		// guardian -> children
+		r.Get("/children", d.GuardianHandler.ListChildren)
+		r.Post("/children", d.GuardianHandler.CreateChild)
+		r.Delete("/children/{childID}", d.GuardianHandler.Unlink)
+		r.Get("/children/{childID}/applications", d.GuardianHandler.ChildApplications)
+	})
+
 	// Public catalog
 	r.Route("/catalog", func(r chi.Router) {
 		r.Get("/programs", d.CatalogHandler.ListPrograms)
 		r.Get("/programs/{id}", d.CatalogHandler.GetProgram)
+		r.Get("/groups/{id}/form", d.FormHandler.GetEffective)
+		// допуск текущего пользователя; ?child_id=... — guardian проверяет ребёнка
+		r.With(d.GuardianHandler.LearnerScope).Get("/programs/{id}/eligibility", d.EligibilityHandler.ForProgram)
 	})
 	r.Get("/applications", d.ApplicationHandler.List)
 	// Private program view (staff/teacher)
@@ -44,22 +101,107 @@ func NewRouter(d Deps) http.Handler {
 
 	// Admin catalog management
 	r.Route("/admin", func(r chi.Router) {
-		// existing:
-		r.Get("/applications", d.ApplicationHandler.List)         //
-		r.Get("/programs", d.CatalogHandler.ListProgramsAdmin)    //
-		r.Get("/programs/{id}", d.CatalogHandler.GetProgramAdmin) //
-		r.Get("/groups/{id}/teachers", d.CatalogHandler.GetGroupTeachers)
-		r.Post("/applications/{id}/status", d.ApplicationHandler.ChangeStatus)
-		r.Post("/programs", d.CatalogHandler.CreateProgram)
-		r.Post("/programs/{id}/publish", d.CatalogHandler.PublishProgram)
-		r.Post("/cohorts", d.CatalogHandler.CreateCohort)
-		r.Post("/groups", d.CatalogHandler.CreateGroup)
-		r.Post("/groups/{id}/teachers", d.CatalogHandler.AssignTeacher) // teacher_user_id in query
+		// staff или преподаватель (проверки по группе внутри)
+		r.Get("/applications", d.ApplicationHandler.List)
+		r.Get("/applications/{id}/history", d.ApplicationHandler.History)
+		r.Get("/applications/{id}/messages", d.MessageHandler.ListStaff)
+		r.Post("/applications/{id}/messages", d.MessageHandler.PostStaff)
+		r.Get("/applications/{id}/attachments", d.AttachmentHandler.ListStaff)
+		r.Get("/applications/{id}/attachments/{attID}/download", d.AttachmentHandler.Download)
+		r.Get("/groups/{id}/ranking", d.AdmissionHandler.Ranking)
 		r.Post("/groups/{groupID}/materials", d.MaterialHandler.CreateForGroup)
-		r.Post("/groups/{id}/close", d.CatalogHandler.CloseGroup)
-		r.Patch("/groups/{id}", d.CatalogHandler.UpdateGroup)
-		r.Patch("/programs/{id}", d.CatalogHandler.UpdateProgram)
-		r.Delete("/groups/{id}/teachers", d.CatalogHandler.RemoveTeacher)
+
+		r.Group(func(r chi.Router) {
+			r.Use(authz.RequirePermission(authz.CatalogReadAll))
+			r.Get("/programs", d.CatalogHandler.ListProgramsAdmin)
+			r.Get("/programs/{id}", d.CatalogHandler.GetProgramAdmin)
+			r.Get("/groups/{id}/teachers", d.CatalogHandler.GetGroupTeachers)
+			r.Get("/groups/{id}/admission", d.AdmissionHandler.Get)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(authz.RequirePermission(authz.ApplicationReview))
+			r.Post("/applications/{id}/status", d.ApplicationHandler.ChangeStatus)
+			r.Post("/applications/bulk-status", d.ApplicationHandler.BulkChangeStatus)
+			r.Put("/applications/{id}/score", d.AdmissionHandler.SetScore)
+			r.Post("/groups/{id}/admission/finalize", d.AdmissionHandler.Finalize)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(authz.RequirePermission(authz.CatalogManage))
+			r.Post("/programs", d.CatalogHandler.CreateProgram)
+			r.Post("/programs/{id}/publish", d.CatalogHandler.PublishProgram)
+			r.Post("/cohorts", d.CatalogHandler.CreateCohort)
+			r.Patch("/cohorts/{id}", d.CatalogHandler.UpdateCohort)
+			r.Post("/groups", d.CatalogHandler.CreateGroup)
+			r.Post("/groups/{id}/teachers", d.CatalogHandler.AssignTeacher) // teacher_user_id in query
+			r.Post("/groups/{id}/close", d.CatalogHandler.CloseGroup)
+			r.Patch("/groups/{id}", d.CatalogHandler.UpdateGroup)
+			r.Patch("/programs/{id}", d.CatalogHandler.UpdateProgram)
+			r.Delete("/groups/{id}/teachers", d.CatalogHandler.RemoveTeacher)
+
+			// анкеты заявок
+			r.Get("/programs/{id}/form", d.FormHandler.GetForProgram)
+			r.Put("/programs/{id}/form", d.FormHandler.PutForProgram)
+			r.Delete("/programs/{id}/form", d.FormHandler.DeleteForProgram)
+			r.Get("/groups/{id}/form", d.FormHandler.GetForGroup)
+			r.Put("/groups/{id}/form", d.FormHandler.PutForGroup)
+			r.Delete("/groups/{id}/form", d.FormHandler.DeleteForGroup)
+
+			// условия допуска
+			r.Get("/programs/{id}/eligibility", d.EligibilityHandler.GetForProgram)
+			r.Put("/programs/{id}/eligibility", d.EligibilityHandler.PutForProgram)
+			r.Delete("/programs/{id}/eligibility", d.EligibilityHandler.DeleteForProgram)
+			r.Get("/groups/{id}/eligibility", d.EligibilityHandler.GetForGroup)
+			r.Put("/groups/{id}/eligibility", d.EligibilityHandler.PutForGroup)
+			r.Delete("/groups/{id}/eligibility", d.EligibilityHandler.DeleteForGroup)
+
+			// конкурсный приём: режим и правила баллов
+			r.Put("/groups/{id}/admission", d.AdmissionHandler.Put)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(authz.RequirePermission(authz.EnrollmentManage))
+			r.Get("/groups/{id}/enrollments", d.EnrollmentHandler.ListByGroup)
+			r.Post("/enrollments/{id}/status", d.EnrollmentHandler.ChangeStatus)
+			r.Get("/enrollments/{id}/history", d.EnrollmentHandler.History)
+			r.Post("/enrollments/{id}/transfer", d.EnrollmentHandler.Transfer)
+			r.Get("/enrollments/{id}/transfers", d.EnrollmentHandler.Transfers)
+		})
+
+		r.With(authz.RequirePermission(authz.GuardianManage)).
+			Post("/guardians/{guardianID}/children/{childID}", d.GuardianHandler.AdminLink)
+
+		r.Group(func(r chi.Router) {
+			r.Use(authz.RequirePermission(authz.OutboxManage))
+			r.Get("/outbox/dead-letters", d.OutboxHandler.ListDeadLetters)
+			r.Post("/outbox/dead-letters/{id}/replay", d.OutboxHandler.Replay)
+		})
+
+		// Event -> Rule -> Action
+		r.Group(func(r chi.Router) {
+			r.Use(authz.RequirePermission(authz.RuleManage))
+			r.Get("/rules", d.RuleHandler.List)
+			r.Post("/rules", d.RuleHandler.Create)
+			r.Get("/rules/{id}", d.RuleHandler.Get)
+			r.Put("/rules/{id}", d.RuleHandler.Update)
+			r.Delete("/rules/{id}", d.RuleHandler.Delete)
+			r.Get("/rules/{id}/executions", d.RuleHandler.Executions)
+		})
+
+		// outgoing webhooks (CRM)
+		r.Group(func(r chi.Router) {
+			r.Use(authz.RequirePermission(authz.WebhookManage))
+			r.Get("/webhooks", d.WebhookHandler.List)
+			r.Post("/webhooks", d.WebhookHandler.Create)
+			r.Get("/webhooks/{id}", d.WebhookHandler.Get)
+			r.Put("/webhooks/{id}", d.WebhookHandler.Update)
+			r.Delete("/webhooks/{id}", d.WebhookHandler.Delete)
+			r.Post("/webhooks/{id}/test", d.WebhookHandler.SendTest)
+			r.Get("/webhooks/{id}/deliveries", d.WebhookHandler.Deliveries)
+			r.Get("/webhooks/deliveries/{deliveryID}/attempts", d.WebhookHandler.Attempts)
+			r.Post("/webhooks/deliveries/{deliveryID}/retry", d.WebhookHandler.Retry)
+		})
 	})
 
 	// Teacher
@@ -67,6 +209,9 @@ func NewRouter(d Deps) http.Handler {
 		r.Get("/groups", d.TeacherHandler.MyGroups)
 		r.Get("/groups/{id}/applications", d.TeacherHandler.GroupApplications) //
 		r.Post("/applications/{appID}/interview", d.TeacherHandler.RecordInterview)
+		r.Get("/groups/{id}/interview-slots", d.SlotHandler.ListForGroup)
+		r.Post("/groups/{id}/interview-slots", d.SlotHandler.Create)
+		r.Delete("/interview-slots/{slotID}", d.SlotHandler.Delete)
 		r.Post("/groups/{groupID}/materials", d.MaterialHandler.CreateForGroup)
 		r.Post("/groups/{groupID}/assignments", d.AssignmentHandler.CreateForGroup)
 		r.Get("/groups/{groupID}/submissions", d.SubmissionHandler.ListForTeacher)
@@ -78,7 +223,11 @@ func NewRouter(d Deps) http.Handler {
 
 	// Learner area (after enrollment)
 	r.Route("/learn", func(r chi.Router) {
+		// ?child_id=... — guardian смотрит данные ребёнка (read-only)
+		r.Use(d.GuardianHandler.LearnerScope)
+
 		r.Get("/groups/{groupID}/materials", d.MaterialHandler.ListForLearner)
+		r.Get("/groups/{groupID}/progress", d.ProgressHandler.GroupProgress)
 
 		// mark material as read
 		r.Post("/materials/{materialID}/read", d.ProgressHandler.MarkRead)
@@ -96,7 +245,32 @@ func NewRouter(d Deps) http.Handler {
 		r.Post("/applications", d.ApplicationHandler.Create)
 		r.Get("/me/applications", d.ApplicationHandler.ListMine)
 		r.Post("/applications/{id}/cancel", d.ApplicationHandler.CancelMyApplication)
+		r.Get("/applications/{id}/waitlist", d.ApplicationHandler.WaitlistPosition)
+		r.Get("/applications/{id}/history", d.ApplicationHandler.MyHistory)
+		r.Get("/applications/{id}/messages", d.MessageHandler.ListMine)
+		r.Post("/applications/{id}/messages", d.MessageHandler.PostMine)
+		r.Get("/applications/{id}/attachments", d.AttachmentHandler.ListMine)
+		r.Post("/applications/{id}/attachments", d.AttachmentHandler.Upload)
+		r.Delete("/applications/{id}/attachments/{attID}", d.AttachmentHandler.DeleteMine)
+		r.Get("/applications/{id}/interview-slots", d.SlotHandler.ListMine)
+		r.Put("/applications/{id}/interview-slot", d.SlotHandler.Book)
+		r.Delete("/applications/{id}/interview-slot", d.SlotHandler.Cancel)
 	})
 
 	return r
 }
+
+// redactAccessToken: ?access_token= (SSE) не должен попадать в access-лог.
+// middleware.Logger пишет r.RequestURI — подменяем только его, r.URL (и сам токен для auth) не трогаем.
+func redactAccessToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Has("access_token") {
+			q.Set("access_token", "REDACTED")
+			r2 := *r
+			r2.RequestURI = r.URL.EscapedPath() + "?" + q.Encode()
+			r = &r2
+		}
+		next.ServeHTTP(w, r)
+	})
+}
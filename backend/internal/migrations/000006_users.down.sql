@@ -0,0 +1,6 @@
+drop index if exists idx_refresh_tokens_family;
+drop index if exists idx_refresh_tokens_user;
+drop table if exists refresh_tokens;
+
+drop index if exists ux_users_email;
+drop table if exists users;
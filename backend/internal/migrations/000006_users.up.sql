@@ -0,0 +1,23 @@
+-- local accounts (можно работать без внешнего ядра идентификации)
+create table if not exists users (
+                                     id uuid primary key,
+                                     email text not null,
+                                     password_hash text not null,
+                                     role text not null default 'user', -- user|moderator|admin
+                                     created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now()
+    );
+create unique index if not exists ux_users_email on users(lower(email));
+
+-- refresh tokens: храним только sha256, при refresh старый токен отзывается (rotation)
+create table if not exists refresh_tokens (
+                                              id uuid primary key,
+                                              user_id uuid not null references users(id) on delete cascade,
+    family_id uuid not null, -- цепочка ротаций одного логина
+    token_hash text not null unique,
+    expires_at timestamptz not null,
+    revoked_at timestamptz null,
+    created_at timestamptz not null default now()
+    );
+create index if not exists idx_refresh_tokens_user on refresh_tokens(user_id);
+create index if not exists idx_refresh_tokens_family on refresh_tokens(family_id);
@@ -0,0 +1,12 @@
+-- user profiles (staff видят имена вместо uuid)
+create table if not exists profiles (
+                                        user_id uuid primary key, -- users.id или sub внешнего ядра, поэтому без FK
+                                        full_name text not null default '',
+                                        birth_date date null,
+                                        school text not null default '',
+                                        grade int null check (grade between 1 and 11),
+    contact_email text not null default '',
+    contact_phone text not null default '',
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now()
+    );
@@ -0,0 +1,4 @@
+alter table enrollment_applications drop column if exists submitted_by_user_id;
+
+drop index if exists idx_guardian_links_child;
+drop table if exists guardian_links;
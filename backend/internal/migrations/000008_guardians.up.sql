@@ -0,0 +1,12 @@
+-- guardian (родитель/опекун) -> child (ученик)
+create table if not exists guardian_links (
+                                              guardian_user_id uuid not null,
+                                              child_user_id uuid not null,
+                                              created_at timestamptz not null default now(),
+    primary key (guardian_user_id, child_user_id),
+    check (guardian_user_id <> child_user_id)
+    );
+create index if not exists idx_guardian_links_child on guardian_links(child_user_id);
+
+-- кто фактически подал заявку (guardian за ребёнка); null = сам заявитель
+alter table enrollment_applications add column if not exists submitted_by_user_id uuid null;
@@ -0,0 +1,6 @@
+drop table if exists outbox_dead_letters;
+
+drop index if exists idx_outbox_due;
+alter table outbox_events drop column if exists last_error;
+alter table outbox_events drop column if exists next_attempt_at;
+alter table outbox_events drop column if exists attempts;
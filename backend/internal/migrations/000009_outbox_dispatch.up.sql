@@ -0,0 +1,18 @@
+-- доставка outbox: повторы с backoff, poison-события уходят в dead letters
+alter table outbox_events add column if not exists attempts int not null default 0;
+alter table outbox_events add column if not exists next_attempt_at timestamptz not null default now();
+alter table outbox_events add column if not exists last_error text null;
+
+create index if not exists idx_outbox_due on outbox_events(next_attempt_at) where published_at is null;
+
+create table if not exists outbox_dead_letters (
+                                                   id uuid primary key, -- id исходного события
+                                                   aggregate_type text not null,
+                                                   aggregate_id uuid not null,
+                                                   event_type text not null,
+                                                   payload jsonb not null,
+                                                   created_at timestamptz not null,
+                                                   attempts int not null,
+                                                   last_error text null,
+                                                   dead_at timestamptz not null default now()
+    );
@@ -0,0 +1,5 @@
+drop index if exists idx_rule_exec_rule;
+drop table if exists rule_executions;
+
+drop index if exists idx_rules_event;
+drop table if exists automation_rules;
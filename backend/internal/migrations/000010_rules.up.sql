@@ -0,0 +1,29 @@
+-- Event -> Rule -> Action
+create table if not exists automation_rules (
+                                                id uuid primary key,
+                                                name text not null,
+                                                event_type text not null,
+                                                conditions jsonb not null default '[]',
+                                                action text not null check (action in ('notify','set_application_status','webhook')),
+                                                action_params jsonb not null default '{}',
+                                                is_enabled boolean not null default true,
+                                                created_by uuid not null,
+                                                created_at timestamptz not null default now(),
+                                                updated_at timestamptz not null default now()
+    );
+create index if not exists idx_rules_event on automation_rules(event_type) where is_enabled;
+
+-- журнал срабатываний; одна строка на (правило, событие) — повтор события не выполнит успешное действие второй раз
+create table if not exists rule_executions (
+                                               id uuid primary key,
+                                               rule_id uuid not null references automation_rules(id) on delete cascade,
+                                               event_id uuid not null,
+                                               event_type text not null,
+                                               status text not null check (status in ('success','failed')),
+                                               attempts int not null default 1,
+                                               error text null,
+                                               created_at timestamptz not null default now(),
+                                               updated_at timestamptz not null default now(),
+    unique(rule_id, event_id)
+    );
+create index if not exists idx_rule_exec_rule on rule_executions(rule_id, created_at desc);
@@ -0,0 +1,8 @@
+drop index if exists idx_webhook_attempts_delivery;
+drop table if exists webhook_attempts;
+
+drop index if exists idx_webhook_deliveries_sub;
+drop index if exists idx_webhook_deliveries_due;
+drop table if exists webhook_deliveries;
+
+drop table if exists webhook_subscriptions;
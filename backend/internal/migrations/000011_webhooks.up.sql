@@ -0,0 +1,41 @@
+-- исходящие вебхуки (CRM и т.п.), источник — outbox_events
+create table if not exists webhook_subscriptions (
+                                                     id uuid primary key,
+                                                     url text not null,
+                                                     event_types text[] not null default '{}', -- пусто = все события
+                                                     secret text not null,
+                                                     description text not null default '',
+                                                     is_enabled boolean not null default true,
+                                                     created_by uuid not null,
+                                                     created_at timestamptz not null default now(),
+                                                     updated_at timestamptz not null default now()
+    );
+
+create table if not exists webhook_deliveries (
+                                                  id uuid primary key,
+                                                  subscription_id uuid not null references webhook_subscriptions(id) on delete cascade,
+                                                  event_id uuid not null,
+                                                  event_type text not null,
+                                                  payload jsonb not null,
+                                                  status text not null default 'pending' check (status in ('pending','delivered','failed')),
+                                                  attempts int not null default 0,
+                                                  next_attempt_at timestamptz not null default now(),
+                                                  last_status_code int null,
+                                                  created_at timestamptz not null default now(),
+                                                  delivered_at timestamptz null,
+    unique(subscription_id, event_id) -- повтор outbox-события не создаст вторую доставку
+    );
+create index if not exists idx_webhook_deliveries_due on webhook_deliveries(next_attempt_at) where status='pending';
+create index if not exists idx_webhook_deliveries_sub on webhook_deliveries(subscription_id, created_at desc);
+
+create table if not exists webhook_attempts (
+                                                id uuid primary key,
+                                                delivery_id uuid not null references webhook_deliveries(id) on delete cascade,
+                                                attempt_no int not null,
+                                                status_code int null,
+                                                error text null,
+                                                response_body text not null default '',
+                                                duration_ms int not null,
+                                                created_at timestamptz not null default now()
+    );
+create index if not exists idx_webhook_attempts_delivery on webhook_attempts(delivery_id);
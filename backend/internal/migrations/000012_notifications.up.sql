@@ -0,0 +1,18 @@
+-- настройки уведомлений пользователя; строки нет = значения по умолчанию
+create table if not exists notification_preferences (
+                                                        user_id uuid primary key,
+                                                        locale text not null default 'ru' check (locale in ('ru','en')),
+                                                        email_enabled boolean not null default true,
+                                                        muted_kinds text[] not null default '{}', -- типы событий без уведомлений
+                                                        updated_at timestamptz not null default now()
+    );
+
+-- что уже отправлено: повтор outbox-события не шлёт письмо второй раз
+create table if not exists notification_log (
+                                                event_id uuid not null,
+                                                user_id uuid not null,
+                                                channel text not null, -- email
+                                                kind text not null,
+                                                created_at timestamptz not null default now(),
+    primary key (event_id, user_id, channel)
+    );
@@ -0,0 +1,3 @@
+drop index if exists idx_notifications_unread;
+drop index if exists idx_notifications_user;
+drop table if exists notifications;
@@ -0,0 +1,15 @@
+-- in-app уведомления (колокольчик); заполняются из outbox тем же notify.Notifier
+create table if not exists notifications (
+                                             id uuid primary key,
+                                             user_id uuid not null,
+                                             event_id uuid not null,
+                                             kind text not null,
+                                             title text not null,
+                                             body text not null,
+                                             data jsonb not null default '{}',
+                                             read_at timestamptz null,
+                                             created_at timestamptz not null default now(),
+    unique(event_id, user_id)
+    );
+create index if not exists idx_notifications_user on notifications(user_id, created_at desc);
+create index if not exists idx_notifications_unread on notifications(user_id) where read_at is null;
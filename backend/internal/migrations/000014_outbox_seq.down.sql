@@ -0,0 +1,2 @@
+drop index if exists idx_outbox_seq;
+alter table outbox_events drop column if exists seq;
@@ -0,0 +1,3 @@
+-- монотонный номер события для SSE (Last-Event-ID); существующие строки нумеруются при добавлении колонки
+alter table outbox_events add column if not exists seq bigserial;
+create unique index if not exists idx_outbox_seq on outbox_events(seq);
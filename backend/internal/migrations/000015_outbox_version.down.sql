@@ -0,0 +1,2 @@
+alter table outbox_dead_letters drop column if exists version;
+alter table outbox_events drop column if exists version;
@@ -0,0 +1,3 @@
+-- версия схемы payload (outbox.Payload.Version)
+alter table outbox_events add column if not exists version int not null default 1;
+alter table outbox_dead_letters add column if not exists version int not null default 1;
@@ -0,0 +1,9 @@
+drop index if exists ux_enrollment_apps_user_group_active;
+create unique index if not exists ux_enrollment_apps_user_group_active
+    on enrollment_applications (user_id, group_id)
+    where status in ('submitted', 'in_review', 'approved');
+
+drop index if exists idx_enroll_apps_waitlist;
+
+alter table enrollment_applications
+    drop column if exists waitlisted_at;
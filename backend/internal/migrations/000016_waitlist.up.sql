@@ -0,0 +1,13 @@
+-- лист ожидания: позиция = порядок waitlisted_at внутри группы
+alter table enrollment_applications
+    add column if not exists waitlisted_at timestamptz null;
+
+create index if not exists idx_enroll_apps_waitlist
+    on enrollment_applications (group_id, waitlisted_at)
+    where status = 'waitlisted';
+
+-- waitlisted — тоже активная заявка
+drop index if exists ux_enrollment_apps_user_group_active;
+create unique index if not exists ux_enrollment_apps_user_group_active
+    on enrollment_applications (user_id, group_id)
+    where status in ('submitted', 'in_review', 'waitlisted', 'approved');
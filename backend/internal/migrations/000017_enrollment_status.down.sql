@@ -0,0 +1,9 @@
+drop table if exists enrollment_status_audit;
+
+drop index if exists idx_enroll_group_active;
+
+alter table enrollments
+    drop column if exists updated_at,
+    drop column if exists ended_at,
+    drop column if exists status_reason,
+    drop column if exists status;
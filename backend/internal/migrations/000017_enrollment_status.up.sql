@@ -0,0 +1,21 @@
+-- жизненный цикл зачисления: active -> withdrawn | expelled | completed
+alter table enrollments
+    add column if not exists status text not null default 'active',
+    add column if not exists status_reason text not null default '',
+    add column if not exists ended_at timestamptz null,
+    add column if not exists updated_at timestamptz not null default now();
+
+create index if not exists idx_enroll_group_active on enrollments(group_id) where status = 'active';
+
+create table if not exists enrollment_status_audit (
+                                                       id uuid primary key,
+                                                       enrollment_id uuid not null references enrollments(id) on delete cascade,
+                                                       actor_user_id uuid not null,
+                                                       actor_role text not null,
+                                                       from_status text not null,
+                                                       to_status text not null,
+                                                       reason text not null default '',
+                                                       created_at timestamptz not null default now()
+);
+
+create index if not exists idx_enroll_audit_enrollment on enrollment_status_audit(enrollment_id, created_at);
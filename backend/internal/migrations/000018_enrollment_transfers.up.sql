@@ -0,0 +1,20 @@
+-- переводы между группами одной программы
+create table if not exists enrollment_transfers (
+                                                    id uuid primary key,
+                                                    user_id uuid not null,
+                                                    from_enrollment_id uuid not null references enrollments(id) on delete cascade,
+                                                    to_enrollment_id uuid not null references enrollments(id) on delete cascade,
+                                                    from_group_id uuid not null references groups(id) on delete cascade,
+                                                    to_group_id uuid not null references groups(id) on delete cascade,
+                                                    actor_user_id uuid not null,
+                                                    actor_role text not null,
+                                                    reason text not null default '',
+                                                    carry_progress boolean not null default false,
+                                                    carried_reads int not null default 0,
+                                                    carried_submissions int not null default 0,
+                                                    created_at timestamptz not null default now()
+);
+
+create index if not exists idx_enroll_transfers_user on enrollment_transfers(user_id, created_at desc);
+create index if not exists idx_enroll_transfers_from on enrollment_transfers(from_enrollment_id);
+create index if not exists idx_enroll_transfers_to on enrollment_transfers(to_enrollment_id);
@@ -0,0 +1,6 @@
+drop index if exists idx_enroll_apps_answers;
+
+alter table enrollment_applications
+    drop column if exists answers;
+
+drop table if exists application_forms;
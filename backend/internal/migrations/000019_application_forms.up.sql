@@ -0,0 +1,20 @@
+-- анкеты заявки: на программу или на группу (анкета группы важнее)
+create table if not exists application_forms (
+                                                 id uuid primary key,
+                                                 program_id uuid null references programs(id) on delete cascade,
+                                                 group_id uuid null references groups(id) on delete cascade,
+                                                 questions jsonb not null default '[]'::jsonb,
+                                                 updated_by uuid not null,
+                                                 created_at timestamptz not null default now(),
+                                                 updated_at timestamptz not null default now(),
+                                                 check ((program_id is null) <> (group_id is null))
+);
+
+create unique index if not exists ux_application_forms_program on application_forms(program_id) where program_id is not null;
+create unique index if not exists ux_application_forms_group on application_forms(group_id) where group_id is not null;
+
+-- ответы заявителя: {"question_key": value}
+alter table enrollment_applications
+    add column if not exists answers jsonb not null default '{}'::jsonb;
+
+create index if not exists idx_enroll_apps_answers on enrollment_applications using gin (answers jsonb_path_ops);
@@ -0,0 +1,11 @@
+alter table groups drop constraint if exists chk_groups_window;
+alter table cohorts drop constraint if exists chk_cohorts_window;
+
+alter table groups
+    drop column if exists window_state,
+    drop column if exists closes_at,
+    drop column if exists opens_at;
+
+alter table cohorts
+    drop column if exists closes_at,
+    drop column if exists opens_at;
@@ -0,0 +1,18 @@
+-- окна приёма заявок: у группы или у потока (cohort); действует окно группы, иначе потока
+alter table cohorts
+    add column if not exists opens_at timestamptz null,
+    add column if not exists closes_at timestamptz null;
+
+alter table groups
+    add column if not exists opens_at timestamptz null,
+    add column if not exists closes_at timestamptz null,
+    -- последнее состояние окна, применённое фоновой задачей: upcoming | open | closed
+    add column if not exists window_state text null;
+
+alter table cohorts drop constraint if exists chk_cohorts_window;
+alter table cohorts
+    add constraint chk_cohorts_window check (opens_at is null or closes_at is null or opens_at < closes_at);
+
+alter table groups drop constraint if exists chk_groups_window;
+alter table groups
+    add constraint chk_groups_window check (opens_at is null or closes_at is null or opens_at < closes_at);
@@ -0,0 +1,17 @@
+-- условия допуска: на программу или на группу (правило группы важнее)
+create table if not exists eligibility_rules (
+                                                 id uuid primary key,
+                                                 program_id uuid null references programs(id) on delete cascade,
+                                                 group_id uuid null references groups(id) on delete cascade,
+                                                 min_age int null,
+                                                 max_age int null,
+                                                 required_program_ids uuid[] not null default '{}',
+                                                 max_active_enrollments int null,
+                                                 updated_by uuid not null,
+                                                 created_at timestamptz not null default now(),
+                                                 updated_at timestamptz not null default now(),
+                                                 check ((program_id is null) <> (group_id is null))
+);
+
+create unique index if not exists ux_eligibility_rules_program on eligibility_rules(program_id) where program_id is not null;
+create unique index if not exists ux_eligibility_rules_group on eligibility_rules(group_id) where group_id is not null;
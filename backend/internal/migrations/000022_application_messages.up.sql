@@ -0,0 +1,14 @@
+-- переписка по заявке: заявитель/guardian <-> модератор/преподаватель; internal — заметки только для staff
+create table if not exists application_messages (
+                                                    id uuid primary key,
+                                                    application_id uuid not null references enrollment_applications(id) on delete cascade,
+                                                    author_user_id uuid not null,
+                                                    author_role text not null,
+                                                    from_applicant boolean not null,
+                                                    body text not null,
+                                                    internal boolean not null default false,
+                                                    created_at timestamptz not null default now(),
+                                                    check (not (internal and from_applicant))
+);
+
+create index if not exists idx_app_messages_app on application_messages(application_id, created_at);
@@ -0,0 +1,15 @@
+-- файлы к заявке (документы): содержимое во внешнем хранилище (storage_key), здесь — метаданные
+create table if not exists application_attachments (
+                                                       id uuid primary key,
+                                                       application_id uuid not null references enrollment_applications(id) on delete cascade,
+                                                       uploaded_by uuid not null,
+                                                       file_name text not null,
+                                                       content_type text not null,
+                                                       size_bytes bigint not null check (size_bytes > 0),
+                                                       storage_key text not null unique,
+                                                       sha256 text not null,
+                                                       scan_status text not null check (scan_status in ('clean','skipped')),
+                                                       created_at timestamptz not null default now()
+);
+
+create index if not exists idx_app_attachments_app on application_attachments(application_id, created_at);
@@ -0,0 +1,11 @@
+alter table enrollment_applications
+    drop column if exists admission_rank;
+
+drop table if exists application_scores;
+
+alter table groups drop constraint if exists chk_groups_admission_mode;
+
+alter table groups
+    drop column if exists admission_finalized_at,
+    drop column if exists admission_scoring,
+    drop column if exists admission_mode;
@@ -0,0 +1,23 @@
+-- конкурсный приём: заявки группы ранжируются по баллам, зачисление — одной операцией (finalize)
+alter table groups
+    add column if not exists admission_mode text not null default 'first_come',
+    -- правила баллов: {"Interview":{"recommended":10},"Answers":[{"Key":"lang","Values":{"go":5}}]}
+    add column if not exists admission_scoring jsonb not null default '{}'::jsonb,
+    add column if not exists admission_finalized_at timestamptz null;
+
+alter table groups drop constraint if exists chk_groups_admission_mode;
+alter table groups
+    add constraint chk_groups_admission_mode check (admission_mode in ('first_come','competitive'));
+
+-- ручные баллы staff
+create table if not exists application_scores (
+                                                  application_id uuid primary key references enrollment_applications(id) on delete cascade,
+                                                  manual_points double precision not null default 0,
+                                                  comment text not null default '',
+                                                  updated_by uuid not null,
+                                                  updated_at timestamptz not null default now()
+);
+
+-- место в рейтинге на момент подведения конкурса: порядок листа ожидания после finalize
+alter table enrollment_applications
+    add column if not exists admission_rank int null;
@@ -0,0 +1 @@
+drop table if exists interview_slots;
@@ -0,0 +1,24 @@
+-- слоты собеседований: преподаватель публикует время, заявитель (in_review) бронирует один слот
+create table if not exists interview_slots (
+                                               id uuid primary key,
+                                               group_id uuid not null references groups(id) on delete cascade,
+                                               teacher_user_id uuid not null,
+                                               starts_at timestamptz not null,
+                                               ends_at timestamptz not null,
+                                               location text not null default '',   -- адрес/кабинет
+                                               online_url text not null default '', -- ссылка на звонок
+                                               application_id uuid null references enrollment_applications(id) on delete set null,
+                                               booked_at timestamptz null,
+                                               reminder_sent_at timestamptz null,
+                                               created_at timestamptz not null default now(),
+                                               constraint chk_interview_slots_time check (ends_at > starts_at),
+                                               constraint chk_interview_slots_place check (location <> '' or online_url <> '')
+);
+
+-- у заявки не больше одного слота
+create unique index if not exists ux_interview_slots_application on interview_slots(application_id) where application_id is not null;
+create index if not exists idx_interview_slots_group on interview_slots(group_id, starts_at);
+create index if not exists idx_interview_slots_teacher on interview_slots(teacher_user_id, starts_at);
+-- напоминания: забронированные слоты без отметки
+create index if not exists idx_interview_slots_reminder on interview_slots(starts_at)
+    where application_id is not null and reminder_sent_at is null;
@@ -0,0 +1,286 @@
+// internal/notify/notifier.go
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+const ChannelEmail = "email"
+
+// Что Notifier берёт из репозиториев (repo.NotificationRepo, GuardianRepo, ApplicationRepo, CatalogRepo; в тестах — фейки)
+type notificationStore interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (domain.NotificationPreferences, error)
+	GetRecipient(ctx context.Context, userID uuid.UUID) (repo.Recipient, error)
+	GroupTitles(ctx context.Context, groupID uuid.UUID) (string, string, error)
+	CreateInbox(ctx context.Context, n domain.Notification) error
+	MarkSent(ctx context.Context, eventID, userID uuid.UUID, channel, kind string) (bool, error)
+	UnmarkSent(ctx context.Context, eventID, userID uuid.UUID, channel string) error
+}
+
+type guardianLister interface {
+	ListGuardians(ctx context.Context, childID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type enrolledLister interface {
+	ListEnrolledUsersByGroup(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type teacherLister interface {
+	ListGroupTeachers(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// Notifier — outbox.Sink: входящие (in-app) и письма ученику (и его guardians),
+// преподавателям группы — только входящие о новых заявках и бронях собеседований
+type Notifier struct {
+	notifications notificationStore
+	guardians     guardianLister
+	apps          enrolledLister
+	catalog       teacherLister
+	tpl           *Templates
+	sender        Sender
+}
+
+func NewNotifier(notifications notificationStore, guardians guardianLister, apps enrolledLister, catalog teacherLister, tpl *Templates, sender Sender) *Notifier {
+	return &Notifier{notifications: notifications, guardians: guardians, apps: apps, catalog: catalog, tpl: tpl, sender: sender}
+}
+
+func (n *Notifier) Name() string { return "notify" }
+
+// target: кому уведомление и о ком оно
+type target struct {
+	userID      uuid.UUID
+	studentID   uuid.UUID
+	forGuardian bool
+	kind        string // шаблон вместо event_type (текст для преподавателя)
+	inboxOnly   bool
+}
+
+func (n *Notifier) Handle(ctx context.Context, e outbox.Event) error {
+	var payload map[string]any
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: %v", outbox.ErrPermanent, err)
+	}
+
+	kind := e.EventType
+	data := payload
+	var targets []target
+	var err error
+
+	switch e.EventType {
+	case outbox.EventApplicationCreated:
+		targets, err = n.withGuardians(ctx, payloadID(payload, "user_id"))
+		if err == nil {
+			var ts []target
+			ts, err = n.groupTeachers(ctx, payloadID(payload, "group_id"), payloadID(payload, "user_id"))
+			targets = append(targets, ts...)
+		}
+	case outbox.EventApplicationStatusChanged:
+		// зачисление из листа ожидания — отдельным письмом (application.promoted)
+		if payload["from"] == string(domain.AppWaitlisted) && payload["actor_role"] == "system" {
+			return nil
+		}
+		targets, err = n.withGuardians(ctx, payloadID(payload, "user_id"))
+	case outbox.EventApplicationPromoted, outbox.EventEnrollmentStatusChanged, outbox.EventEnrollmentTransferred, outbox.EventSubmissionReviewed:
+		targets, err = n.withGuardians(ctx, payloadID(payload, "user_id"))
+	case outbox.EventApplicationMessagePosted:
+		switch {
+		case payload["internal"] == true:
+			return nil
+		case payload["from_applicant"] == true:
+			// заявитель написал — преподавателям группы во входящие
+			targets, err = n.groupTeachers(ctx, payloadID(payload, "group_id"), payloadID(payload, "user_id"))
+			for i := range targets {
+				targets[i].kind = "teacher.application_message"
+			}
+		default:
+			targets, err = n.withGuardians(ctx, payloadID(payload, "user_id"))
+		}
+	case outbox.EventInterviewRecorded:
+		targets, err = n.withGuardians(ctx, payloadID(payload, "candidate_id"))
+	case outbox.EventInterviewSlotBooked:
+		targets, err = n.withGuardians(ctx, payloadID(payload, "user_id"))
+		targets = append(targets, slotTeacher(payload, "teacher.interview_booked")...)
+	case outbox.EventInterviewSlotCancelled:
+		if payload["cancelled_by"] == "applicant" {
+			// заявитель отменил сам — сообщаем только преподавателю слота
+			targets = slotTeacher(payload, "teacher.interview_cancelled")
+		} else {
+			targets, err = n.withGuardians(ctx, payloadID(payload, "user_id"))
+		}
+	case outbox.EventInterviewReminder:
+		targets, err = n.withGuardians(ctx, payloadID(payload, "user_id"))
+	case outbox.EventMaterialCreated, outbox.EventAssignmentCreated:
+		targets, err = n.groupStudents(ctx, payloadID(payload, "group_id"))
+	case outbox.EventNotificationRequested:
+		// от правила автоматизации: шаблон задан в правиле, данные — payload исходного события
+		kind, _ = payload["template"].(string)
+		if !n.tpl.Has(kind) {
+			return fmt.Errorf("%w: unknown template %q", outbox.ErrPermanent, kind)
+		}
+		data, _ = payload["data"].(map[string]any)
+		if uid := payloadID(payload, "user_id"); uid != uuid.Nil {
+			targets = []target{{userID: uid, studentID: uid}}
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	td := TemplateData{Event: data}
+	if gid := payloadID(data, "group_id"); gid != uuid.Nil {
+		td.Group, td.Program, err = n.notifications.GroupTitles(ctx, gid)
+		if err != nil {
+			return err
+		}
+	}
+
+	var retry []error
+	for _, t := range targets {
+		if err := n.notify(ctx, e.ID, kind, t, td); err != nil {
+			retry = append(retry, err)
+		}
+	}
+	return errors.Join(retry...)
+}
+
+func (n *Notifier) withGuardians(ctx context.Context, studentID uuid.UUID) ([]target, error) {
+	if studentID == uuid.Nil {
+		return nil, nil
+	}
+	ts := []target{{userID: studentID, studentID: studentID}}
+	gs, err := n.guardians.ListGuardians(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range gs {
+		ts = append(ts, target{userID: g, studentID: studentID, forGuardian: true})
+	}
+	return ts, nil
+}
+
+func (n *Notifier) groupStudents(ctx context.Context, groupID uuid.UUID) ([]target, error) {
+	if groupID == uuid.Nil {
+		return nil, nil
+	}
+	ids, err := n.apps.ListEnrolledUsersByGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	ts := make([]target, 0, len(ids))
+	for _, id := range ids {
+		ts = append(ts, target{userID: id, studentID: id})
+	}
+	return ts, nil
+}
+
+func (n *Notifier) groupTeachers(ctx context.Context, groupID, studentID uuid.UUID) ([]target, error) {
+	if groupID == uuid.Nil {
+		return nil, nil
+	}
+	ids, err := n.catalog.ListGroupTeachers(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	ts := make([]target, 0, len(ids))
+	for _, id := range ids {
+		ts = append(ts, target{userID: id, studentID: studentID, kind: "teacher.application_created", inboxOnly: true})
+	}
+	return ts, nil
+}
+
+// slotTeacher: преподаватель слота собеседования (только входящие)
+func slotTeacher(payload map[string]any, kind string) []target {
+	id := payloadID(payload, "teacher_id")
+	if id == uuid.Nil {
+		return nil
+	}
+	return []target{{userID: id, studentID: payloadID(payload, "user_id"), kind: kind, inboxOnly: true}}
+}
+
+func (n *Notifier) notify(ctx context.Context, eventID uuid.UUID, kind string, t target, td TemplateData) error {
+	prefs, err := n.notifications.GetPreferences(ctx, t.userID)
+	if err != nil {
+		return err
+	}
+	if prefs.Muted(kind) {
+		return nil
+	}
+	if t.kind != "" {
+		kind = t.kind
+		if prefs.Muted(kind) {
+			return nil
+		}
+	}
+
+	rc, err := n.notifications.GetRecipient(ctx, t.userID)
+	if err != nil {
+		return err
+	}
+
+	td.Name = rc.FullName
+	td.ForGuardian = t.forGuardian
+	td.Student = rc.FullName
+	if t.studentID != t.userID {
+		st, err := n.notifications.GetRecipient(ctx, t.studentID)
+		if err != nil {
+			return err
+		}
+		td.Student = st.FullName
+	}
+
+	subj, body, err := n.tpl.Render(prefs.Locale, kind, td)
+	if err != nil {
+		// битый шаблон повтором не починить — в лог и дальше
+		slog.Error("notify render", "kind", kind, "err", err)
+		return nil
+	}
+
+	data, _ := json.Marshal(td.Event)
+	if err := n.notifications.CreateInbox(ctx, domain.Notification{
+		ID:      uuid.New(),
+		UserID:  t.userID,
+		EventID: eventID,
+		Kind:    kind,
+		Title:   subj,
+		Body:    body,
+		Data:    data,
+	}); err != nil {
+		return err
+	}
+
+	if t.inboxOnly || !prefs.EmailEnabled || rc.Email == "" {
+		return nil
+	}
+
+	first, err := n.notifications.MarkSent(ctx, eventID, t.userID, ChannelEmail, kind)
+	if err != nil || !first {
+		return err
+	}
+	if err := n.sender.Send(ctx, Message{To: rc.Email, Subject: subj, Body: body}); err != nil {
+		// снимаем отметку, чтобы повтор события отправил письмо
+		if uerr := n.notifications.UnmarkSent(ctx, eventID, t.userID, ChannelEmail); uerr != nil {
+			return errors.Join(err, uerr)
+		}
+		return fmt.Errorf("email to %s: %w", t.userID, err)
+	}
+	return nil
+}
+
+func payloadID(m map[string]any, key string) uuid.UUID {
+	s, _ := m[key].(string)
+	id, _ := uuid.Parse(s)
+	return id
+}
@@ -0,0 +1,230 @@
+// internal/notify/notifier_test.go
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+// fakeStore — notifications, notification_log и настройки в памяти, с теми же уникальными ключами
+type fakeStore struct {
+	prefs  map[uuid.UUID]domain.NotificationPreferences
+	emails map[uuid.UUID]string
+	inbox  map[[2]uuid.UUID]domain.Notification // (event_id, user_id)
+	sent   map[[2]uuid.UUID]bool                // (event_id, user_id), канал один — email
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		prefs:  map[uuid.UUID]domain.NotificationPreferences{},
+		emails: map[uuid.UUID]string{},
+		inbox:  map[[2]uuid.UUID]domain.Notification{},
+		sent:   map[[2]uuid.UUID]bool{},
+	}
+}
+
+func (s *fakeStore) GetPreferences(_ context.Context, userID uuid.UUID) (domain.NotificationPreferences, error) {
+	if p, ok := s.prefs[userID]; ok {
+		return p, nil
+	}
+	return domain.DefaultNotificationPreferences(userID), nil
+}
+
+func (s *fakeStore) GetRecipient(_ context.Context, userID uuid.UUID) (repo.Recipient, error) {
+	return repo.Recipient{UserID: userID, Email: s.emails[userID], FullName: "User " + s.emails[userID]}, nil
+}
+
+func (s *fakeStore) GroupTitles(_ context.Context, _ uuid.UUID) (string, string, error) {
+	return "Группа 1", "Python", nil
+}
+
+func (s *fakeStore) CreateInbox(_ context.Context, n domain.Notification) error {
+	k := [2]uuid.UUID{n.EventID, n.UserID}
+	if _, ok := s.inbox[k]; !ok {
+		s.inbox[k] = n
+	}
+	return nil
+}
+
+func (s *fakeStore) MarkSent(_ context.Context, eventID, userID uuid.UUID, _, _ string) (bool, error) {
+	k := [2]uuid.UUID{eventID, userID}
+	if s.sent[k] {
+		return false, nil
+	}
+	s.sent[k] = true
+	return true, nil
+}
+
+func (s *fakeStore) UnmarkSent(_ context.Context, eventID, userID uuid.UUID, _ string) error {
+	delete(s.sent, [2]uuid.UUID{eventID, userID})
+	return nil
+}
+
+type fakeGuardians map[uuid.UUID][]uuid.UUID
+
+func (g fakeGuardians) ListGuardians(_ context.Context, childID uuid.UUID) ([]uuid.UUID, error) {
+	return g[childID], nil
+}
+
+type noGroup struct{}
+
+func (noGroup) ListEnrolledUsersByGroup(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (noGroup) ListGroupTeachers(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+// fakeSender запоминает адресатов; err != nil — отправка не удалась
+type fakeSender struct {
+	to  []string
+	err error
+}
+
+func (s *fakeSender) Send(_ context.Context, m Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.to = append(s.to, m.To)
+	return nil
+}
+
+type family struct {
+	student, mother, father uuid.UUID
+	store                   *fakeStore
+	sender                  *fakeSender
+	n                       *Notifier
+}
+
+func newFamily(t *testing.T) *family {
+	t.Helper()
+	tpl, err := LoadTemplates(domain.LocaleRU)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &family{student: uuid.New(), mother: uuid.New(), father: uuid.New(), store: newFakeStore(), sender: &fakeSender{}}
+	f.store.emails[f.student] = "student@example.com"
+	f.store.emails[f.mother] = "mother@example.com"
+	f.store.emails[f.father] = "father@example.com"
+	guardians := fakeGuardians{f.student: {f.mother, f.father}}
+	f.n = NewNotifier(f.store, guardians, noGroup{}, noGroup{}, tpl, f.sender)
+	return f
+}
+
+func (f *family) approved() outbox.Event {
+	payload, _ := json.Marshal(map[string]any{
+		"application_id": uuid.New(),
+		"user_id":        f.student,
+		"group_id":       uuid.New(),
+		"from":           "in_review",
+		"to":             "approved",
+	})
+	return outbox.Event{ID: uuid.New(), EventType: "application.status_changed", Payload: payload}
+}
+
+func sorted(s []string) []string {
+	s = append([]string(nil), s...)
+	sort.Strings(s)
+	return s
+}
+
+func equal(a, b []string) bool {
+	a, b = sorted(a), sorted(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHandleNotifiesStudentAndGuardians(t *testing.T) {
+	f := newFamily(t)
+	e := f.approved()
+
+	if err := f.n.Handle(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"student@example.com", "mother@example.com", "father@example.com"}
+	if !equal(f.sender.to, want) {
+		t.Errorf("emails to %v, want %v", f.sender.to, want)
+	}
+	if len(f.store.inbox) != 3 {
+		t.Errorf("inbox has %d notifications, want 3", len(f.store.inbox))
+	}
+	if n := f.store.inbox[[2]uuid.UUID{e.ID, f.mother}]; n.Kind != "application.status_changed" || n.Title == "" {
+		t.Errorf("guardian notification = %+v", n)
+	}
+}
+
+func TestHandleSkipsMutedKind(t *testing.T) {
+	f := newFamily(t)
+	p := domain.DefaultNotificationPreferences(f.father)
+	p.MutedKinds = []string{"application.status_changed"}
+	f.store.prefs[f.father] = p
+
+	e := f.approved()
+	if err := f.n.Handle(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"student@example.com", "mother@example.com"}; !equal(f.sender.to, want) {
+		t.Errorf("emails to %v, want %v", f.sender.to, want)
+	}
+	if _, ok := f.store.inbox[[2]uuid.UUID{e.ID, f.father}]; ok {
+		t.Error("muted guardian got an inbox notification")
+	}
+}
+
+func TestHandleUnmarksFailedSend(t *testing.T) {
+	f := newFamily(t)
+	ctx := context.Background()
+	e := f.approved()
+
+	f.sender.err = errors.New("smtp down")
+	if err := f.n.Handle(ctx, e); err == nil {
+		t.Fatal("Handle succeeded with a failing sender")
+	}
+	if len(f.store.sent) != 0 {
+		t.Errorf("notification_log keeps %d marks after failed sends, want 0", len(f.store.sent))
+	}
+
+	// outbox повторяет событие — письма уходят
+	f.sender.err = nil
+	if err := f.n.Handle(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.sender.to) != 3 {
+		t.Errorf("sent %d emails on retry, want 3", len(f.sender.to))
+	}
+}
+
+func TestHandleReplayDoesNotEmailTwice(t *testing.T) {
+	f := newFamily(t)
+	ctx := context.Background()
+	e := f.approved()
+
+	for i := 0; i < 2; i++ {
+		if err := f.n.Handle(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(f.sender.to) != 3 || len(f.store.inbox) != 3 {
+		t.Errorf("emails = %d, inbox = %d after replay; want 3 and 3", len(f.sender.to), len(f.store.inbox))
+	}
+}
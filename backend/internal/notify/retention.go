@@ -0,0 +1,32 @@
+// internal/notify/retention.go
+
+package notify
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+// RunRetention раз в interval удаляет уведомления старше keep. Крутится до отмены ctx.
+func RunRetention(ctx context.Context, notifications *repo.NotificationRepo, keep, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		n, err := notifications.DeleteOlderThan(ctx, time.Now().Add(-keep))
+		if err != nil && ctx.Err() == nil {
+			slog.Error("notifications retention", "err", err)
+		} else if n > 0 {
+			slog.Info("notifications retention", "deleted", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
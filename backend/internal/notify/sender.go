@@ -0,0 +1,91 @@
+// internal/notify/sender.go
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string // text/plain
+}
+
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string // пусто — без AUTH (локальный MailHog / fake SMTP)
+	Password string
+	From     string
+}
+
+type SMTPSender struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPSender(cfg SMTPConfig) *SMTPSender { return &SMTPSender{cfg: cfg} }
+
+func (s *SMTPSender) Send(ctx context.Context, m Message) error {
+	var a smtp.Auth
+	if s.cfg.Username != "" {
+		a = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	msg, err := buildMessage(s.cfg.From, m)
+	if err != nil {
+		return err
+	}
+
+	// smtp.SendMail не принимает ctx — ждём в горутине, чтобы не держать диспетчер при отмене
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(s.cfg.Host, s.cfg.Port), a, s.cfg.From, []string{m.To}, msg)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func buildMessage(from string, m Message) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", m.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&b)
+	if _, err := qp.Write([]byte(m.Body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// LogSender — когда SMTP не настроен (локальная разработка)
+type LogSender struct{}
+
+func (LogSender) Send(ctx context.Context, m Message) error {
+	slog.Info("email (smtp not configured)", "to", m.To, "subject", m.Subject)
+	return nil
+}
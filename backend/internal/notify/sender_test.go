@@ -0,0 +1,65 @@
+// internal/notify/sender_test.go
+
+package notify
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestBuildMessage(t *testing.T) {
+	m := Message{
+		To:      "student@example.com",
+		Subject: "Заявка: одобрена",
+		Body:    "Здравствуйте, Иван!\n\n" + strings.Repeat("Материалы группы появятся в личном кабинете. ", 5) + "\n",
+	}
+	raw, err := buildMessage("school@example.com", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := msg.Header
+	if h.Get("From") != "school@example.com" || h.Get("To") != m.To {
+		t.Errorf("From = %q, To = %q", h.Get("From"), h.Get("To"))
+	}
+
+	// заголовок — только ASCII, кириллица в Q-encoding
+	subj := h.Get("Subject")
+	if !strings.HasPrefix(subj, "=?utf-8?q?") {
+		t.Errorf("Subject is not Q-encoded: %q", subj)
+	}
+	if got, err := new(mime.WordDecoder).DecodeHeader(subj); err != nil || got != m.Subject {
+		t.Errorf("decoded Subject = %q, %v", got, err)
+	}
+
+	if h.Get("Content-Transfer-Encoding") != "quoted-printable" {
+		t.Errorf("Content-Transfer-Encoding = %q", h.Get("Content-Transfer-Encoding"))
+	}
+	if ct := h.Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	encoded, _ := io.ReadAll(msg.Body)
+	for _, line := range strings.Split(string(encoded), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("body line longer than 76 chars: %q", line)
+		}
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(encoded)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// quotedprintable пишет переводы строк как CRLF
+	if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != m.Body {
+		t.Errorf("decoded body = %q, want %q", got, m.Body)
+	}
+}
@@ -0,0 +1,161 @@
+// internal/notify/templates.go
+
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+//go:embed templates/*/*.tmpl
+var templateFS embed.FS
+
+// TemplateData — то, что видят шаблоны
+type TemplateData struct {
+	Name        string         // получатель
+	Student     string         // о ком письмо (для guardian — ребёнок)
+	ForGuardian bool           // письмо родителю/опекуну, а не самому ученику
+	Group       string         // название группы
+	Program     string         // название программы
+	Event       map[string]any // payload события
+}
+
+var statusNames = map[string]map[string]string{
+	domain.LocaleRU: {
+		"submitted":  "отправлена",
+		"in_review":  "на рассмотрении",
+		"waitlisted": "в листе ожидания",
+		"approved":   "одобрена",
+		"rejected":   "отклонена",
+		"cancelled":  "отменена",
+		// зачисление
+		"active":      "учится",
+		"withdrawn":   "выбыл(а)",
+		"expelled":    "отчислен(а)",
+		"completed":   "курс окончен",
+		"transferred": "переведён(а) в другую группу",
+	},
+	domain.LocaleEN: {
+		"submitted":  "submitted",
+		"in_review":  "in review",
+		"waitlisted": "waitlisted",
+		"approved":   "approved",
+		"rejected":   "rejected",
+		"cancelled":  "cancelled",
+		// enrollment
+		"active":      "active",
+		"withdrawn":   "withdrawn",
+		"expelled":    "expelled",
+		"completed":   "completed",
+		"transferred": "transferred",
+	},
+}
+
+var interviewNames = map[string]map[string]string{
+	domain.LocaleRU: {
+		"pending":         "ожидается",
+		"recommended":     "рекомендован",
+		"not_recommended": "не рекомендован",
+		"needs_more":      "нужно дополнительное собеседование",
+	},
+	domain.LocaleEN: {
+		"pending":         "pending",
+		"recommended":     "recommended",
+		"not_recommended": "not recommended",
+		"needs_more":      "another interview needed",
+	},
+}
+
+// Templates: locale -> kind (event_type) -> шаблон с блоками subject и body
+type Templates struct {
+	byLocale      map[string]map[string]*template.Template
+	defaultLocale string
+}
+
+func LoadTemplates(defaultLocale string) (*Templates, error) {
+	t := &Templates{byLocale: map[string]map[string]*template.Template{}, defaultLocale: defaultLocale}
+
+	files, err := fs.Glob(templateFS, "templates/*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		locale := path.Base(path.Dir(f))
+		kind := strings.TrimSuffix(path.Base(f), ".tmpl")
+
+		tpl, err := template.New(kind).Funcs(funcs(locale)).ParseFS(templateFS, f)
+		if err != nil {
+			return nil, err
+		}
+		if t.byLocale[locale] == nil {
+			t.byLocale[locale] = map[string]*template.Template{}
+		}
+		t.byLocale[locale][kind] = tpl
+	}
+	if _, ok := t.byLocale[defaultLocale]; !ok {
+		return nil, fmt.Errorf("no templates for default locale %q", defaultLocale)
+	}
+	return t, nil
+}
+
+func funcs(locale string) template.FuncMap {
+	lookup := func(m map[string]map[string]string) func(v any) string {
+		return func(v any) string {
+			s := fmt.Sprint(v)
+			if n, ok := m[locale][s]; ok {
+				return n
+			}
+			return s
+		}
+	}
+	layout := "02.01.2006 15:04"
+	if locale == domain.LocaleEN {
+		layout = "Jan 2, 2006 15:04"
+	}
+	return template.FuncMap{
+		"status":    lookup(statusNames),
+		"interview": lookup(interviewNames),
+		// date: RFC3339 из payload -> дата для письма
+		"date": func(v any) string {
+			s := fmt.Sprint(v)
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return s
+			}
+			return t.Format(layout)
+		},
+	}
+}
+
+func (t *Templates) Has(kind string) bool {
+	_, ok := t.byLocale[t.defaultLocale][kind]
+	return ok
+}
+
+// Render: шаблон нужной локали, если его нет — локали по умолчанию
+func (t *Templates) Render(locale, kind string, data TemplateData) (string, string, error) {
+	tpl, ok := t.byLocale[locale][kind]
+	if !ok {
+		tpl, ok = t.byLocale[t.defaultLocale][kind]
+	}
+	if !ok {
+		return "", "", fmt.Errorf("no template %q", kind)
+	}
+
+	var subj, body bytes.Buffer
+	if err := tpl.ExecuteTemplate(&subj, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subj.String()), strings.TrimSpace(body.String()) + "\n", nil
+}
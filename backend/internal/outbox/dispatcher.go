@@ -0,0 +1,147 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Sink — получатель событий (правила, вебхуки, уведомления...).
+// Событие повторяется целиком, пока все sinks не ответят nil, поэтому Handle должен быть идемпотентным.
+type Sink interface {
+	Name() string
+	Handle(ctx context.Context, e Event) error
+}
+
+// ErrPermanent: повтор бессмысленен (битый payload и т.п.) — сразу в dead letters
+var ErrPermanent = errors.New("permanent failure")
+
+type DispatcherConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Lease        time.Duration // сколько событие считается "занятым" одним диспетчером
+}
+
+type Dispatcher struct {
+	repo  *Repo
+	sinks []Sink
+	cfg   DispatcherConfig
+}
+
+func NewDispatcher(repo *Repo, cfg DispatcherConfig, sinks ...Sink) *Dispatcher {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 5 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = time.Hour
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = time.Minute
+	}
+	return &Dispatcher{repo: repo, sinks: sinks, cfg: cfg}
+}
+
+// Run крутится до отмены ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	t := time.NewTicker(d.cfg.PollInterval)
+	defer t.Stop()
+
+	for {
+		// пачка была полной — сразу берём следующую, не дожидаясь тика
+		n, err := d.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("outbox dispatch", "err", err)
+		}
+		// после ошибки ждём тика, чтобы не крутиться вхолостую на больной БД
+		if err == nil && n == d.cfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce: одна пачка, возвращает сколько событий было взято.
+// Ошибка по одному событию не останавливает остальные: иначе они висят под lease до его истечения.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	events, err := d.repo.Claim(ctx, d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+	failed := 0
+	for _, e := range events {
+		if err := d.dispatch(ctx, e); err != nil {
+			slog.Error("outbox dispatch event", "id", e.ID, "type", e.EventType, "err", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return len(events), fmt.Errorf("%d of %d events not dispatched", failed, len(events))
+	}
+	return len(events), nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, e Event) error {
+	err := d.deliver(ctx, e)
+	if err == nil {
+		return d.repo.MarkPublished(ctx, e.ID)
+	}
+
+	attempt := e.Attempts + 1
+	if errors.Is(err, ErrPermanent) || attempt >= d.cfg.MaxAttempts {
+		slog.Warn("outbox event dead-lettered", "id", e.ID, "type", e.EventType, "attempts", attempt, "err", err)
+		return d.repo.MoveToDeadLetter(ctx, e.ID, err.Error())
+	}
+
+	slog.Warn("outbox delivery failed", "id", e.ID, "type", e.EventType, "attempt", attempt, "err", err)
+	return d.repo.MarkFailed(ctx, e.ID, time.Now().Add(d.backoff(attempt)), err.Error())
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, e Event) error {
+	for _, s := range d.sinks {
+		if err := s.Handle(ctx, e); err != nil {
+			return fmt.Errorf("%s: %w", s.Name(), err)
+		}
+	}
+	return nil
+}
+
+// backoff: base * 2^(attempt-1), не больше MaxBackoff
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	b := d.cfg.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		b *= 2
+		if b >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return b
+}
+
+// LogSink просто пишет события в лог — sink по умолчанию
+type LogSink struct{}
+
+func (LogSink) Name() string { return "log" }
+
+func (LogSink) Handle(ctx context.Context, e Event) error {
+	slog.Info("outbox event", "id", e.ID, "type", e.EventType, "aggregate", e.AggregateType, "aggregate_id", e.AggregateID)
+	return nil
+}
@@ -0,0 +1,248 @@
+// internal/outbox/payloads.go
+
+package outbox
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы событий. Имена полей payload — контракт для правил (/admin/rules), вебхуков и SSE:
+// переименование или смена смысла поля = новая версия (Version) и новая структура, старую не трогаем.
+const (
+	EventApplicationCreated       = "application.created"
+	EventApplicationStatusChanged = "application.status_changed"
+	EventApplicationCancelled     = "application.cancelled"
+	EventApplicationPromoted      = "application.promoted"
+	EventApplicationMessagePosted = "application.message_posted"
+	EventEnrollmentStatusChanged  = "enrollment.status_changed"
+	EventAdmissionFinalized       = "admission.finalized"
+	EventEnrollmentTransferred    = "enrollment.transferred"
+	EventInterviewRecorded        = "interview.recorded"
+	EventInterviewSlotBooked      = "interview.slot_booked"
+	EventInterviewSlotCancelled   = "interview.slot_cancelled"
+	EventInterviewReminder        = "interview.reminder"
+	EventSubmissionReviewed       = "submission.reviewed"
+	EventMaterialCreated          = "material.created"
+	EventAssignmentCreated        = "assignment.created"
+	EventNotificationRequested    = "notification.requested"
+)
+
+// Payload — типизированное тело события
+type Payload interface {
+	EventType() string
+	Version() int
+}
+
+type ApplicationCreatedV1 struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	GroupID       uuid.UUID `json:"group_id"`
+	SubmittedBy   uuid.UUID `json:"submitted_by"`
+}
+
+func (ApplicationCreatedV1) EventType() string { return EventApplicationCreated }
+func (ApplicationCreatedV1) Version() int      { return 1 }
+
+type ApplicationStatusChangedV1 struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	GroupID       uuid.UUID `json:"group_id"`
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	ActorRole     string    `json:"actor_role"`
+	Reason        string    `json:"reason"`
+}
+
+func (ApplicationStatusChangedV1) EventType() string { return EventApplicationStatusChanged }
+func (ApplicationStatusChangedV1) Version() int      { return 1 }
+
+type ApplicationCancelledV1 struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	GroupID       uuid.UUID `json:"group_id"`
+	CancelledBy   uuid.UUID `json:"cancelled_by"`
+}
+
+func (ApplicationCancelledV1) EventType() string { return EventApplicationCancelled }
+func (ApplicationCancelledV1) Version() int      { return 1 }
+
+// ApplicationPromotedV1: заявка из листа ожидания получила освободившееся место
+// (вместе с ним пишется обычный application.status_changed waitlisted -> approved)
+type ApplicationPromotedV1 struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	GroupID       uuid.UUID `json:"group_id"`
+}
+
+func (ApplicationPromotedV1) EventType() string { return EventApplicationPromoted }
+func (ApplicationPromotedV1) Version() int      { return 1 }
+
+type EnrollmentStatusChangedV1 struct {
+	EnrollmentID uuid.UUID `json:"enrollment_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	GroupID      uuid.UUID `json:"group_id"`
+	From         string    `json:"from"`
+	To           string    `json:"to"`
+	ActorRole    string    `json:"actor_role"`
+	Reason       string    `json:"reason"`
+}
+
+func (EnrollmentStatusChangedV1) EventType() string { return EventEnrollmentStatusChanged }
+func (EnrollmentStatusChangedV1) Version() int      { return 1 }
+
+// EnrollmentTransferredV1: group_id — целевая группа (по ней фильтруются SSE и уведомления)
+type EnrollmentTransferredV1 struct {
+	TransferID         uuid.UUID `json:"transfer_id"`
+	UserID             uuid.UUID `json:"user_id"`
+	FromEnrollmentID   uuid.UUID `json:"from_enrollment_id"`
+	ToEnrollmentID     uuid.UUID `json:"to_enrollment_id"`
+	FromGroupID        uuid.UUID `json:"from_group_id"`
+	GroupID            uuid.UUID `json:"group_id"`
+	ActorRole          string    `json:"actor_role"`
+	Reason             string    `json:"reason"`
+	CarriedReads       int       `json:"carried_reads"`
+	CarriedSubmissions int       `json:"carried_submissions"`
+}
+
+func (EnrollmentTransferredV1) EventType() string { return EventEnrollmentTransferred }
+func (EnrollmentTransferredV1) Version() int      { return 1 }
+
+// ApplicationMessagePostedV1: новое сообщение в переписке по заявке.
+// Internal-сообщения в SSE видят только staff и преподаватели группы.
+type ApplicationMessagePostedV1 struct {
+	MessageID     uuid.UUID `json:"message_id"`
+	ApplicationID uuid.UUID `json:"application_id"`
+	UserID        uuid.UUID `json:"user_id"` // заявитель
+	GroupID       uuid.UUID `json:"group_id"`
+	AuthorID      uuid.UUID `json:"author_id"`
+	AuthorRole    string    `json:"author_role"`
+	FromApplicant bool      `json:"from_applicant"`
+	Internal      bool      `json:"internal"`
+	Body          string    `json:"body"`
+}
+
+func (ApplicationMessagePostedV1) EventType() string { return EventApplicationMessagePosted }
+func (ApplicationMessagePostedV1) Version() int      { return 1 }
+
+// AdmissionFinalizedV1: итоги конкурса группы (по каждой заявке — отдельное application.status_changed)
+type AdmissionFinalizedV1 struct {
+	GroupID    uuid.UUID `json:"group_id"`
+	Seats      int       `json:"seats"`
+	Approved   int       `json:"approved"`
+	Waitlisted int       `json:"waitlisted"`
+	Rejected   int       `json:"rejected"`
+	ActorRole  string    `json:"actor_role"`
+}
+
+func (AdmissionFinalizedV1) EventType() string { return EventAdmissionFinalized }
+func (AdmissionFinalizedV1) Version() int      { return 1 }
+
+type InterviewRecordedV1 struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	GroupID       uuid.UUID `json:"group_id"`
+	CandidateID   uuid.UUID `json:"candidate_id"`
+	UserID        uuid.UUID `json:"user_id"` // = candidate_id, общее поле для фильтров
+	Result        string    `json:"result"`
+	ActorRole     string    `json:"actor_role"`
+}
+
+func (InterviewRecordedV1) EventType() string { return EventInterviewRecorded }
+func (InterviewRecordedV1) Version() int      { return 1 }
+
+// InterviewSlotBookedV1: заявитель забронировал слот собеседования (или перенёс — PreviousSlotID)
+type InterviewSlotBookedV1 struct {
+	SlotID         uuid.UUID  `json:"slot_id"`
+	ApplicationID  uuid.UUID  `json:"application_id"`
+	UserID         uuid.UUID  `json:"user_id"` // заявитель
+	GroupID        uuid.UUID  `json:"group_id"`
+	TeacherID      uuid.UUID  `json:"teacher_id"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         time.Time  `json:"ends_at"`
+	Location       string     `json:"location"`
+	OnlineURL      string     `json:"online_url"`
+	PreviousSlotID *uuid.UUID `json:"previous_slot_id"`
+	ActorRole      string     `json:"actor_role"`
+}
+
+func (InterviewSlotBookedV1) EventType() string { return EventInterviewSlotBooked }
+func (InterviewSlotBookedV1) Version() int      { return 1 }
+
+// InterviewSlotCancelledV1: бронь снята заявителем или слот удалил преподаватель (CancelledBy: applicant|teacher)
+type InterviewSlotCancelledV1 struct {
+	SlotID        uuid.UUID `json:"slot_id"`
+	ApplicationID uuid.UUID `json:"application_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	GroupID       uuid.UUID `json:"group_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	CancelledBy   string    `json:"cancelled_by"`
+	ActorRole     string    `json:"actor_role"`
+}
+
+func (InterviewSlotCancelledV1) EventType() string { return EventInterviewSlotCancelled }
+func (InterviewSlotCancelledV1) Version() int      { return 1 }
+
+// InterviewReminderV1: до собеседования осталось меньше INTERVIEW_REMINDER_BEFORE (одно на бронь)
+type InterviewReminderV1 struct {
+	SlotID        uuid.UUID `json:"slot_id"`
+	ApplicationID uuid.UUID `json:"application_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	GroupID       uuid.UUID `json:"group_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	Location      string    `json:"location"`
+	OnlineURL     string    `json:"online_url"`
+}
+
+func (InterviewReminderV1) EventType() string { return EventInterviewReminder }
+func (InterviewReminderV1) Version() int      { return 1 }
+
+type SubmissionReviewedV1 struct {
+	SubmissionID    uuid.UUID `json:"submission_id"`
+	AssignmentID    uuid.UUID `json:"assignment_id"`
+	AssignmentTitle string    `json:"assignment_title"`
+	GroupID         uuid.UUID `json:"group_id"`
+	UserID          uuid.UUID `json:"user_id"`
+	Grade           *int      `json:"grade"`
+	Comment         string    `json:"comment"`
+}
+
+func (SubmissionReviewedV1) EventType() string { return EventSubmissionReviewed }
+func (SubmissionReviewedV1) Version() int      { return 1 }
+
+type MaterialCreatedV1 struct {
+	MaterialID uuid.UUID `json:"material_id"`
+	GroupID    uuid.UUID `json:"group_id"`
+	Title      string    `json:"title"`
+	Type       string    `json:"type"`
+}
+
+func (MaterialCreatedV1) EventType() string { return EventMaterialCreated }
+func (MaterialCreatedV1) Version() int      { return 1 }
+
+type AssignmentCreatedV1 struct {
+	AssignmentID uuid.UUID  `json:"assignment_id"`
+	GroupID      uuid.UUID  `json:"group_id"`
+	Title        string     `json:"title"`
+	DueAt        *time.Time `json:"due_at"`
+}
+
+func (AssignmentCreatedV1) EventType() string { return EventAssignmentCreated }
+func (AssignmentCreatedV1) Version() int      { return 1 }
+
+// NotificationRequestedV1 ставит правило автоматизации (action notify)
+type NotificationRequestedV1 struct {
+	UserID          uuid.UUID      `json:"user_id"`
+	Template        string         `json:"template"`
+	RuleID          uuid.UUID      `json:"rule_id"`
+	SourceEventID   uuid.UUID      `json:"source_event_id"`
+	SourceEventType string         `json:"source_event_type"`
+	Data            map[string]any `json:"data"` // payload исходного события
+}
+
+func (NotificationRequestedV1) EventType() string { return EventNotificationRequested }
+func (NotificationRequestedV1) Version() int      { return 1 }
@@ -3,20 +3,182 @@ package outbox
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/db"
 )
 
 type Repo struct{ db *pgxpool.Pool }
 
 func New(db *pgxpool.Pool) *Repo { return &Repo{db: db} }
 
-func (r *Repo) Add(ctx context.Context, aggregateType string, aggregateID uuid.UUID, eventType string, payload map[string]any) error {
-	b, _ := json.Marshal(payload)
-	_, err := r.db.Exec(ctx, `
-		insert into outbox_events(id, aggregate_type, aggregate_id, event_type, payload)
-		values ($1,$2,$3,$4,$5)
-	`, uuid.New(), aggregateType, aggregateID, eventType, b)
+// Add пишет событие через транзакцию из ctx (db.TxManager.Do): вызывайте его внутри той же Do,
+// что и изменение данных — тогда событие и изменение коммитятся или откатываются вместе.
+func (r *Repo) Add(ctx context.Context, aggregateType string, aggregateID uuid.UUID, p Payload) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = db.Conn(ctx, r.db).Exec(ctx, `
+		insert into outbox_events(id, aggregate_type, aggregate_id, event_type, version, payload)
+		values ($1,$2,$3,$4,$5,$6)
+	`, uuid.New(), aggregateType, aggregateID, p.EventType(), p.Version(), b)
+	return err
+}
+
+// Event — строка outbox_events, как её видят диспетчер и sinks
+type Event struct {
+	Seq           int64 // порядковый номер (SSE id)
+	ID            uuid.UUID
+	AggregateType string
+	AggregateID   uuid.UUID
+	EventType     string
+	Version       int // версия схемы payload
+	Payload       json.RawMessage
+	CreatedAt     time.Time
+	Attempts      int
+}
+
+// Claim забирает пачку готовых к доставке событий и сдвигает их next_attempt_at на lease:
+// параллельные диспетчеры не возьмут те же строки (SKIP LOCKED), а упавший — отпустит их по истечении lease.
+func (r *Repo) Claim(ctx context.Context, limit int, lease time.Duration) ([]Event, error) {
+	rows, err := db.Conn(ctx, r.db).Query(ctx, `
+		update outbox_events e
+		set next_attempt_at = now() + make_interval(secs => $2)
+		where e.id in (
+			select id from outbox_events
+			where published_at is null and next_attempt_at <= now()
+			order by created_at
+			limit $1
+			for update skip locked
+		)
+		returning e.seq, e.id, e.aggregate_type, e.aggregate_id, e.event_type, e.version, e.payload, e.created_at, e.attempts
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.Seq, &e.ID, &e.AggregateType, &e.AggregateID, &e.EventType, &e.Version, &e.Payload, &e.CreatedAt, &e.Attempts); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// ListSince: события с seq > after по порядку (SSE, включая уже опубликованные)
+func (r *Repo) ListSince(ctx context.Context, after int64, limit int) ([]Event, error) {
+	rows, err := db.Conn(ctx, r.db).Query(ctx, `
+		select seq, id, aggregate_type, aggregate_id, event_type, version, payload, created_at, attempts
+		from outbox_events
+		where seq > $1
+		order by seq
+		limit $2
+	`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.Seq, &e.ID, &e.AggregateType, &e.AggregateID, &e.EventType, &e.Version, &e.Payload, &e.CreatedAt, &e.Attempts); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *Repo) LatestSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := db.Conn(ctx, r.db).QueryRow(ctx, `select coalesce(max(seq), 0) from outbox_events`).Scan(&seq)
+	return seq, err
+}
+
+func (r *Repo) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	_, err := db.Conn(ctx, r.db).Exec(ctx, `
+		update outbox_events
+		set published_at=now(), last_error=null
+		where id=$1
+	`, id)
 	return err
 }
+
+// MarkFailed: +1 попытка, следующая не раньше next
+func (r *Repo) MarkFailed(ctx context.Context, id uuid.UUID, next time.Time, lastErr string) error {
+	_, err := db.Conn(ctx, r.db).Exec(ctx, `
+		update outbox_events
+		set attempts=attempts+1, next_attempt_at=$2, last_error=$3
+		where id=$1
+	`, id, next, lastErr)
+	return err
+}
+
+// MoveToDeadLetter переносит событие одним запросом (delete ... returning -> insert)
+func (r *Repo) MoveToDeadLetter(ctx context.Context, id uuid.UUID, lastErr string) error {
+	_, err := db.Conn(ctx, r.db).Exec(ctx, `
+		with e as (
+			delete from outbox_events where id=$1
+			returning id, aggregate_type, aggregate_id, event_type, version, payload, created_at, attempts
+		)
+		insert into outbox_dead_letters(id, aggregate_type, aggregate_id, event_type, version, payload, created_at, attempts, last_error)
+		select id, aggregate_type, aggregate_id, event_type, version, payload, created_at, attempts+1, $2 from e
+	`, id, lastErr)
+	return err
+}
+
+// -------- Dead letters --------
+
+type DeadLetter struct {
+	Event
+	LastError *string
+	DeadAt    time.Time
+}
+
+func (r *Repo) ListDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	rows, err := db.Conn(ctx, r.db).Query(ctx, `
+		select id, aggregate_type, aggregate_id, event_type, version, payload, created_at, attempts, last_error, dead_at
+		from outbox_dead_letters
+		order by dead_at desc
+		limit $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]DeadLetter, 0)
+	for rows.Next() {
+		var d DeadLetter
+		if err := rows.Scan(&d.ID, &d.AggregateType, &d.AggregateID, &d.EventType, &d.Version, &d.Payload, &d.CreatedAt, &d.Attempts, &d.LastError, &d.DeadAt); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// Replay возвращает событие в outbox_events с обнулёнными попытками. false — такого dead letter нет.
+func (r *Repo) Replay(ctx context.Context, id uuid.UUID) (bool, error) {
+	ct, err := db.Conn(ctx, r.db).Exec(ctx, `
+		with d as (
+			delete from outbox_dead_letters where id=$1
+			returning id, aggregate_type, aggregate_id, event_type, version, payload, created_at
+		)
+		insert into outbox_events(id, aggregate_type, aggregate_id, event_type, version, payload, created_at)
+		select id, aggregate_type, aggregate_id, event_type, version, payload, created_at from d
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
@@ -0,0 +1,125 @@
+// internal/repo/admission_repo.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type AdmissionRepo struct{ db *pgxpool.Pool }
+
+func NewAdmissionRepo(db *pgxpool.Pool) *AdmissionRepo { return &AdmissionRepo{db: db} }
+
+func (r *AdmissionRepo) Get(ctx context.Context, groupID uuid.UUID) (domain.GroupAdmission, error) {
+	a := domain.GroupAdmission{GroupID: groupID}
+	var mode string
+	var scoring []byte
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select admission_mode, admission_scoring, admission_finalized_at
+		from groups
+		where id=$1
+	`, groupID).Scan(&mode, &scoring, &a.FinalizedAt)
+	if err != nil {
+		return domain.GroupAdmission{}, err
+	}
+	a.Mode = domain.AdmissionMode(mode)
+	if err := json.Unmarshal(scoring, &a.Scoring); err != nil {
+		return domain.GroupAdmission{}, err
+	}
+	return a, nil
+}
+
+// Set: режим и правила баллов (только пока конкурс не подведён)
+func (r *AdmissionRepo) Set(ctx context.Context, groupID uuid.UUID, mode domain.AdmissionMode, scoring domain.ScoringRules) (bool, error) {
+	b, err := json.Marshal(scoring)
+	if err != nil {
+		return false, err
+	}
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update groups
+		set admission_mode=$2, admission_scoring=$3
+		where id=$1 and admission_finalized_at is null
+	`, groupID, string(mode), b)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *AdmissionRepo) MarkFinalized(ctx context.Context, groupID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `update groups set admission_finalized_at=now() where id=$1`, groupID)
+	return err
+}
+
+func (r *AdmissionRepo) UpsertScore(ctx context.Context, s domain.ApplicationScore) (domain.ApplicationScore, error) {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		insert into application_scores(application_id, manual_points, comment, updated_by)
+		values ($1,$2,$3,$4)
+		on conflict (application_id)
+		do update set manual_points=excluded.manual_points, comment=excluded.comment,
+			updated_by=excluded.updated_by, updated_at=now()
+		returning updated_at
+	`, s.ApplicationID, s.ManualPoints, s.Comment, s.UpdatedBy).Scan(&s.UpdatedAt)
+	return s, err
+}
+
+// ListCandidates: заявки группы, участвующие в конкурсе (in_review, waitlisted), с собеседованием и ручными баллами.
+// forUpdate — блокировка заявок (подведение конкурса).
+func (r *AdmissionRepo) ListCandidates(ctx context.Context, groupID uuid.UUID, forUpdate bool) ([]domain.RankedApplication, error) {
+	q := `
+		select a.id, a.user_id, a.status, a.created_at, a.answers,
+		       pr.full_name, i.result, coalesce(sc.manual_points, 0)
+		from enrollment_applications a
+		left join profiles pr on pr.user_id = a.user_id
+		left join interviews i on i.application_id = a.id
+		left join application_scores sc on sc.application_id = a.id
+		where a.group_id=$1 and a.status in ('in_review','waitlisted')
+		order by a.created_at asc, a.id asc
+	`
+	if forUpdate {
+		q += " for update of a"
+	}
+	rows, err := conn(ctx, r.db).Query(ctx, q, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.RankedApplication, 0)
+	for rows.Next() {
+		var a domain.RankedApplication
+		var st string
+		var result *string
+		if err := rows.Scan(&a.ApplicationID, &a.UserID, &st, &a.CreatedAt, &a.Answers,
+			&a.ApplicantName, &result, &a.ManualPoints); err != nil {
+			return nil, err
+		}
+		a.Status = domain.ApplicationStatus(st)
+		if result != nil {
+			ir := domain.InterviewResult(*result)
+			a.InterviewResult = &ir
+		}
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
+
+// CountUnreviewed: заявки группы в статусе submitted (в конкурсе не участвуют)
+func (r *AdmissionRepo) CountUnreviewed(ctx context.Context, groupID uuid.UUID) (int, error) {
+	var n int
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select count(*) from enrollment_applications where group_id=$1 and status='submitted'
+	`, groupID).Scan(&n)
+	return n, err
+}
+
+func (r *AdmissionRepo) SetRank(ctx context.Context, appID uuid.UUID, rank int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `update enrollment_applications set admission_rank=$2 where id=$1`, appID, rank)
+	return err
+}
@@ -0,0 +1,51 @@
+// internal/repo/application_message_repo.go
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type ApplicationMessageRepo struct{ db *pgxpool.Pool }
+
+func NewApplicationMessageRepo(db *pgxpool.Pool) *ApplicationMessageRepo {
+	return &ApplicationMessageRepo{db: db}
+}
+
+func (r *ApplicationMessageRepo) Create(ctx context.Context, m domain.ApplicationMessage) (domain.ApplicationMessage, error) {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		insert into application_messages(id, application_id, author_user_id, author_role, from_applicant, body, internal)
+		values ($1,$2,$3,$4,$5,$6,$7)
+		returning created_at
+	`, m.ID, m.ApplicationID, m.AuthorUserID, m.AuthorRole, m.FromApplicant, m.Body, m.Internal).Scan(&m.CreatedAt)
+	return m, err
+}
+
+// List: переписка по заявке по времени; withInternal=false — без заметок staff (для заявителя)
+func (r *ApplicationMessageRepo) List(ctx context.Context, appID uuid.UUID, withInternal bool) ([]domain.ApplicationMessage, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select id, application_id, author_user_id, author_role, from_applicant, body, internal, created_at
+		from application_messages
+		where application_id=$1 and ($2 or not internal)
+		order by created_at asc, id asc
+	`, appID, withInternal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.ApplicationMessage, 0)
+	for rows.Next() {
+		var m domain.ApplicationMessage
+		if err := rows.Scan(&m.ID, &m.ApplicationID, &m.AuthorUserID, &m.AuthorRole, &m.FromApplicant, &m.Body, &m.Internal, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, rows.Err()
+}
@@ -4,6 +4,7 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -26,6 +27,7 @@ type ApplicationView struct {
 	GroupID   uuid.UUID
 	Status    string
 	Comment   string
+	Answers   json.RawMessage // ответы на анкету
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
@@ -39,24 +41,44 @@ type ApplicationView struct {
 	ProgramTitle string
 	GroupTitle   string
 	CohortYear   *int
+
+	// профиль заявителя (nil если профиль не заполнен)
+	ApplicantName   *string
+	ApplicantSchool *string
+	ApplicantGrade  *int
+	ApplicantEmail  *string
+	ApplicantPhone  *string
+}
+
+// EnrolledStudentView: зачисленный студент + краткий профиль
+type EnrolledStudentView struct {
+	UserID     uuid.UUID
+	FullName   *string
+	School     *string
+	Grade      *int
+	Email      *string
+	Phone      *string
+	EnrolledAt time.Time
 }
 
 func NewApplicationRepo(db *pgxpool.Pool) *ApplicationRepo {
 	return &ApplicationRepo{db: db}
 }
 
-// NEW signature: + year
-func (r *ApplicationRepo) ListByFilterView(ctx context.Context, groupID *uuid.UUID, status *string, year *int) ([]ApplicationView, error) {
+// NEW signature: + year, + answers (jsonb @>)
+func (r *ApplicationRepo) ListByFilterView(ctx context.Context, groupID *uuid.UUID, status *string, year *int, answers []byte) ([]ApplicationView, error) {
 	q := `
-		select a.id, a.user_id, a.group_id, a.status, a.comment, a.created_at, a.updated_at,
+		select a.id, a.user_id, a.group_id, a.status, a.comment, a.answers, a.created_at, a.updated_at,
 		       p.id as program_id, p.title as program_title, g.title as group_title,
 			   c.year as cohort_year,
-		       i.result, i.comment, i.interviewer_role, i.updated_at
+		       i.result, i.comment, i.interviewer_role, i.updated_at,
+		       pr.full_name, pr.school, pr.grade, pr.contact_email, pr.contact_phone
 		from enrollment_applications a
 		join groups g on g.id = a.group_id
   		join programs p on p.id = g.program_id
 		left join cohorts c on c.id = g.cohort_id
 		left join interviews i on i.application_id = a.id
+		left join profiles pr on pr.user_id = a.user_id
 		where 1=1
 	`
 	args := []any{}
@@ -77,10 +99,15 @@ func (r *ApplicationRepo) ListByFilterView(ctx context.Context, groupID *uuid.UU
 		args = append(args, *year)
 		i++
 	}
+	if answers != nil {
+		q += " and a.answers @> $" + strconv.Itoa(i) + "::jsonb"
+		args = append(args, answers)
+		i++
+	}
 
 	q += " order by a.created_at desc"
 
-	rows, err := r.db.Query(ctx, q, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -90,10 +117,11 @@ func (r *ApplicationRepo) ListByFilterView(ctx context.Context, groupID *uuid.UU
 	for rows.Next() {
 		var a ApplicationView
 		if err := rows.Scan(
-			&a.ID, &a.UserID, &a.GroupID, &a.Status, &a.Comment, &a.CreatedAt, &a.UpdatedAt,
+			&a.ID, &a.UserID, &a.GroupID, &a.Status, &a.Comment, &a.Answers, &a.CreatedAt, &a.UpdatedAt,
 			&a.ProgramID, &a.ProgramTitle, &a.GroupTitle,
 			&a.CohortYear,
 			&a.InterviewResult, &a.InterviewComment, &a.InterviewByRole, &a.InterviewAt,
+			&a.ApplicantName, &a.ApplicantSchool, &a.ApplicantGrade, &a.ApplicantEmail, &a.ApplicantPhone,
 		); err != nil {
 			return nil, err
 		}
@@ -102,18 +130,20 @@ func (r *ApplicationRepo) ListByFilterView(ctx context.Context, groupID *uuid.UU
 	return res, rows.Err()
 }
 
-// NEW signature: + year
-func (r *ApplicationRepo) ListByProgramView(ctx context.Context, programID uuid.UUID, status *string, year *int) ([]ApplicationView, error) {
+// NEW signature: + year, + answers (jsonb @>)
+func (r *ApplicationRepo) ListByProgramView(ctx context.Context, programID uuid.UUID, status *string, year *int, answers []byte) ([]ApplicationView, error) {
 	q := `
-		select a.id, a.user_id, a.group_id, a.status, a.comment, a.created_at, a.updated_at,
+		select a.id, a.user_id, a.group_id, a.status, a.comment, a.answers, a.created_at, a.updated_at,
 		       p.id as program_id, p.title as program_title, g.title as group_title,
 			   c.year as cohort_year,
-		       i.result, i.comment, i.interviewer_role, i.updated_at
+		       i.result, i.comment, i.interviewer_role, i.updated_at,
+		       pr.full_name, pr.school, pr.grade, pr.contact_email, pr.contact_phone
 		from enrollment_applications a
 		join groups g on g.id = a.group_id
   		join programs p on p.id = g.program_id
 		left join cohorts c on c.id = g.cohort_id
 		left join interviews i on i.application_id = a.id
+		left join profiles pr on pr.user_id = a.user_id
 		where g.program_id = $1
 	`
 	args := []any{programID}
@@ -129,9 +159,14 @@ func (r *ApplicationRepo) ListByProgramView(ctx context.Context, programID uuid.
 		args = append(args, *year)
 		i++
 	}
+	if answers != nil {
+		q += " and a.answers @> $" + strconv.Itoa(i) + "::jsonb"
+		args = append(args, answers)
+		i++
+	}
 	q += " order by a.created_at desc"
 
-	rows, err := r.db.Query(ctx, q, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -141,10 +176,11 @@ func (r *ApplicationRepo) ListByProgramView(ctx context.Context, programID uuid.
 	for rows.Next() {
 		var a ApplicationView
 		if err := rows.Scan(
-			&a.ID, &a.UserID, &a.GroupID, &a.Status, &a.Comment, &a.CreatedAt, &a.UpdatedAt,
+			&a.ID, &a.UserID, &a.GroupID, &a.Status, &a.Comment, &a.Answers, &a.CreatedAt, &a.UpdatedAt,
 			&a.ProgramID, &a.ProgramTitle, &a.GroupTitle,
 			&a.CohortYear,
 			&a.InterviewResult, &a.InterviewComment, &a.InterviewByRole, &a.InterviewAt,
+			&a.ApplicantName, &a.ApplicantSchool, &a.ApplicantGrade, &a.ApplicantEmail, &a.ApplicantPhone,
 		); err != nil {
 			return nil, err
 		}
@@ -153,19 +189,21 @@ func (r *ApplicationRepo) ListByProgramView(ctx context.Context, programID uuid.
 	return res, rows.Err()
 }
 
-// NEW signature: + year
-func (r *ApplicationRepo) ListForTeacherByProgramView(ctx context.Context, teacherID, programID uuid.UUID, status *string, year *int) ([]ApplicationView, error) {
+// NEW signature: + year, + answers (jsonb @>)
+func (r *ApplicationRepo) ListForTeacherByProgramView(ctx context.Context, teacherID, programID uuid.UUID, status *string, year *int, answers []byte) ([]ApplicationView, error) {
 	q := `
-		select a.id, a.user_id, a.group_id, a.status, a.comment, a.created_at, a.updated_at,
+		select a.id, a.user_id, a.group_id, a.status, a.comment, a.answers, a.created_at, a.updated_at,
 		       p.id as program_id, p.title as program_title, g.title as group_title,
 			   c.year as cohort_year,
-		       i.result, i.comment, i.interviewer_role, i.updated_at
+		       i.result, i.comment, i.interviewer_role, i.updated_at,
+		       pr.full_name, pr.school, pr.grade, pr.contact_email, pr.contact_phone
 		from enrollment_applications a
 		join groups g on g.id = a.group_id
   		join programs p on p.id = g.program_id
 		join group_teachers gt on gt.group_id = g.id
 		left join cohorts c on c.id = g.cohort_id
 		left join interviews i on i.application_id = a.id
+		left join profiles pr on pr.user_id = a.user_id
 		where gt.teacher_user_id = $1 and g.program_id = $2
 	`
 	args := []any{teacherID, programID}
@@ -181,9 +219,14 @@ func (r *ApplicationRepo) ListForTeacherByProgramView(ctx context.Context, teach
 		args = append(args, *year)
 		i++
 	}
+	if answers != nil {
+		q += " and a.answers @> $" + strconv.Itoa(i) + "::jsonb"
+		args = append(args, answers)
+		i++
+	}
 	q += " order by a.created_at desc"
 
-	rows, err := r.db.Query(ctx, q, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -193,10 +236,11 @@ func (r *ApplicationRepo) ListForTeacherByProgramView(ctx context.Context, teach
 	for rows.Next() {
 		var a ApplicationView
 		if err := rows.Scan(
-			&a.ID, &a.UserID, &a.GroupID, &a.Status, &a.Comment, &a.CreatedAt, &a.UpdatedAt,
+			&a.ID, &a.UserID, &a.GroupID, &a.Status, &a.Comment, &a.Answers, &a.CreatedAt, &a.UpdatedAt,
 			&a.ProgramID, &a.ProgramTitle, &a.GroupTitle,
 			&a.CohortYear,
 			&a.InterviewResult, &a.InterviewComment, &a.InterviewByRole, &a.InterviewAt,
+			&a.ApplicantName, &a.ApplicantSchool, &a.ApplicantGrade, &a.ApplicantEmail, &a.ApplicantPhone,
 		); err != nil {
 			return nil, err
 		}
@@ -205,18 +249,20 @@ func (r *ApplicationRepo) ListForTeacherByProgramView(ctx context.Context, teach
 	return res, rows.Err()
 }
 
-// NEW signature: + year
-func (r *ApplicationRepo) ListAllView(ctx context.Context, status *string, year *int) ([]ApplicationView, error) {
+// NEW signature: + year, + answers (jsonb @>)
+func (r *ApplicationRepo) ListAllView(ctx context.Context, status *string, year *int, answers []byte) ([]ApplicationView, error) {
 	q := `
-		select a.id, a.user_id, a.group_id, a.status, a.comment, a.created_at, a.updated_at,
+		select a.id, a.user_id, a.group_id, a.status, a.comment, a.answers, a.created_at, a.updated_at,
 		       p.id as program_id, p.title as program_title, g.title as group_title,
 			   c.year as cohort_year,
-		       i.result, i.comment, i.interviewer_role, i.updated_at
+		       i.result, i.comment, i.interviewer_role, i.updated_at,
+		       pr.full_name, pr.school, pr.grade, pr.contact_email, pr.contact_phone
 		from enrollment_applications a
 	  	join groups g on g.id = a.group_id
   		join programs p on p.id = g.program_id
 		left join cohorts c on c.id = g.cohort_id
 		left join interviews i on i.application_id = a.id
+		left join profiles pr on pr.user_id = a.user_id
 		where 1=1
 	`
 	args := []any{}
@@ -232,10 +278,15 @@ func (r *ApplicationRepo) ListAllView(ctx context.Context, status *string, year
 		args = append(args, *year)
 		i++
 	}
+	if answers != nil {
+		q += " and a.answers @> $" + strconv.Itoa(i) + "::jsonb"
+		args = append(args, answers)
+		i++
+	}
 
 	q += " order by a.created_at desc"
 
-	rows, err := r.db.Query(ctx, q, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -245,10 +296,11 @@ func (r *ApplicationRepo) ListAllView(ctx context.Context, status *string, year
 	for rows.Next() {
 		var a ApplicationView
 		if err := rows.Scan(
-			&a.ID, &a.UserID, &a.GroupID, &a.Status, &a.Comment, &a.CreatedAt, &a.UpdatedAt,
+			&a.ID, &a.UserID, &a.GroupID, &a.Status, &a.Comment, &a.Answers, &a.CreatedAt, &a.UpdatedAt,
 			&a.ProgramID, &a.ProgramTitle, &a.GroupTitle,
 			&a.CohortYear,
 			&a.InterviewResult, &a.InterviewComment, &a.InterviewByRole, &a.InterviewAt,
+			&a.ApplicantName, &a.ApplicantSchool, &a.ApplicantGrade, &a.ApplicantEmail, &a.ApplicantPhone,
 		); err != nil {
 			return nil, err
 		}
@@ -258,23 +310,28 @@ func (r *ApplicationRepo) ListAllView(ctx context.Context, status *string, year
 }
 
 func (r *ApplicationRepo) Create(ctx context.Context, a domain.EnrollmentApplication) error {
-	_, err := r.db.Exec(ctx, `
-		insert into enrollment_applications(id, user_id, group_id, status, comment, created_at, updated_at)
-		values ($1,$2,$3,$4,$5, now(), now())
-	`, a.ID, a.UserID, a.GroupID, a.Status, a.Comment)
+	// submitted_by_user_id: null = сам заявитель, иначе guardian (чтение — через coalesce)
+	var submittedBy *uuid.UUID
+	if a.SubmittedBy != uuid.Nil && a.SubmittedBy != a.UserID {
+		submittedBy = &a.SubmittedBy
+	}
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into enrollment_applications(id, user_id, group_id, status, comment, submitted_by_user_id, answers, created_at, updated_at)
+		values ($1,$2,$3,$4,$5,$6,$7, now(), now())
+	`, a.ID, a.UserID, a.GroupID, a.Status, a.Comment, submittedBy, answersJSON(a.Answers))
 	return err
 }
 
 func (r *ApplicationRepo) Get(ctx context.Context, id uuid.UUID) (domain.EnrollmentApplication, error) {
-	row := r.db.QueryRow(ctx, `
-		select id, user_id, group_id, status, comment, created_at, updated_at
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select id, user_id, group_id, status, comment, created_at, updated_at, coalesce(submitted_by_user_id, user_id), answers
 		from enrollment_applications
 		where id=$1
 	`, id)
 
 	var a domain.EnrollmentApplication
 	var status string
-	err := row.Scan(&a.ID, &a.UserID, &a.GroupID, &status, &a.Comment, &a.CreatedAt, &a.UpdatedAt)
+	err := row.Scan(&a.ID, &a.UserID, &a.GroupID, &status, &a.Comment, &a.CreatedAt, &a.UpdatedAt, &a.SubmittedBy, &a.Answers)
 	if err != nil {
 		return domain.EnrollmentApplication{}, err
 	}
@@ -283,11 +340,19 @@ func (r *ApplicationRepo) Get(ctx context.Context, id uuid.UUID) (domain.Enrollm
 }
 
 func (r *ApplicationRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.EnrollmentApplication, error) {
-	rows, err := r.db.Query(ctx, `
-		select id, user_id, group_id, status, comment, created_at, updated_at
-		from enrollment_applications
-		where user_id=$1
-		order by created_at desc
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select a.id, a.user_id, a.group_id, a.status, a.comment, a.created_at, a.updated_at,
+			coalesce(a.submitted_by_user_id, a.user_id), a.answers, coalesce(last.reason, '')
+		from enrollment_applications a
+		left join lateral (
+			select reason
+			from application_status_audit
+			where application_id = a.id
+			order by created_at desc
+			limit 1
+		) last on true
+		where a.user_id=$1
+		order by a.created_at desc
 	`, userID)
 	if err != nil {
 		return nil, err
@@ -298,7 +363,7 @@ func (r *ApplicationRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]d
 	for rows.Next() {
 		var a domain.EnrollmentApplication
 		var status string
-		if err := rows.Scan(&a.ID, &a.UserID, &a.GroupID, &status, &a.Comment, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.UserID, &a.GroupID, &status, &a.Comment, &a.CreatedAt, &a.UpdatedAt, &a.SubmittedBy, &a.Answers, &a.LastReason); err != nil {
 			return nil, err
 		}
 		a.Status = domain.ApplicationStatus(status)
@@ -308,56 +373,199 @@ func (r *ApplicationRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]d
 }
 
 func (r *ApplicationRepo) UpdateStatus(ctx context.Context, id uuid.UUID, to domain.ApplicationStatus) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		update enrollment_applications
-		set status=$2, updated_at=now()
+		set status=$2,
+			updated_at=now(),
+			waitlisted_at = case when $2='waitlisted' then now() else waitlisted_at end
 		where id=$1
 	`, id, string(to))
 	return err
 }
 
 func (r *ApplicationRepo) InsertAudit(ctx context.Context, appID uuid.UUID, actorID uuid.UUID, actorRole string, from, to domain.ApplicationStatus, reason string) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into application_status_audit(id, application_id, actor_user_id, actor_role, from_status, to_status, reason, created_at)
 		values ($1,$2,$3,$4,$5,$6,$7, now())
 	`, uuid.New(), appID, actorID, actorRole, string(from), string(to), reason)
 	return err
 }
 
+// ListAudit: переходы статусов заявки по времени
+func (r *ApplicationRepo) ListAudit(ctx context.Context, appID uuid.UUID) ([]domain.ApplicationAudit, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select id, actor_user_id, actor_role, from_status, to_status, reason, created_at
+		from application_status_audit
+		where application_id=$1
+		order by created_at asc, id asc
+	`, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.ApplicationAudit, 0)
+	for rows.Next() {
+		var a domain.ApplicationAudit
+		var from, to string
+		if err := rows.Scan(&a.ID, &a.ActorUserID, &a.ActorRole, &from, &to, &a.Reason, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.From, a.To = domain.ApplicationStatus(from), domain.ApplicationStatus(to)
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
+
 func (r *ApplicationRepo) CountEnrollmentsByGroup(ctx context.Context, groupID uuid.UUID) (int, error) {
-	row := r.db.QueryRow(ctx, `select count(*) from enrollments where group_id=$1`, groupID)
+	// места занимают только активные зачисления
+	row := conn(ctx, r.db).QueryRow(ctx, `select count(*) from enrollments where group_id=$1 and status='active'`, groupID)
 	var n int
 	return n, row.Scan(&n)
 }
 
 func (r *ApplicationRepo) GroupCapacity(ctx context.Context, groupID uuid.UUID) (int, error) {
-	row := r.db.QueryRow(ctx, `select capacity from groups where id=$1`, groupID)
+	row := conn(ctx, r.db).QueryRow(ctx, `select capacity from groups where id=$1`, groupID)
 	var cap int
 	return cap, row.Scan(&cap)
 }
 
-func (r *ApplicationRepo) CreateEnrollment(ctx context.Context, userID, groupID uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `
-		insert into enrollments(id, user_id, group_id, created_at)
-		values ($1,$2,$3, now())
-		on conflict (user_id, group_id) do nothing
-	`, uuid.New(), userID, groupID)
+// LockGroupCapacity: блокирует строку группы до конца транзакции (одобрения в группу идут по очереди)
+func (r *ApplicationRepo) LockGroupCapacity(ctx context.Context, groupID uuid.UUID) (int, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `select capacity from groups where id=$1 for update`, groupID)
+	var cap int
+	return cap, row.Scan(&cap)
+}
+
+// GetForUpdate: как Get, но с блокировкой строки заявки (только внутри транзакции)
+func (r *ApplicationRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (domain.EnrollmentApplication, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select id, user_id, group_id, status, comment, created_at, updated_at, coalesce(submitted_by_user_id, user_id)
+		from enrollment_applications
+		where id=$1
+		for update
+	`, id)
+
+	var a domain.EnrollmentApplication
+	var status string
+	err := row.Scan(&a.ID, &a.UserID, &a.GroupID, &status, &a.Comment, &a.CreatedAt, &a.UpdatedAt, &a.SubmittedBy)
+	if err != nil {
+		return domain.EnrollmentApplication{}, err
+	}
+	a.Status = domain.ApplicationStatus(status)
+	return a, nil
+}
+
+// NextWaitlisted: первая в очереди заявка группы (с блокировкой строки).
+// После подведения конкурса очередь идёт по месту в рейтинге (admission_rank).
+func (r *ApplicationRepo) NextWaitlisted(ctx context.Context, groupID uuid.UUID) (domain.EnrollmentApplication, bool, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select id, user_id, group_id, status, comment, created_at, updated_at, coalesce(submitted_by_user_id, user_id)
+		from enrollment_applications
+		where group_id=$1 and status='waitlisted'
+		order by waitlisted_at asc, admission_rank asc nulls last, created_at asc
+		limit 1
+		for update
+	`, groupID)
+
+	var a domain.EnrollmentApplication
+	var st string
+	err := row.Scan(&a.ID, &a.UserID, &a.GroupID, &st, &a.Comment, &a.CreatedAt, &a.UpdatedAt, &a.SubmittedBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.EnrollmentApplication{}, false, nil
+		}
+		return domain.EnrollmentApplication{}, false, err
+	}
+	a.Status = domain.ApplicationStatus(st)
+	return a, true, nil
+}
+
+// WaitlistPosition: место заявки в очереди группы (с 1) и длина очереди; false — заявка не в листе ожидания
+func (r *ApplicationRepo) WaitlistPosition(ctx context.Context, appID uuid.UUID) (int, int, bool, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select q.pos, q.total
+		from (
+			select id,
+				row_number() over (partition by group_id order by waitlisted_at asc, admission_rank asc nulls last, created_at asc) as pos,
+				count(*) over (partition by group_id) as total
+			from enrollment_applications
+			where status='waitlisted'
+			  and group_id = (select group_id from enrollment_applications where id=$1)
+		) q
+		where q.id=$1
+	`, appID)
+
+	var pos, total int
+	if err := row.Scan(&pos, &total); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, 0, false, nil
+		}
+		return 0, 0, false, err
+	}
+	return pos, total, true, nil
+}
+
+// CreateEnrollment: зачисление по одобренной заявке. Прежнее зачисление в ту же группу
+// (ушёл, отчислен, переведён) снова становится active, переход пишется в аудит зачисления
+// от имени того, кто одобрил; уже active — без изменений.
+func (r *ApplicationRepo) CreateEnrollment(ctx context.Context, userID, groupID, actorID uuid.UUID, actorRole, reason string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		with prev as (
+			select id, status from enrollments
+			where user_id=$2 and group_id=$3
+			for update
+		), up as (
+			insert into enrollments(id, user_id, group_id, created_at)
+			values ($1,$2,$3, now())
+			on conflict (user_id, group_id) do update
+				set status='active', status_reason=$6, ended_at=null, updated_at=now()
+				where enrollments.status <> 'active'
+			returning id
+		)
+		insert into enrollment_status_audit(id, enrollment_id, actor_user_id, actor_role, from_status, to_status, reason)
+		select $7, prev.id, $4, $5, prev.status, 'active', $6
+		from prev join up on up.id = prev.id
+	`, uuid.New(), userID, groupID, actorID, actorRole, reason, uuid.New())
 	return err
 }
 
 func (r *ApplicationRepo) HasEnrollment(ctx context.Context, userID, groupID uuid.UUID) (bool, error) {
-	row := r.db.QueryRow(ctx, `
-		select exists(select 1 from enrollments where user_id=$1 and group_id=$2)
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select exists(select 1 from enrollments where user_id=$1 and group_id=$2 and status='active')
 	`, userID, groupID)
 	var ok bool
 	return ok, row.Scan(&ok)
 }
 
+// ListEnrolledGroupIDs: группы, где пользователь учится
+func (r *ApplicationRepo) ListEnrolledGroupIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select group_id
+		from enrollments
+		where user_id=$1 and status='active'
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []uuid.UUID
+	for rows.Next() {
+		var gid uuid.UUID
+		if err := rows.Scan(&gid); err != nil {
+			return nil, err
+		}
+		res = append(res, gid)
+	}
+	return res, rows.Err()
+}
+
 func (r *ApplicationRepo) ListEnrolledUsersByGroup(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := conn(ctx, r.db).Query(ctx, `
 		select user_id
 		from enrollments
-		where group_id=$1
+		where group_id=$1 and status='active'
 		order by created_at asc
 	`, groupID)
 	if err != nil {
@@ -376,9 +584,33 @@ func (r *ApplicationRepo) ListEnrolledUsersByGroup(ctx context.Context, groupID
 	return res, rows.Err()
 }
 
+func (r *ApplicationRepo) ListEnrolledStudentsByGroup(ctx context.Context, groupID uuid.UUID) ([]EnrolledStudentView, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select e.user_id, pr.full_name, pr.school, pr.grade, pr.contact_email, pr.contact_phone, e.created_at
+		from enrollments e
+		left join profiles pr on pr.user_id = e.user_id
+		where e.group_id=$1 and e.status='active'
+		order by e.created_at asc
+	`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]EnrolledStudentView, 0)
+	for rows.Next() {
+		var s EnrolledStudentView
+		if err := rows.Scan(&s.UserID, &s.FullName, &s.School, &s.Grade, &s.Email, &s.Phone, &s.EnrolledAt); err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
 func (r *ApplicationRepo) ListByProgram(ctx context.Context, programID uuid.UUID, status *string) ([]domain.EnrollmentApplication, error) {
 	q := `
-		select a.id, a.user_id, a.group_id, a.status, a.comment, a.created_at, a.updated_at
+		select a.id, a.user_id, a.group_id, a.status, a.comment, a.created_at, a.updated_at, coalesce(a.submitted_by_user_id, a.user_id)
 		from enrollment_applications a
 		join groups g on g.id = a.group_id
 		where g.program_id = $1
@@ -393,7 +625,7 @@ func (r *ApplicationRepo) ListByProgram(ctx context.Context, programID uuid.UUID
 	}
 	q += " order by a.created_at desc"
 
-	rows, err := r.db.Query(ctx, q, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -403,7 +635,7 @@ func (r *ApplicationRepo) ListByProgram(ctx context.Context, programID uuid.UUID
 	for rows.Next() {
 		var a domain.EnrollmentApplication
 		var st string
-		if err := rows.Scan(&a.ID, &a.UserID, &a.GroupID, &st, &a.Comment, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.UserID, &a.GroupID, &st, &a.Comment, &a.CreatedAt, &a.UpdatedAt, &a.SubmittedBy); err != nil {
 			return nil, err
 		}
 		a.Status = domain.ApplicationStatus(st)
@@ -414,7 +646,7 @@ func (r *ApplicationRepo) ListByProgram(ctx context.Context, programID uuid.UUID
 
 func (r *ApplicationRepo) ListForTeacherByProgram(ctx context.Context, teacherID, programID uuid.UUID, status *string) ([]domain.EnrollmentApplication, error) {
 	q := `
-		select a.id, a.user_id, a.group_id, a.status, a.comment, a.created_at, a.updated_at
+		select a.id, a.user_id, a.group_id, a.status, a.comment, a.created_at, a.updated_at, coalesce(a.submitted_by_user_id, a.user_id)
 		from enrollment_applications a
 		join groups g on g.id = a.group_id
 		join group_teachers gt on gt.group_id = g.id
@@ -430,7 +662,7 @@ func (r *ApplicationRepo) ListForTeacherByProgram(ctx context.Context, teacherID
 	}
 	q += " order by a.created_at desc"
 
-	rows, err := r.db.Query(ctx, q, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -440,7 +672,7 @@ func (r *ApplicationRepo) ListForTeacherByProgram(ctx context.Context, teacherID
 	for rows.Next() {
 		var a domain.EnrollmentApplication
 		var st string
-		if err := rows.Scan(&a.ID, &a.UserID, &a.GroupID, &st, &a.Comment, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.UserID, &a.GroupID, &st, &a.Comment, &a.CreatedAt, &a.UpdatedAt, &a.SubmittedBy); err != nil {
 			return nil, err
 		}
 		a.Status = domain.ApplicationStatus(st)
@@ -451,7 +683,7 @@ func (r *ApplicationRepo) ListForTeacherByProgram(ctx context.Context, teacherID
 
 func (r *ApplicationRepo) ListAll(ctx context.Context, status *string) ([]domain.EnrollmentApplication, error) {
 	q := `
-		select id, user_id, group_id, status, comment, created_at, updated_at
+		select id, user_id, group_id, status, comment, created_at, updated_at, coalesce(submitted_by_user_id, user_id)
 		from enrollment_applications
 		where 1=1
 	`
@@ -466,7 +698,7 @@ func (r *ApplicationRepo) ListAll(ctx context.Context, status *string) ([]domain
 
 	q += " order by created_at desc"
 
-	rows, err := r.db.Query(ctx, q, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -476,7 +708,7 @@ func (r *ApplicationRepo) ListAll(ctx context.Context, status *string) ([]domain
 	for rows.Next() {
 		var a domain.EnrollmentApplication
 		var st string
-		if err := rows.Scan(&a.ID, &a.UserID, &a.GroupID, &st, &a.Comment, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.UserID, &a.GroupID, &st, &a.Comment, &a.CreatedAt, &a.UpdatedAt, &a.SubmittedBy); err != nil {
 			return nil, err
 		}
 		a.Status = domain.ApplicationStatus(st)
@@ -485,11 +717,60 @@ func (r *ApplicationRepo) ListAll(ctx context.Context, status *string) ([]domain
 	return res, rows.Err()
 }
 
+// ListIDsByFilter: id заявок по фильтрам списка (status/year/program) для массовых операций,
+// старые первыми — при одобрении места достаются по времени подачи
+func (r *ApplicationRepo) ListIDsByFilter(ctx context.Context, programID *uuid.UUID, status *string, year *int, limit int) ([]uuid.UUID, error) {
+	q := `
+		select a.id
+		from enrollment_applications a
+		join groups g on g.id = a.group_id
+		left join cohorts c on c.id = g.cohort_id
+		where 1=1
+	`
+	args := []any{}
+	i := 1
+
+	if programID != nil {
+		q += " and g.program_id=$" + strconv.Itoa(i)
+		args = append(args, *programID)
+		i++
+	}
+	if status != nil {
+		q += " and a.status=$" + strconv.Itoa(i)
+		args = append(args, *status)
+		i++
+	}
+	if year != nil {
+		q += " and c.year=$" + strconv.Itoa(i)
+		args = append(args, *year)
+		i++
+	}
+
+	q += " order by a.created_at asc, a.id asc limit $" + strconv.Itoa(i)
+	args = append(args, limit)
+
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		res = append(res, id)
+	}
+	return res, rows.Err()
+}
+
 func (r *ApplicationRepo) CancelByUser(ctx context.Context, appID, userID uuid.UUID) (bool, error) {
-	ct, err := r.db.Exec(ctx, `
+	ct, err := conn(ctx, r.db).Exec(ctx, `
 		update enrollment_applications
 		set status = 'cancelled', updated_at = now()
-		where id=$1 and user_id=$2 and status in ('submitted','in_review')
+		where id=$1 and user_id=$2 and status in ('submitted','in_review','waitlisted')
 	`, appID, userID)
 	if err != nil {
 		return false, err
@@ -498,8 +779,8 @@ func (r *ApplicationRepo) CancelByUser(ctx context.Context, appID, userID uuid.U
 }
 
 func (r *ApplicationRepo) GetLatestByUserGroup(ctx context.Context, userID, groupID uuid.UUID) (domain.EnrollmentApplication, bool, error) {
-	row := r.db.QueryRow(ctx, `
-		select id, user_id, group_id, status, comment, created_at, updated_at
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select id, user_id, group_id, status, comment, created_at, updated_at, coalesce(submitted_by_user_id, user_id)
 		from enrollment_applications
 		where user_id=$1 and group_id=$2
 		order by created_at desc
@@ -508,7 +789,7 @@ func (r *ApplicationRepo) GetLatestByUserGroup(ctx context.Context, userID, grou
 
 	var a domain.EnrollmentApplication
 	var st string
-	err := row.Scan(&a.ID, &a.UserID, &a.GroupID, &st, &a.Comment, &a.CreatedAt, &a.UpdatedAt)
+	err := row.Scan(&a.ID, &a.UserID, &a.GroupID, &st, &a.Comment, &a.CreatedAt, &a.UpdatedAt, &a.SubmittedBy)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.EnrollmentApplication{}, false, nil
@@ -520,7 +801,7 @@ func (r *ApplicationRepo) GetLatestByUserGroup(ctx context.Context, userID, grou
 }
 
 func (r *ApplicationRepo) HasRejected(ctx context.Context, userID, groupID uuid.UUID) (bool, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select exists(
 			select 1
 			from enrollment_applications
@@ -531,6 +812,14 @@ func (r *ApplicationRepo) HasRejected(ctx context.Context, userID, groupID uuid.
 	return ok, row.Scan(&ok)
 }
 
+// answersJSON: заявка без анкеты хранит {}
+func answersJSON(b json.RawMessage) []byte {
+	if len(b) == 0 {
+		return []byte("{}")
+	}
+	return b
+}
+
 func itoa(i int) string { // чтобы не тащить strconv в каждый файл
 	return string(rune('0' + i))
 }
@@ -538,3 +827,26 @@ func itoa(i int) string { // чтобы не тащить strconv в кажды
 // NOTE: itoa выше годится только до 9 параметров; для MVP ок.
 // Потом заменишь на strconv.Itoa(i).
 var _ = time.Now
+
+// GroupIDsOf: группы заявок без повторов (порядок блокировок выбирает вызывающий)
+func (r *ApplicationRepo) GroupIDsOf(ctx context.Context, appIDs []uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select distinct group_id
+		from enrollment_applications
+		where id = any($1)
+	`, appIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		res = append(res, id)
+	}
+	return res, rows.Err()
+}
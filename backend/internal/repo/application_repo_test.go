@@ -0,0 +1,67 @@
+// internal/repo/application_repo_test.go
+
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+func TestCreateEnrollmentReactivatesPrevious(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	apps, enrollments := NewApplicationRepo(pool), NewEnrollmentRepo(pool)
+	gid := testGroup(t, pool, 10)
+	userID, moderatorID := uuid.New(), uuid.New()
+
+	if err := apps.CreateEnrollment(ctx, userID, gid, moderatorID, "moderator", ""); err != nil {
+		t.Fatal(err)
+	}
+	list, err := enrollments.ListByUser(ctx, userID)
+	if err != nil || len(list) != 1 || list[0].Status != domain.EnrollmentActive {
+		t.Fatalf("first enrollment = %+v, %v", list, err)
+	}
+	e := list[0]
+
+	// ушёл, потом снова подал заявку и её одобрили — то же зачисление снова active
+	if err := enrollments.UpdateStatus(ctx, e.ID, domain.EnrollmentWithdrawn, "переезд"); err != nil {
+		t.Fatal(err)
+	}
+	if err := apps.CreateEnrollment(ctx, userID, gid, moderatorID, "moderator", "вернулся"); err != nil {
+		t.Fatal(err)
+	}
+	again, _, err := enrollments.Get(ctx, e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Status != domain.EnrollmentActive || again.EndedAt != nil || again.Reason != "вернулся" {
+		t.Errorf("after approval: %+v, want the same enrollment active again", again)
+	}
+	if n, err := apps.CountEnrollmentsByGroup(ctx, gid); err != nil || n != 1 {
+		t.Errorf("CountEnrollmentsByGroup = %d, %v; want 1", n, err)
+	}
+
+	audit, err := enrollments.ListAudit(ctx, e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(audit) != 1 {
+		t.Fatalf("audit = %+v, want one withdrawn -> active row", audit)
+	}
+	if a := audit[0]; a.From != domain.EnrollmentWithdrawn || a.To != domain.EnrollmentActive ||
+		a.ActorUserID != moderatorID || a.ActorRole != "moderator" {
+		t.Errorf("audit row = %+v", a)
+	}
+
+	// уже active — ни изменений, ни аудита
+	if err := apps.CreateEnrollment(ctx, userID, gid, uuid.Nil, "system", "promoted from waitlist"); err != nil {
+		t.Fatal(err)
+	}
+	if audit, _ := enrollments.ListAudit(ctx, e.ID); len(audit) != 1 {
+		t.Errorf("audit has %d rows after approving an active enrollment, want 1", len(audit))
+	}
+}
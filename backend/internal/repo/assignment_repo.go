@@ -17,7 +17,7 @@ type AssignmentRepo struct{ db *pgxpool.Pool }
 func NewAssignmentRepo(db *pgxpool.Pool) *AssignmentRepo { return &AssignmentRepo{db: db} }
 
 func (r *AssignmentRepo) Create(ctx context.Context, a domain.Assignment) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into assignments(id, group_id, title, description, due_at, created_by_user_id)
 		values ($1,$2,$3,$4,$5,$6)
 	`, a.ID, a.GroupID, a.Title, a.Description, a.DueAt, a.CreatedBy)
@@ -25,7 +25,7 @@ func (r *AssignmentRepo) Create(ctx context.Context, a domain.Assignment) error
 }
 
 func (r *AssignmentRepo) ListByGroup(ctx context.Context, groupID uuid.UUID) ([]domain.Assignment, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := conn(ctx, r.db).Query(ctx, `
 		select id, group_id, title, description, due_at, created_by_user_id, created_at, updated_at
 		from assignments
 		where group_id=$1
@@ -50,7 +50,7 @@ func (r *AssignmentRepo) ListByGroup(ctx context.Context, groupID uuid.UUID) ([]
 }
 
 func (r *AssignmentRepo) Get(ctx context.Context, id uuid.UUID) (domain.Assignment, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select id, group_id, title, description, due_at, created_by_user_id, created_at, updated_at
 		from assignments where id=$1
 	`, id)
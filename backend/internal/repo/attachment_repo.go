@@ -0,0 +1,81 @@
+// internal/repo/attachment_repo.go
+
+package repo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type AttachmentRepo struct{ db *pgxpool.Pool }
+
+func NewAttachmentRepo(db *pgxpool.Pool) *AttachmentRepo { return &AttachmentRepo{db: db} }
+
+const attachmentColumns = `id, application_id, uploaded_by, file_name, content_type, size_bytes, storage_key, sha256, scan_status, created_at`
+
+func scanAttachment(row pgx.Row) (domain.ApplicationAttachment, error) {
+	var a domain.ApplicationAttachment
+	err := row.Scan(&a.ID, &a.ApplicationID, &a.UploadedBy, &a.FileName, &a.ContentType, &a.SizeBytes,
+		&a.StorageKey, &a.SHA256, &a.ScanStatus, &a.CreatedAt)
+	return a, err
+}
+
+func (r *AttachmentRepo) Create(ctx context.Context, a domain.ApplicationAttachment) (domain.ApplicationAttachment, error) {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		insert into application_attachments(id, application_id, uploaded_by, file_name, content_type, size_bytes, storage_key, sha256, scan_status)
+		values ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+		returning created_at
+	`, a.ID, a.ApplicationID, a.UploadedBy, a.FileName, a.ContentType, a.SizeBytes, a.StorageKey, a.SHA256, a.ScanStatus).Scan(&a.CreatedAt)
+	return a, err
+}
+
+// Get: вложение конкретной заявки (чужой appID — pgx.ErrNoRows)
+func (r *AttachmentRepo) Get(ctx context.Context, appID, id uuid.UUID) (domain.ApplicationAttachment, error) {
+	return scanAttachment(conn(ctx, r.db).QueryRow(ctx, `
+		select `+attachmentColumns+`
+		from application_attachments
+		where id=$1 and application_id=$2
+	`, id, appID))
+}
+
+func (r *AttachmentRepo) ListByApplication(ctx context.Context, appID uuid.UUID) ([]domain.ApplicationAttachment, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select `+attachmentColumns+`
+		from application_attachments
+		where application_id=$1
+		order by created_at asc, id asc
+	`, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.ApplicationAttachment, 0)
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
+
+func (r *AttachmentRepo) CountByApplication(ctx context.Context, appID uuid.UUID) (int, error) {
+	var n int
+	err := conn(ctx, r.db).QueryRow(ctx, `select count(*) from application_attachments where application_id=$1`, appID).Scan(&n)
+	return n, err
+}
+
+func (r *AttachmentRepo) Delete(ctx context.Context, appID, id uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `delete from application_attachments where id=$1 and application_id=$2`, id, appID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
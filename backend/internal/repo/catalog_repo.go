@@ -4,6 +4,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -16,10 +17,28 @@ type CatalogRepo struct{ db *pgxpool.Pool }
 
 func NewCatalogRepo(db *pgxpool.Pool) *CatalogRepo { return &CatalogRepo{db: db} }
 
+// окно приёма группы: своё, иначе окно потока (cohort); запросы делают join cohorts c
+const groupColumns = `g.id, g.program_id, g.cohort_id, g.title, g.capacity, g.is_open, g.requires_interview, g.created_at,
+	g.opens_at, g.closes_at, coalesce(g.opens_at, c.opens_at), coalesce(g.closes_at, c.closes_at),
+	g.admission_mode, g.admission_finalized_at`
+
+func scanGroup(row pgx.Row) (domain.Group, error) {
+	var g domain.Group
+	var mode string
+	if err := row.Scan(&g.ID, &g.ProgramID, &g.CohortID, &g.Title, &g.Capacity, &g.IsOpen, &g.RequiresInterview, &g.CreatedAt,
+		&g.OwnOpensAt, &g.OwnClosesAt, &g.OpensAt, &g.ClosesAt,
+		&mode, &g.AdmissionFinalizedAt); err != nil {
+		return domain.Group{}, err
+	}
+	g.AdmissionMode = domain.AdmissionMode(mode)
+	g.SetApplyStatus(time.Now())
+	return g, nil
+}
+
 // -------- Public catalog --------
 
 func (r *CatalogRepo) ListPublishedPrograms(ctx context.Context) ([]domain.Program, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := conn(ctx, r.db).Query(ctx, `
     select id, title, description, status, created_at
     from programs
     where status='published'
@@ -43,14 +62,14 @@ func (r *CatalogRepo) ListPublishedPrograms(ctx context.Context) ([]domain.Progr
 	return res, rows.Err()
 }
 
-// Program page: program + groups (only open groups) for published program
+// Program page: program + groups (open or with an upcoming window) for published program
 type ProgramWithGroups struct {
 	Program domain.Program
 	Groups  []domain.Group
 }
 
 func (r *CatalogRepo) GetPublishedProgramWithGroups(ctx context.Context, programID uuid.UUID) (ProgramWithGroups, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select id, title, description, status, created_at
 		from programs
 		where id=$1 and status='published'
@@ -63,11 +82,13 @@ func (r *CatalogRepo) GetPublishedProgramWithGroups(ctx context.Context, program
 	}
 	p.Status = domain.ProgramStatus(st)
 
-	rows, err := r.db.Query(ctx, `
-		select id, program_id, cohort_id, title, capacity, is_open, requires_interview, created_at
-		from groups
-		where program_id=$1 and is_open=true
-		order by created_at desc
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select `+groupColumns+`
+		from groups g
+		join cohorts c on c.id = g.cohort_id
+		where g.program_id=$1
+		  and (g.is_open=true or coalesce(g.opens_at, c.opens_at) > now())
+		order by g.created_at desc
 	`, programID)
 	if err != nil {
 		return ProgramWithGroups{}, err
@@ -76,8 +97,8 @@ func (r *CatalogRepo) GetPublishedProgramWithGroups(ctx context.Context, program
 
 	gs := make([]domain.Group, 0)
 	for rows.Next() {
-		var g domain.Group
-		if err := rows.Scan(&g.ID, &g.ProgramID, &g.CohortID, &g.Title, &g.Capacity, &g.IsOpen, &g.RequiresInterview, &g.CreatedAt); err != nil {
+		g, err := scanGroup(rows)
+		if err != nil {
 			return ProgramWithGroups{}, err
 		}
 		gs = append(gs, g)
@@ -88,11 +109,16 @@ func (r *CatalogRepo) GetPublishedProgramWithGroups(ctx context.Context, program
 
 // Used by ApplicationService.Create: ensure group open + program published
 func (r *CatalogRepo) IsGroupAvailableForApply(ctx context.Context, groupID uuid.UUID) (bool, bool, error) {
-	// returns: (programPublished, groupOpen)
-	row := r.db.QueryRow(ctx, `
-		select p.status, g.is_open
+	// returns: (programPublished, groupOpen); groupOpen учитывает окно приёма (opens_at/closes_at),
+	// даже если фоновая задача ещё не переключила is_open
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select p.status,
+			g.is_open
+			and coalesce(coalesce(g.opens_at, c.opens_at) <= now(), true)
+			and coalesce(coalesce(g.closes_at, c.closes_at) > now(), true)
 		from groups g
 		join programs p on p.id=g.program_id
+		join cohorts c on c.id=g.cohort_id
 		where g.id=$1
 	`, groupID)
 	var pStatus string
@@ -103,14 +129,34 @@ func (r *CatalogRepo) IsGroupAvailableForApply(ctx context.Context, groupID uuid
 	return pStatus == string(domain.ProgramPublished), open, nil
 }
 
+// IsGroupOpenForTransfer: перевод (EnrollmentService.Transfer) смотрит только на is_open, окно приёма ему не мешает.
+// После закрытия окна фоновая задача сбрасывает is_open (window_state='closed') — такая группа для перевода открыта.
+func (r *CatalogRepo) IsGroupOpenForTransfer(ctx context.Context, groupID uuid.UUID) (bool, error) {
+	var open bool
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select is_open or coalesce(window_state = 'closed', false) from groups where id=$1
+	`, groupID).Scan(&open)
+	return open, err
+}
+
 func (r *CatalogRepo) GroupRequiresInterview(ctx context.Context, groupID uuid.UUID) (bool, error) {
-	row := r.db.QueryRow(ctx, `select requires_interview from groups where id=$1`, groupID)
+	row := conn(ctx, r.db).QueryRow(ctx, `select requires_interview from groups where id=$1`, groupID)
 	var req bool
 	return req, row.Scan(&req)
 }
 
+// IsAdmissionPending: группа с конкурсным приёмом, конкурс ещё не подведён
+func (r *CatalogRepo) IsAdmissionPending(ctx context.Context, groupID uuid.UUID) (bool, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select admission_mode='competitive' and admission_finalized_at is null
+		from groups where id=$1
+	`, groupID)
+	var pending bool
+	return pending, row.Scan(&pending)
+}
+
 func (r *CatalogRepo) IsTeacherInGroup(ctx context.Context, groupID, teacherID uuid.UUID) (bool, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select exists(
 			select 1 from group_teachers where group_id=$1 and teacher_user_id=$2
 		)
@@ -120,10 +166,11 @@ func (r *CatalogRepo) IsTeacherInGroup(ctx context.Context, groupID, teacherID u
 }
 
 func (r *CatalogRepo) ListTeacherGroups(ctx context.Context, teacherID uuid.UUID) ([]domain.Group, error) {
-	rows, err := r.db.Query(ctx, `
-    select g.id, g.program_id, g.cohort_id, g.title, g.capacity, g.is_open, g.requires_interview, g.created_at
+	rows, err := conn(ctx, r.db).Query(ctx, `
+    select `+groupColumns+`
     from group_teachers gt
     join groups g on g.id=gt.group_id
+    join cohorts c on c.id=g.cohort_id
     where gt.teacher_user_id=$1
     order by g.created_at desc
   `, teacherID)
@@ -134,8 +181,8 @@ func (r *CatalogRepo) ListTeacherGroups(ctx context.Context, teacherID uuid.UUID
 
 	res := make([]domain.Group, 0)
 	for rows.Next() {
-		var g domain.Group
-		if err := rows.Scan(&g.ID, &g.ProgramID, &g.CohortID, &g.Title, &g.Capacity, &g.IsOpen, &g.RequiresInterview, &g.CreatedAt); err != nil {
+		g, err := scanGroup(rows)
+		if err != nil {
 			return nil, err
 		}
 		res = append(res, g)
@@ -147,7 +194,7 @@ func (r *CatalogRepo) ListTeacherGroups(ctx context.Context, teacherID uuid.UUID
 
 func (r *CatalogRepo) CreateProgramDraft(ctx context.Context, title, desc string) (uuid.UUID, error) {
 	id := uuid.New()
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into programs(id, title, description, status)
 		values ($1,$2,$3,'draft')
 	`, id, title, desc)
@@ -155,30 +202,30 @@ func (r *CatalogRepo) CreateProgramDraft(ctx context.Context, title, desc string
 }
 
 func (r *CatalogRepo) PublishProgram(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `update programs set status='published' where id=$1`, id)
+	_, err := conn(ctx, r.db).Exec(ctx, `update programs set status='published' where id=$1`, id)
 	return err
 }
 
 func (r *CatalogRepo) CreateCohort(ctx context.Context, programID uuid.UUID, year int) (uuid.UUID, error) {
 	id := uuid.New()
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into cohorts(id, program_id, year)
 		values ($1,$2,$3)
 	`, id, programID, year)
 	return id, err
 }
 
-func (r *CatalogRepo) CreateGroup(ctx context.Context, programID, cohortID uuid.UUID, title string, capacity int, requiresInterview bool, isOpen bool) (uuid.UUID, error) {
+func (r *CatalogRepo) CreateGroup(ctx context.Context, programID, cohortID uuid.UUID, title string, capacity int, requiresInterview bool, isOpen bool, opensAt, closesAt *time.Time) (uuid.UUID, error) {
 	id := uuid.New()
-	_, err := r.db.Exec(ctx, `
-		insert into groups(id, program_id, cohort_id, title, capacity, requires_interview, is_open)
-		values ($1,$2,$3,$4,$5,$6,$7)
-	`, id, programID, cohortID, title, capacity, requiresInterview, isOpen)
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into groups(id, program_id, cohort_id, title, capacity, requires_interview, is_open, opens_at, closes_at)
+		values ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	`, id, programID, cohortID, title, capacity, requiresInterview, isOpen, opensAt, closesAt)
 	return id, err
 }
 
 func (r *CatalogRepo) AssignTeacherToGroup(ctx context.Context, groupID, teacherUserID uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into group_teachers(group_id, teacher_user_id)
 		values ($1,$2)
 		on conflict do nothing
@@ -187,7 +234,7 @@ func (r *CatalogRepo) AssignTeacherToGroup(ctx context.Context, groupID, teacher
 }
 
 func (r *CatalogRepo) SetGroupOpen(ctx context.Context, groupID uuid.UUID, open bool) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		update groups
 		set is_open=$2
 		where id=$1
@@ -196,7 +243,7 @@ func (r *CatalogRepo) SetGroupOpen(ctx context.Context, groupID uuid.UUID, open
 }
 
 func (r *CatalogRepo) ListAllPrograms(ctx context.Context) ([]domain.Program, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := conn(ctx, r.db).Query(ctx, `
 		select id, title, description, status, created_at
 		from programs
 		order by created_at desc
@@ -220,7 +267,7 @@ func (r *CatalogRepo) ListAllPrograms(ctx context.Context) ([]domain.Program, er
 }
 
 func (r *CatalogRepo) GetProgramWithGroupsAdmin(ctx context.Context, programID uuid.UUID) (ProgramWithGroups, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select id, title, description, status, created_at
 		from programs
 		where id=$1
@@ -234,11 +281,12 @@ func (r *CatalogRepo) GetProgramWithGroupsAdmin(ctx context.Context, programID u
 	p.Status = domain.ProgramStatus(st)
 
 	// для staff показываем ВСЕ группы (и закрытые тоже), чтобы админ мог их править
-	rows, err := r.db.Query(ctx, `
-		select id, program_id, cohort_id, title, capacity, is_open, requires_interview, created_at
-		from groups
-		where program_id=$1
-		order by created_at desc
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select `+groupColumns+`
+		from groups g
+		join cohorts c on c.id = g.cohort_id
+		where g.program_id=$1
+		order by g.created_at desc
 	`, programID)
 	if err != nil {
 		return ProgramWithGroups{}, err
@@ -247,8 +295,8 @@ func (r *CatalogRepo) GetProgramWithGroupsAdmin(ctx context.Context, programID u
 
 	gs := make([]domain.Group, 0)
 	for rows.Next() {
-		var g domain.Group
-		if err := rows.Scan(&g.ID, &g.ProgramID, &g.CohortID, &g.Title, &g.Capacity, &g.IsOpen, &g.RequiresInterview, &g.CreatedAt); err != nil {
+		g, err := scanGroup(rows)
+		if err != nil {
 			return ProgramWithGroups{}, err
 		}
 		gs = append(gs, g)
@@ -258,7 +306,7 @@ func (r *CatalogRepo) GetProgramWithGroupsAdmin(ctx context.Context, programID u
 }
 
 func (r *CatalogRepo) ListGroupTeachers(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := conn(ctx, r.db).Query(ctx, `
 		select teacher_user_id
 		from group_teachers
 		where group_id=$1
@@ -281,7 +329,7 @@ func (r *CatalogRepo) ListGroupTeachers(ctx context.Context, groupID uuid.UUID)
 }
 
 func (r *CatalogRepo) RemoveTeacherFromGroup(ctx context.Context, groupID, teacherUserID uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		delete from group_teachers
 		where group_id=$1 and teacher_user_id=$2
 	`, groupID, teacherUserID)
@@ -289,7 +337,7 @@ func (r *CatalogRepo) RemoveTeacherFromGroup(ctx context.Context, groupID, teach
 }
 
 func (r *CatalogRepo) UpdateGroup(ctx context.Context, groupID uuid.UUID, title *string, capacity *int, isOpen *bool, requiresInterview *bool) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		update groups
 		set
 			title = coalesce($2, title),
@@ -303,7 +351,7 @@ func (r *CatalogRepo) UpdateGroup(ctx context.Context, groupID uuid.UUID, title
 
 // Program without "published only" restriction (for staff view)
 func (r *CatalogRepo) GetProgram(ctx context.Context, programID uuid.UUID) (domain.Program, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select id, title, description, status, created_at
 		from programs
 		where id=$1
@@ -319,8 +367,8 @@ func (r *CatalogRepo) GetProgram(ctx context.Context, programID uuid.UUID) (doma
 }
 
 func (r *CatalogRepo) ListCohortsByProgram(ctx context.Context, programID uuid.UUID) ([]domain.Cohort, error) {
-	rows, err := r.db.Query(ctx, `
-		select id, program_id, year, created_at
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select id, program_id, year, created_at, opens_at, closes_at
 		from cohorts
 		where program_id=$1
 		order by year desc
@@ -333,7 +381,7 @@ func (r *CatalogRepo) ListCohortsByProgram(ctx context.Context, programID uuid.U
 	res := make([]domain.Cohort, 0)
 	for rows.Next() {
 		var c domain.Cohort
-		if err := rows.Scan(&c.ID, &c.ProgramID, &c.Year, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.ProgramID, &c.Year, &c.CreatedAt, &c.OpensAt, &c.ClosesAt); err != nil {
 			return nil, err
 		}
 		res = append(res, c)
@@ -342,11 +390,12 @@ func (r *CatalogRepo) ListCohortsByProgram(ctx context.Context, programID uuid.U
 }
 
 func (r *CatalogRepo) ListGroupsByProgram(ctx context.Context, programID uuid.UUID) ([]domain.Group, error) {
-	rows, err := r.db.Query(ctx, `
-		select id, program_id, cohort_id, title, capacity, is_open, requires_interview, created_at
-		from groups
-		where program_id=$1
-		order by created_at desc
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select `+groupColumns+`
+		from groups g
+		join cohorts c on c.id = g.cohort_id
+		where g.program_id=$1
+		order by g.created_at desc
 	`, programID)
 	if err != nil {
 		return nil, err
@@ -355,8 +404,8 @@ func (r *CatalogRepo) ListGroupsByProgram(ctx context.Context, programID uuid.UU
 
 	res := make([]domain.Group, 0)
 	for rows.Next() {
-		var g domain.Group
-		if err := rows.Scan(&g.ID, &g.ProgramID, &g.CohortID, &g.Title, &g.Capacity, &g.IsOpen, &g.RequiresInterview, &g.CreatedAt); err != nil {
+		g, err := scanGroup(rows)
+		if err != nil {
 			return nil, err
 		}
 		res = append(res, g)
@@ -366,10 +415,11 @@ func (r *CatalogRepo) ListGroupsByProgram(ctx context.Context, programID uuid.UU
 
 // teacher = назначение, не роль
 func (r *CatalogRepo) ListTeacherGroupsByProgram(ctx context.Context, teacherID, programID uuid.UUID) ([]domain.Group, error) {
-	rows, err := r.db.Query(ctx, `
-		select g.id, g.program_id, g.cohort_id, g.title, g.capacity, g.is_open, g.requires_interview, g.created_at
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select `+groupColumns+`
 		from group_teachers gt
 		join groups g on g.id = gt.group_id
+		join cohorts c on c.id = g.cohort_id
 		where gt.teacher_user_id=$1 and g.program_id=$2
 		order by g.created_at desc
 	`, teacherID, programID)
@@ -380,8 +430,8 @@ func (r *CatalogRepo) ListTeacherGroupsByProgram(ctx context.Context, teacherID,
 
 	res := make([]domain.Group, 0)
 	for rows.Next() {
-		var g domain.Group
-		if err := rows.Scan(&g.ID, &g.ProgramID, &g.CohortID, &g.Title, &g.Capacity, &g.IsOpen, &g.RequiresInterview, &g.CreatedAt); err != nil {
+		g, err := scanGroup(rows)
+		if err != nil {
 			return nil, err
 		}
 		res = append(res, g)
@@ -390,7 +440,7 @@ func (r *CatalogRepo) ListTeacherGroupsByProgram(ctx context.Context, teacherID,
 }
 
 func (r *CatalogRepo) UpdateProgram(ctx context.Context, id uuid.UUID, title *string, desc *string) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		update programs
 		set
 			title = coalesce($2, title),
@@ -401,14 +451,14 @@ func (r *CatalogRepo) UpdateProgram(ctx context.Context, id uuid.UUID, title *st
 }
 
 func (r *CatalogRepo) GetCohortByProgramYear(ctx context.Context, programID uuid.UUID, year int) (domain.Cohort, bool, error) {
-	row := r.db.QueryRow(ctx, `
-		select id, program_id, year, created_at
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select id, program_id, year, created_at, opens_at, closes_at
 		from cohorts
 		where program_id=$1 and year=$2
 	`, programID, year)
 
 	var c domain.Cohort
-	err := row.Scan(&c.ID, &c.ProgramID, &c.Year, &c.CreatedAt)
+	err := row.Scan(&c.ID, &c.ProgramID, &c.Year, &c.CreatedAt, &c.OpensAt, &c.ClosesAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return domain.Cohort{}, false, nil
@@ -420,7 +470,7 @@ func (r *CatalogRepo) GetCohortByProgramYear(ctx context.Context, programID uuid
 
 // true если teacher назначен хотя бы на одну группу этой программы
 func (r *CatalogRepo) IsTeacherInProgram(ctx context.Context, teacherID, programID uuid.UUID) (bool, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select exists(
 			select 1
 			from group_teachers gt
@@ -434,7 +484,89 @@ func (r *CatalogRepo) IsTeacherInProgram(ctx context.Context, teacherID, program
 }
 
 func (r *CatalogRepo) GetGroupProgramID(ctx context.Context, groupID uuid.UUID) (uuid.UUID, error) {
-	row := r.db.QueryRow(ctx, `select program_id from groups where id=$1`, groupID)
+	row := conn(ctx, r.db).QueryRow(ctx, `select program_id from groups where id=$1`, groupID)
 	var pid uuid.UUID
 	return pid, row.Scan(&pid)
 }
+
+// -------- Application windows --------
+
+// SetGroupWindow: окно приёма группы; nil — брать окно потока
+func (r *CatalogRepo) SetGroupWindow(ctx context.Context, groupID uuid.UUID, opensAt, closesAt *time.Time) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		update groups
+		set opens_at=$2, closes_at=$3
+		where id=$1
+	`, groupID, opensAt, closesAt)
+	return err
+}
+
+// SetCohortWindow: окно приёма по умолчанию для всех групп потока
+func (r *CatalogRepo) SetCohortWindow(ctx context.Context, cohortID uuid.UUID, opensAt, closesAt *time.Time) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update cohorts
+		set opens_at=$2, closes_at=$3
+		where id=$1
+	`, cohortID, opensAt, closesAt)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// GetGroupWindow: окно, заданное у самой группы
+func (r *CatalogRepo) GetGroupWindow(ctx context.Context, groupID uuid.UUID) (*time.Time, *time.Time, error) {
+	var opensAt, closesAt *time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, `select opens_at, closes_at from groups where id=$1`, groupID).Scan(&opensAt, &closesAt)
+	return opensAt, closesAt, err
+}
+
+func (r *CatalogRepo) GetCohortWindow(ctx context.Context, cohortID uuid.UUID) (*time.Time, *time.Time, error) {
+	var opensAt, closesAt *time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, `select opens_at, closes_at from cohorts where id=$1`, cohortID).Scan(&opensAt, &closesAt)
+	return opensAt, closesAt, err
+}
+
+// SyncGroupWindows: переключает is_open на границах окна приёма (открытие/закрытие).
+// window_state — последнее применённое состояние: ручные open/close между границами не перетираются.
+func (r *CatalogRepo) SyncGroupWindows(ctx context.Context) (opened, closed int, err error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		with w as (
+			select g.id,
+				case
+					when coalesce(g.opens_at, c.opens_at) is null and coalesce(g.closes_at, c.closes_at) is null then null
+					when coalesce(g.opens_at, c.opens_at) > now() then 'upcoming'
+					when coalesce(g.closes_at, c.closes_at) <= now() then 'closed'
+					else 'open'
+				end as state
+			from groups g
+			join cohorts c on c.id = g.cohort_id
+		)
+		update groups g
+		set window_state = w.state,
+			is_open = case when w.state is null then g.is_open else w.state = 'open' end
+		from w
+		where w.id = g.id and g.window_state is distinct from w.state
+		returning g.is_open, w.state is not null
+	`)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var open, windowed bool
+		if err := rows.Scan(&open, &windowed); err != nil {
+			return 0, 0, err
+		}
+		if !windowed {
+			continue
+		}
+		if open {
+			opened++
+		} else {
+			closed++
+		}
+	}
+	return opened, closed, rows.Err()
+}
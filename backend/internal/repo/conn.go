@@ -0,0 +1,16 @@
+// internal/repo/conn.go
+
+package repo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/db"
+)
+
+// conn: текущая транзакция (db.TxManager.Do) или пул
+func conn(ctx context.Context, pool *pgxpool.Pool) db.Querier {
+	return db.Conn(ctx, pool)
+}
@@ -0,0 +1,133 @@
+// internal/repo/eligibility_repo.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type EligibilityRepo struct{ db *pgxpool.Pool }
+
+func NewEligibilityRepo(db *pgxpool.Pool) *EligibilityRepo { return &EligibilityRepo{db: db} }
+
+const eligibilityColumns = `id, program_id, group_id, min_age, max_age, required_program_ids, max_active_enrollments, updated_by, created_at, updated_at`
+
+func scanEligibility(row pgx.Row) (domain.EligibilityRule, error) {
+	var e domain.EligibilityRule
+	err := row.Scan(&e.ID, &e.ProgramID, &e.GroupID, &e.MinAge, &e.MaxAge, &e.RequiredProgramIDs, &e.MaxActiveEnrollments,
+		&e.UpdatedBy, &e.CreatedAt, &e.UpdatedAt)
+	return e, err
+}
+
+func eligibilityOrNotFound(e domain.EligibilityRule, err error) (domain.EligibilityRule, bool, error) {
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.EligibilityRule{}, false, nil
+		}
+		return domain.EligibilityRule{}, false, err
+	}
+	return e, true, nil
+}
+
+func (r *EligibilityRepo) GetByProgram(ctx context.Context, programID uuid.UUID) (domain.EligibilityRule, bool, error) {
+	return eligibilityOrNotFound(scanEligibility(conn(ctx, r.db).QueryRow(ctx, `
+		select `+eligibilityColumns+`
+		from eligibility_rules
+		where program_id=$1
+	`, programID)))
+}
+
+func (r *EligibilityRepo) GetByGroup(ctx context.Context, groupID uuid.UUID) (domain.EligibilityRule, bool, error) {
+	return eligibilityOrNotFound(scanEligibility(conn(ctx, r.db).QueryRow(ctx, `
+		select `+eligibilityColumns+`
+		from eligibility_rules
+		where group_id=$1
+	`, groupID)))
+}
+
+// GetEffective: правило для заявки в группу — своё у группы, иначе программы
+func (r *EligibilityRepo) GetEffective(ctx context.Context, groupID uuid.UUID) (domain.EligibilityRule, bool, error) {
+	return eligibilityOrNotFound(scanEligibility(conn(ctx, r.db).QueryRow(ctx, `
+		select `+eligibilityColumns+`
+		from eligibility_rules
+		where group_id=$1
+		   or program_id = (select program_id from groups where id=$1)
+		order by group_id is null
+		limit 1
+	`, groupID)))
+}
+
+// UpsertForProgram / UpsertForGroup: одно правило на программу / группу
+func (r *EligibilityRepo) UpsertForProgram(ctx context.Context, programID uuid.UUID, e domain.EligibilityRule) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into eligibility_rules(id, program_id, min_age, max_age, required_program_ids, max_active_enrollments, updated_by)
+		values ($1,$2,$3,$4,$5,$6,$7)
+		on conflict (program_id) where program_id is not null
+		do update set min_age=excluded.min_age, max_age=excluded.max_age,
+			required_program_ids=excluded.required_program_ids,
+			max_active_enrollments=excluded.max_active_enrollments,
+			updated_by=excluded.updated_by, updated_at=now()
+	`, uuid.New(), programID, e.MinAge, e.MaxAge, requiredPrograms(e), e.MaxActiveEnrollments, e.UpdatedBy)
+	return err
+}
+
+func (r *EligibilityRepo) UpsertForGroup(ctx context.Context, groupID uuid.UUID, e domain.EligibilityRule) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into eligibility_rules(id, group_id, min_age, max_age, required_program_ids, max_active_enrollments, updated_by)
+		values ($1,$2,$3,$4,$5,$6,$7)
+		on conflict (group_id) where group_id is not null
+		do update set min_age=excluded.min_age, max_age=excluded.max_age,
+			required_program_ids=excluded.required_program_ids,
+			max_active_enrollments=excluded.max_active_enrollments,
+			updated_by=excluded.updated_by, updated_at=now()
+	`, uuid.New(), groupID, e.MinAge, e.MaxAge, requiredPrograms(e), e.MaxActiveEnrollments, e.UpdatedBy)
+	return err
+}
+
+// required_program_ids not null: nil-слайс пишем как пустой массив
+func requiredPrograms(e domain.EligibilityRule) []uuid.UUID {
+	if e.RequiredProgramIDs == nil {
+		return []uuid.UUID{}
+	}
+	return e.RequiredProgramIDs
+}
+
+func (r *EligibilityRepo) DeleteForProgram(ctx context.Context, programID uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `delete from eligibility_rules where program_id=$1`, programID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *EligibilityRepo) DeleteForGroup(ctx context.Context, groupID uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `delete from eligibility_rules where group_id=$1`, groupID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Facts: дата рождения из профиля, завершённые программы и число активных зачислений ученика
+func (r *EligibilityRepo) Facts(ctx context.Context, userID uuid.UUID) (domain.EligibilityFacts, error) {
+	var f domain.EligibilityFacts
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select
+			(select birth_date from profiles where user_id=$1),
+			coalesce((
+				select array_agg(distinct g.program_id)
+				from enrollments e
+				join groups g on g.id = e.group_id
+				where e.user_id=$1 and e.status='completed'
+			), '{}'),
+			(select count(*) from enrollments where user_id=$1 and status='active')
+	`, userID).Scan(&f.BirthDate, &f.CompletedProgramIDs, &f.ActiveEnrollments)
+	return f, err
+}
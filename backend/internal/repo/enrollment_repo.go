@@ -0,0 +1,267 @@
+// internal/repo/enrollment_repo.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+// EnrollmentRepo: статусы зачислений, аудит и переводы
+// (зачисление по заявке и проверки доступа — в ApplicationRepo)
+type EnrollmentRepo struct{ db *pgxpool.Pool }
+
+func NewEnrollmentRepo(db *pgxpool.Pool) *EnrollmentRepo { return &EnrollmentRepo{db: db} }
+
+const enrollmentColumns = `id, user_id, group_id, status, status_reason, ended_at, created_at, updated_at`
+
+func scanEnrollment(row pgx.Row) (domain.Enrollment, error) {
+	var e domain.Enrollment
+	var st string
+	if err := row.Scan(&e.ID, &e.UserID, &e.GroupID, &st, &e.Reason, &e.EndedAt, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return domain.Enrollment{}, err
+	}
+	e.Status = domain.EnrollmentStatus(st)
+	return e, nil
+}
+
+func (r *EnrollmentRepo) Get(ctx context.Context, id uuid.UUID) (domain.Enrollment, bool, error) {
+	e, err := scanEnrollment(conn(ctx, r.db).QueryRow(ctx, `
+		select `+enrollmentColumns+`
+		from enrollments
+		where id=$1
+	`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Enrollment{}, false, nil
+		}
+		return domain.Enrollment{}, false, err
+	}
+	return e, true, nil
+}
+
+// GetForUpdate: как Get, но с блокировкой строки (только внутри транзакции)
+func (r *EnrollmentRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (domain.Enrollment, error) {
+	return scanEnrollment(conn(ctx, r.db).QueryRow(ctx, `
+		select `+enrollmentColumns+`
+		from enrollments
+		where id=$1
+		for update
+	`, id))
+}
+
+func (r *EnrollmentRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.Enrollment, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select `+enrollmentColumns+`
+		from enrollments
+		where user_id=$1
+		order by created_at desc
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Enrollment
+	for rows.Next() {
+		e, err := scanEnrollment(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
+
+// ListByGroup: все зачисления группы (для staff), status — необязательный фильтр
+func (r *EnrollmentRepo) ListByGroup(ctx context.Context, groupID uuid.UUID, status *string) ([]domain.Enrollment, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select `+enrollmentColumns+`
+		from enrollments
+		where group_id=$1 and ($2::text is null or status=$2)
+		order by created_at asc
+	`, groupID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Enrollment
+	for rows.Next() {
+		e, err := scanEnrollment(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
+
+func (r *EnrollmentRepo) UpdateStatus(ctx context.Context, id uuid.UUID, to domain.EnrollmentStatus, reason string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		update enrollments
+		set status=$2,
+			status_reason=$3,
+			ended_at = case when $2='active' then null else now() end,
+			updated_at=now()
+		where id=$1
+	`, id, string(to), reason)
+	return err
+}
+
+func (r *EnrollmentRepo) InsertAudit(ctx context.Context, enrollmentID, actorID uuid.UUID, actorRole string, from, to domain.EnrollmentStatus, reason string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into enrollment_status_audit(id, enrollment_id, actor_user_id, actor_role, from_status, to_status, reason)
+		values ($1,$2,$3,$4,$5,$6,$7)
+	`, uuid.New(), enrollmentID, actorID, actorRole, string(from), string(to), reason)
+	return err
+}
+
+func (r *EnrollmentRepo) ListAudit(ctx context.Context, enrollmentID uuid.UUID) ([]domain.EnrollmentAudit, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select id, actor_user_id, actor_role, from_status, to_status, reason, created_at
+		from enrollment_status_audit
+		where enrollment_id=$1
+		order by created_at asc
+	`, enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.EnrollmentAudit
+	for rows.Next() {
+		var a domain.EnrollmentAudit
+		var from, to string
+		if err := rows.Scan(&a.ID, &a.ActorUserID, &a.ActorRole, &from, &to, &a.Reason, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.From, a.To = domain.EnrollmentStatus(from), domain.EnrollmentStatus(to)
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
+
+// -------- Transfers --------
+
+// GetByUserGroup: зачисление ученика в группу с любым статусом (с блокировкой строки)
+func (r *EnrollmentRepo) GetByUserGroup(ctx context.Context, userID, groupID uuid.UUID) (domain.Enrollment, bool, error) {
+	e, err := scanEnrollment(conn(ctx, r.db).QueryRow(ctx, `
+		select `+enrollmentColumns+`
+		from enrollments
+		where user_id=$1 and group_id=$2
+		for update
+	`, userID, groupID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Enrollment{}, false, nil
+		}
+		return domain.Enrollment{}, false, err
+	}
+	return e, true, nil
+}
+
+// Create: зачисление при переводе (по заявке — ApplicationRepo.CreateEnrollment)
+func (r *EnrollmentRepo) Create(ctx context.Context, e domain.Enrollment) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into enrollments(id, user_id, group_id, status, status_reason)
+		values ($1,$2,$3,$4,$5)
+	`, e.ID, e.UserID, e.GroupID, string(e.Status), e.Reason)
+	return err
+}
+
+// CopyMaterialReads: отметки о прочтении переносятся на материалы целевой группы
+// с теми же type/title/content (материал привязан к группе, копии в группах — разные строки)
+func (r *EnrollmentRepo) CopyMaterialReads(ctx context.Context, userID, fromGroupID, toGroupID uuid.UUID) (int, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		insert into material_reads(user_id, material_id, group_id, read_at)
+		select distinct on (mt.id) mr.user_id, mt.id, $3, mr.read_at
+		from material_reads mr
+		join materials ms on ms.id = mr.material_id
+		join materials mt on mt.group_id = $3
+			and mt.type = ms.type
+			and mt.title = ms.title
+			and mt.content = ms.content
+		where mr.user_id=$1 and mr.group_id=$2
+		order by mt.id, mr.read_at asc
+		on conflict (user_id, material_id) do nothing
+	`, userID, fromGroupID, toGroupID)
+	if err != nil {
+		return 0, err
+	}
+	return int(ct.RowsAffected()), nil
+}
+
+// CopySubmissions: работы (и их проверки) переносятся на задания целевой группы с тем же названием
+func (r *EnrollmentRepo) CopySubmissions(ctx context.Context, userID, fromGroupID, toGroupID uuid.UUID) (int, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		with src as (
+			select distinct on (ta.id) s.id as src_id, ta.id as assignment_id,
+				s.content_type, s.content, s.status, s.created_at
+			from submissions s
+			join assignments sa on sa.id = s.assignment_id
+			join assignments ta on ta.group_id = $3 and ta.title = sa.title
+			where s.student_user_id=$1 and s.group_id=$2
+			order by ta.id, s.updated_at desc
+		),
+		ins as (
+			insert into submissions(id, assignment_id, group_id, student_user_id, content_type, content, status, created_at, updated_at)
+			select gen_random_uuid(), src.assignment_id, $3, $1, src.content_type, src.content, src.status, src.created_at, now()
+			from src
+			on conflict (assignment_id, student_user_id) do nothing
+			returning id, assignment_id
+		),
+		rev as (
+			insert into submission_reviews(id, submission_id, reviewer_user_id, grade, comment, created_at)
+			select gen_random_uuid(), ins.id, rv.reviewer_user_id, rv.grade, rv.comment, rv.created_at
+			from ins
+			join src on src.assignment_id = ins.assignment_id
+			join submission_reviews rv on rv.submission_id = src.src_id
+		)
+		select count(*) from ins
+	`, userID, fromGroupID, toGroupID)
+	var n int
+	return n, row.Scan(&n)
+}
+
+func (r *EnrollmentRepo) InsertTransfer(ctx context.Context, t domain.EnrollmentTransfer) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into enrollment_transfers(id, user_id, from_enrollment_id, to_enrollment_id, from_group_id, to_group_id,
+			actor_user_id, actor_role, reason, carry_progress, carried_reads, carried_submissions)
+		values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+	`, t.ID, t.UserID, t.FromEnrollmentID, t.ToEnrollmentID, t.FromGroupID, t.ToGroupID,
+		t.ActorUserID, t.ActorRole, t.Reason, t.CarryProgress, t.CarriedReads, t.CarriedSubmissions)
+	return err
+}
+
+// ListTransfers: переводы, где зачисление было исходным или целевым
+func (r *EnrollmentRepo) ListTransfers(ctx context.Context, enrollmentID uuid.UUID) ([]domain.EnrollmentTransfer, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select id, user_id, from_enrollment_id, to_enrollment_id, from_group_id, to_group_id,
+			actor_user_id, actor_role, reason, carry_progress, carried_reads, carried_submissions, created_at
+		from enrollment_transfers
+		where from_enrollment_id=$1 or to_enrollment_id=$1
+		order by created_at asc
+	`, enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.EnrollmentTransfer
+	for rows.Next() {
+		var t domain.EnrollmentTransfer
+		if err := rows.Scan(&t.ID, &t.UserID, &t.FromEnrollmentID, &t.ToEnrollmentID, &t.FromGroupID, &t.ToGroupID,
+			&t.ActorUserID, &t.ActorRole, &t.Reason, &t.CarryProgress, &t.CarriedReads, &t.CarriedSubmissions, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
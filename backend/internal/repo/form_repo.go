@@ -0,0 +1,110 @@
+// internal/repo/form_repo.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type FormRepo struct{ db *pgxpool.Pool }
+
+func NewFormRepo(db *pgxpool.Pool) *FormRepo { return &FormRepo{db: db} }
+
+const formColumns = `id, program_id, group_id, questions, updated_by, created_at, updated_at`
+
+func scanForm(row pgx.Row) (domain.ApplicationForm, error) {
+	var f domain.ApplicationForm
+	var qs []byte
+	if err := row.Scan(&f.ID, &f.ProgramID, &f.GroupID, &qs, &f.UpdatedBy, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return domain.ApplicationForm{}, err
+	}
+	if err := json.Unmarshal(qs, &f.Questions); err != nil {
+		return domain.ApplicationForm{}, err
+	}
+	return f, nil
+}
+
+func formOrNotFound(f domain.ApplicationForm, err error) (domain.ApplicationForm, bool, error) {
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ApplicationForm{}, false, nil
+		}
+		return domain.ApplicationForm{}, false, err
+	}
+	return f, true, nil
+}
+
+func (r *FormRepo) GetByProgram(ctx context.Context, programID uuid.UUID) (domain.ApplicationForm, bool, error) {
+	return formOrNotFound(scanForm(conn(ctx, r.db).QueryRow(ctx, `
+		select `+formColumns+`
+		from application_forms
+		where program_id=$1
+	`, programID)))
+}
+
+func (r *FormRepo) GetByGroup(ctx context.Context, groupID uuid.UUID) (domain.ApplicationForm, bool, error) {
+	return formOrNotFound(scanForm(conn(ctx, r.db).QueryRow(ctx, `
+		select `+formColumns+`
+		from application_forms
+		where group_id=$1
+	`, groupID)))
+}
+
+// GetEffective: анкета, которую заполняет заявитель в группу — своя у группы, иначе программы
+func (r *FormRepo) GetEffective(ctx context.Context, groupID uuid.UUID) (domain.ApplicationForm, bool, error) {
+	return formOrNotFound(scanForm(conn(ctx, r.db).QueryRow(ctx, `
+		select `+formColumns+`
+		from application_forms f
+		where f.group_id=$1
+		   or f.program_id = (select program_id from groups where id=$1)
+		order by f.group_id is null
+		limit 1
+	`, groupID)))
+}
+
+// UpsertForProgram / UpsertForGroup: одна анкета на программу / группу
+func (r *FormRepo) UpsertForProgram(ctx context.Context, programID uuid.UUID, questions []domain.Question, actorID uuid.UUID) error {
+	qs, _ := json.Marshal(questions)
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into application_forms(id, program_id, questions, updated_by)
+		values ($1,$2,$3,$4)
+		on conflict (program_id) where program_id is not null
+		do update set questions=excluded.questions, updated_by=excluded.updated_by, updated_at=now()
+	`, uuid.New(), programID, qs, actorID)
+	return err
+}
+
+func (r *FormRepo) UpsertForGroup(ctx context.Context, groupID uuid.UUID, questions []domain.Question, actorID uuid.UUID) error {
+	qs, _ := json.Marshal(questions)
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into application_forms(id, group_id, questions, updated_by)
+		values ($1,$2,$3,$4)
+		on conflict (group_id) where group_id is not null
+		do update set questions=excluded.questions, updated_by=excluded.updated_by, updated_at=now()
+	`, uuid.New(), groupID, qs, actorID)
+	return err
+}
+
+func (r *FormRepo) DeleteForProgram(ctx context.Context, programID uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `delete from application_forms where program_id=$1`, programID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *FormRepo) DeleteForGroup(ctx context.Context, groupID uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `delete from application_forms where group_id=$1`, groupID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
@@ -0,0 +1,110 @@
+// internal/repo/guardian_repo.go
+
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GuardianRepo struct{ db *pgxpool.Pool }
+
+func NewGuardianRepo(db *pgxpool.Pool) *GuardianRepo { return &GuardianRepo{db: db} }
+
+type ChildView struct {
+	UserID    uuid.UUID
+	FullName  *string
+	BirthDate *time.Time
+	School    *string
+	Grade     *int
+	LinkedAt  time.Time
+}
+
+func (r *GuardianRepo) Link(ctx context.Context, guardianID, childID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into guardian_links(guardian_user_id, child_user_id)
+		values ($1,$2)
+		on conflict do nothing
+	`, guardianID, childID)
+	return err
+}
+
+// Unlink: false — связи нет или это последний guardian ребёнка без собственного логина
+// (такой аккаунт без связи недоступен никому). Связи ребёнка блокируются, чтобы два guardian
+// не отвязались одновременно.
+func (r *GuardianRepo) Unlink(ctx context.Context, guardianID, childID uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		with links as (
+			select guardian_user_id from guardian_links
+			where child_user_id=$2
+			for update
+		)
+		delete from guardian_links
+		where guardian_user_id=$1 and child_user_id=$2
+		  and (exists(select 1 from users where id=$2) or (select count(*) from links) > 1)
+	`, guardianID, childID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() == 1, nil
+}
+
+func (r *GuardianRepo) IsGuardianOf(ctx context.Context, guardianID, childID uuid.UUID) (bool, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select exists(
+			select 1 from guardian_links where guardian_user_id=$1 and child_user_id=$2
+		)
+	`, guardianID, childID)
+	var ok bool
+	return ok, row.Scan(&ok)
+}
+
+func (r *GuardianRepo) ListChildren(ctx context.Context, guardianID uuid.UUID) ([]ChildView, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select gl.child_user_id, pr.full_name, pr.birth_date, pr.school, pr.grade, gl.created_at
+		from guardian_links gl
+		left join profiles pr on pr.user_id = gl.child_user_id
+		where gl.guardian_user_id=$1
+		order by gl.created_at asc
+	`, guardianID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]ChildView, 0)
+	for rows.Next() {
+		var c ChildView
+		if err := rows.Scan(&c.UserID, &c.FullName, &c.BirthDate, &c.School, &c.Grade, &c.LinkedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+// ListGuardians: получатели уведомлений ребёнка помимо него самого
+func (r *GuardianRepo) ListGuardians(ctx context.Context, childID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select guardian_user_id
+		from guardian_links
+		where child_user_id=$1
+	`, childID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		res = append(res, id)
+	}
+	return res, rows.Err()
+}
@@ -17,7 +17,7 @@ type InterviewRepo struct{ db *pgxpool.Pool }
 func NewInterviewRepo(db *pgxpool.Pool) *InterviewRepo { return &InterviewRepo{db: db} }
 
 func (r *InterviewRepo) GetByApplication(ctx context.Context, appID uuid.UUID) (domain.Interview, bool, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select id, application_id, group_id, candidate_user_id, interviewer_user_id, interviewer_role,
 		       result, comment, created_at, updated_at
 		from interviews
@@ -38,7 +38,7 @@ func (r *InterviewRepo) GetByApplication(ctx context.Context, appID uuid.UUID) (
 
 func (r *InterviewRepo) Upsert(ctx context.Context, in domain.Interview) error {
 	now := time.Now()
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into interviews(id, application_id, group_id, candidate_user_id, interviewer_user_id, interviewer_role, result, comment, created_at, updated_at)
 		values ($1,$2,$3,$4,$5,$6,$7,$8, now(), now())
 		on conflict (application_id) do update set
@@ -51,3 +51,19 @@ func (r *InterviewRepo) Upsert(ctx context.Context, in domain.Interview) error {
 	_ = now
 	return err
 }
+
+// EnsurePending: собеседование назначено (бронь слота) — результат pending, пока преподаватель его не внесёт.
+// Окончательный результат (recommended/not_recommended) не перезаписывается.
+func (r *InterviewRepo) EnsurePending(ctx context.Context, in domain.Interview) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into interviews(id, application_id, group_id, candidate_user_id, interviewer_user_id, interviewer_role, result, comment, created_at, updated_at)
+		values ($1,$2,$3,$4,$5,$6,'pending','', now(), now())
+		on conflict (application_id) do update set
+			interviewer_user_id=excluded.interviewer_user_id,
+			interviewer_role=excluded.interviewer_role,
+			result='pending',
+			updated_at=now()
+		where interviews.result in ('pending','needs_more')
+	`, uuid.New(), in.ApplicationID, in.GroupID, in.CandidateUserID, in.InterviewerUserID, in.InterviewerRole)
+	return err
+}
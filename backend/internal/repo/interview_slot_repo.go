@@ -0,0 +1,203 @@
+// internal/repo/interview_slot_repo.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type InterviewSlotRepo struct{ db *pgxpool.Pool }
+
+func NewInterviewSlotRepo(db *pgxpool.Pool) *InterviewSlotRepo { return &InterviewSlotRepo{db: db} }
+
+const interviewSlotColumns = `s.id, s.group_id, s.teacher_user_id, s.starts_at, s.ends_at, s.location, s.online_url,
+	s.application_id, s.booked_at, s.reminder_sent_at, s.created_at`
+
+// slotHeld: слот занят заявкой, которая ещё в работе.
+// Заявку одобрили/отклонили/отозвали — её будущий слот снова свободен без отдельной отмены.
+const slotHeld = `exists (select 1 from enrollment_applications ea where ea.id = s.application_id and ea.status = 'in_review')`
+
+func scanInterviewSlot(row pgx.Row) (domain.InterviewSlot, error) {
+	var s domain.InterviewSlot
+	err := row.Scan(&s.ID, &s.GroupID, &s.TeacherUserID, &s.StartsAt, &s.EndsAt, &s.Location, &s.OnlineURL,
+		&s.ApplicationID, &s.BookedAt, &s.ReminderSentAt, &s.CreatedAt)
+	return s, err
+}
+
+func (r *InterviewSlotRepo) list(ctx context.Context, q string, args ...any) ([]domain.InterviewSlot, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.InterviewSlot, 0)
+	for rows.Next() {
+		s, err := scanInterviewSlot(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+func (r *InterviewSlotRepo) Create(ctx context.Context, s domain.InterviewSlot) (domain.InterviewSlot, error) {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		insert into interview_slots(id, group_id, teacher_user_id, starts_at, ends_at, location, online_url)
+		values ($1,$2,$3,$4,$5,$6,$7)
+		returning created_at
+	`, s.ID, s.GroupID, s.TeacherUserID, s.StartsAt, s.EndsAt, s.Location, s.OnlineURL).Scan(&s.CreatedAt)
+	return s, err
+}
+
+func (r *InterviewSlotRepo) Get(ctx context.Context, id uuid.UUID) (domain.InterviewSlot, error) {
+	return scanInterviewSlot(conn(ctx, r.db).QueryRow(ctx, `
+		select `+interviewSlotColumns+`
+		from interview_slots s
+		where s.id=$1
+	`, id))
+}
+
+// GetForUpdate: как Get, но с блокировкой строки слота (только внутри транзакции)
+func (r *InterviewSlotRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (domain.InterviewSlot, error) {
+	return scanInterviewSlot(conn(ctx, r.db).QueryRow(ctx, `
+		select `+interviewSlotColumns+`
+		from interview_slots s
+		where s.id=$1
+		for update
+	`, id))
+}
+
+// IsHeld: слот занят заявкой in_review
+func (r *InterviewSlotRepo) IsHeld(ctx context.Context, id uuid.UUID) (bool, error) {
+	var held bool
+	err := conn(ctx, r.db).QueryRow(ctx, `select `+slotHeld+` from interview_slots s where s.id=$1`, id).Scan(&held)
+	return held, err
+}
+
+// ListByGroup: все слоты группы, начиная с from (для преподавателя — с бронями)
+func (r *InterviewSlotRepo) ListByGroup(ctx context.Context, groupID uuid.UUID, from time.Time) ([]domain.InterviewSlot, error) {
+	return r.list(ctx, `
+		select `+interviewSlotColumns+`
+		from interview_slots s
+		where s.group_id=$1 and s.ends_at >= $2
+		order by s.starts_at asc, s.id asc
+	`, groupID, from)
+}
+
+// ListOpen: свободные слоты группы, которые начнутся после after
+func (r *InterviewSlotRepo) ListOpen(ctx context.Context, groupID uuid.UUID, after time.Time) ([]domain.InterviewSlot, error) {
+	res, err := r.list(ctx, `
+		select `+interviewSlotColumns+`
+		from interview_slots s
+		where s.group_id=$1 and s.starts_at > $2
+		  and (s.application_id is null or not `+slotHeld+`)
+		order by s.starts_at asc, s.id asc
+	`, groupID, after)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res {
+		// бывшая бронь заявки, ушедшей из in_review, — заявителю не показываем
+		res[i].ApplicationID, res[i].BookedAt, res[i].ReminderSentAt = nil, nil, nil
+	}
+	return res, nil
+}
+
+// GetByApplication: слот, забронированный заявкой
+func (r *InterviewSlotRepo) GetByApplication(ctx context.Context, appID uuid.UUID) (domain.InterviewSlot, bool, error) {
+	s, err := scanInterviewSlot(conn(ctx, r.db).QueryRow(ctx, `
+		select `+interviewSlotColumns+`
+		from interview_slots s
+		where s.application_id=$1
+	`, appID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.InterviewSlot{}, false, nil
+	}
+	if err != nil {
+		return domain.InterviewSlot{}, false, err
+	}
+	return s, true, nil
+}
+
+// LockTeacher: сериализует публикацию слотов одного преподавателя (до конца транзакции)
+func (r *InterviewSlotRepo) LockTeacher(ctx context.Context, teacherID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `select pg_advisory_xact_lock(hashtextextended($1::text, 0))`, teacherID)
+	return err
+}
+
+// HasOverlap: у преподавателя уже есть слот, пересекающийся с [startsAt, endsAt)
+func (r *InterviewSlotRepo) HasOverlap(ctx context.Context, teacherID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	var ok bool
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select exists (
+			select 1 from interview_slots
+			where teacher_user_id=$1 and starts_at < $3 and ends_at > $2
+		)
+	`, teacherID, startsAt, endsAt).Scan(&ok)
+	return ok, err
+}
+
+// Book: занять слот, если он свободен и ещё не начался. false — слот уже занят (или прошёл).
+func (r *InterviewSlotRepo) Book(ctx context.Context, id, appID uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update interview_slots s
+		set application_id=$2, booked_at=now(), reminder_sent_at=null
+		where s.id=$1 and s.starts_at > now()
+		  and (s.application_id is null or not `+slotHeld+`)
+	`, id, appID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Release: снять бронь заявки со слота
+func (r *InterviewSlotRepo) Release(ctx context.Context, id, appID uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update interview_slots
+		set application_id=null, booked_at=null, reminder_sent_at=null
+		where id=$1 and application_id=$2
+	`, id, appID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *InterviewSlotRepo) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `delete from interview_slots where id=$1`, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// DueReminders: забронированные слоты, которые начнутся до until и по которым ещё не напоминали
+// (с блокировкой строк; параллельные экземпляры берут разные слоты)
+func (r *InterviewSlotRepo) DueReminders(ctx context.Context, until time.Time, limit int) ([]domain.InterviewSlot, error) {
+	return r.list(ctx, `
+		select `+interviewSlotColumns+`
+		from interview_slots s
+		where s.application_id is not null and s.reminder_sent_at is null
+		  and s.starts_at > now() and s.starts_at <= $1
+		  and `+slotHeld+`
+		order by s.starts_at asc
+		limit $2
+		for update of s skip locked
+	`, until, limit)
+}
+
+func (r *InterviewSlotRepo) MarkReminded(ctx context.Context, id uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `update interview_slots set reminder_sent_at=now() where id=$1`, id)
+	return err
+}
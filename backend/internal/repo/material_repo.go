@@ -16,7 +16,7 @@ type MaterialRepo struct{ db *pgxpool.Pool }
 func NewMaterialRepo(db *pgxpool.Pool) *MaterialRepo { return &MaterialRepo{db: db} }
 
 func (r *MaterialRepo) Create(ctx context.Context, m domain.Material) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into materials(id, group_id, type, title, content, created_by_user_id)
 		values ($1,$2,$3,$4,$5,$6)
 	`, m.ID, m.GroupID, string(m.Type), m.Title, m.Content, m.CreatedBy)
@@ -24,7 +24,7 @@ func (r *MaterialRepo) Create(ctx context.Context, m domain.Material) error {
 }
 
 func (r *MaterialRepo) ListByGroup(ctx context.Context, groupID uuid.UUID) ([]domain.Material, error) {
-	rows, err := r.db.Query(ctx, `
+	rows, err := conn(ctx, r.db).Query(ctx, `
 		select id, group_id, type, title, content, created_by_user_id, created_at
 		from materials
 		where group_id=$1
@@ -49,7 +49,7 @@ func (r *MaterialRepo) ListByGroup(ctx context.Context, groupID uuid.UUID) ([]do
 }
 
 func (r *MaterialRepo) Get(ctx context.Context, id uuid.UUID) (domain.Material, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select id, group_id, type, title, content, created_by_user_id, created_at
 		from materials
 		where id=$1
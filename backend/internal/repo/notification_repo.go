@@ -0,0 +1,188 @@
+// internal/repo/notification_repo.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type NotificationRepo struct{ db *pgxpool.Pool }
+
+func NewNotificationRepo(db *pgxpool.Pool) *NotificationRepo { return &NotificationRepo{db: db} }
+
+// GetPreferences: настроек нет — значения по умолчанию
+func (r *NotificationRepo) GetPreferences(ctx context.Context, userID uuid.UUID) (domain.NotificationPreferences, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select user_id, locale, email_enabled, muted_kinds, updated_at
+		from notification_preferences
+		where user_id=$1
+	`, userID)
+
+	var p domain.NotificationPreferences
+	err := row.Scan(&p.UserID, &p.Locale, &p.EmailEnabled, &p.MutedKinds, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.DefaultNotificationPreferences(userID), nil
+		}
+		return domain.NotificationPreferences{}, err
+	}
+	return p, nil
+}
+
+func (r *NotificationRepo) UpsertPreferences(ctx context.Context, p domain.NotificationPreferences) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into notification_preferences(user_id, locale, email_enabled, muted_kinds)
+		values ($1,$2,$3,$4)
+		on conflict (user_id) do update set
+			locale=excluded.locale,
+			email_enabled=excluded.email_enabled,
+			muted_kinds=excluded.muted_kinds,
+			updated_at=now()
+	`, p.UserID, p.Locale, p.EmailEnabled, p.MutedKinds)
+	return err
+}
+
+// Recipient: куда и как обращаться
+type Recipient struct {
+	UserID   uuid.UUID
+	Email    string // profiles.contact_email, иначе users.email; "" — писать некуда
+	FullName string
+}
+
+func (r *NotificationRepo) GetRecipient(ctx context.Context, userID uuid.UUID) (Recipient, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select coalesce(nullif(pr.contact_email,''), u.email, ''), coalesce(pr.full_name, '')
+		from (select $1::uuid as id) x
+		left join users u on u.id = x.id
+		left join profiles pr on pr.user_id = x.id
+	`, userID)
+
+	rc := Recipient{UserID: userID}
+	err := row.Scan(&rc.Email, &rc.FullName)
+	return rc, err
+}
+
+// GroupTitles: название группы и программы для текста письма
+func (r *NotificationRepo) GroupTitles(ctx context.Context, groupID uuid.UUID) (string, string, error) {
+	var group, program string
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select g.title, p.title
+		from groups g
+		join programs p on p.id = g.program_id
+		where g.id=$1
+	`, groupID).Scan(&group, &program)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", "", nil
+	}
+	return group, program, err
+}
+
+// MarkSent: false — уже отправляли (повтор события)
+func (r *NotificationRepo) MarkSent(ctx context.Context, eventID, userID uuid.UUID, channel, kind string) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		insert into notification_log(event_id, user_id, channel, kind)
+		values ($1,$2,$3,$4)
+		on conflict do nothing
+	`, eventID, userID, channel, kind)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *NotificationRepo) UnmarkSent(ctx context.Context, eventID, userID uuid.UUID, channel string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		delete from notification_log
+		where event_id=$1 and user_id=$2 and channel=$3
+	`, eventID, userID, channel)
+	return err
+}
+
+// -------- Inbox --------
+
+// CreateInbox: повтор события — no-op (unique event_id, user_id)
+func (r *NotificationRepo) CreateInbox(ctx context.Context, n domain.Notification) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into notifications(id, user_id, event_id, kind, title, body, data)
+		values ($1,$2,$3,$4,$5,$6,$7)
+		on conflict (event_id, user_id) do nothing
+	`, n.ID, n.UserID, n.EventID, n.Kind, n.Title, n.Body, n.Data)
+	return err
+}
+
+func (r *NotificationRepo) ListInbox(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Notification, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select id, user_id, event_id, kind, title, body, data, read_at, created_at
+		from notifications
+		where user_id=$1 and (not $2 or read_at is null)
+		order by created_at desc, id
+		limit $3 offset $4
+	`, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.Notification, 0)
+	for rows.Next() {
+		var n domain.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.EventID, &n.Kind, &n.Title, &n.Body, &n.Data, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+	}
+	return res, rows.Err()
+}
+
+func (r *NotificationRepo) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select count(*) from notifications where user_id=$1 and read_at is null
+	`, userID).Scan(&n)
+	return n, err
+}
+
+// MarkRead: false — нет такого уведомления у пользователя (повторная отметка — true)
+func (r *NotificationRepo) MarkRead(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update notifications
+		set read_at=coalesce(read_at, now())
+		where id=$1 and user_id=$2
+	`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *NotificationRepo) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update notifications
+		set read_at=now()
+		where user_id=$1 and read_at is null
+	`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
+
+// DeleteOlderThan: retention входящих и журнала отправки
+func (r *NotificationRepo) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `delete from notifications where created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := conn(ctx, r.db).Exec(ctx, `delete from notification_log where created_at < $1`, before); err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
@@ -0,0 +1,52 @@
+// internal/repo/profile_repo.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type ProfileRepo struct{ db *pgxpool.Pool }
+
+func NewProfileRepo(db *pgxpool.Pool) *ProfileRepo { return &ProfileRepo{db: db} }
+
+func (r *ProfileRepo) Get(ctx context.Context, userID uuid.UUID) (domain.Profile, bool, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select user_id, full_name, birth_date, school, grade, contact_email, contact_phone, created_at, updated_at
+		from profiles
+		where user_id=$1
+	`, userID)
+
+	var p domain.Profile
+	err := row.Scan(&p.UserID, &p.FullName, &p.BirthDate, &p.School, &p.Grade, &p.ContactEmail, &p.ContactPhone, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Profile{}, false, nil
+		}
+		return domain.Profile{}, false, err
+	}
+	return p, true, nil
+}
+
+func (r *ProfileRepo) Upsert(ctx context.Context, p domain.Profile) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into profiles(user_id, full_name, birth_date, school, grade, contact_email, contact_phone)
+		values ($1,$2,$3,$4,$5,$6,$7)
+		on conflict (user_id) do update set
+			full_name=excluded.full_name,
+			birth_date=excluded.birth_date,
+			school=excluded.school,
+			grade=excluded.grade,
+			contact_email=excluded.contact_email,
+			contact_phone=excluded.contact_phone,
+			updated_at=now()
+	`, p.UserID, p.FullName, p.BirthDate, p.School, p.Grade, p.ContactEmail, p.ContactPhone)
+	return err
+}
@@ -15,7 +15,7 @@ func NewProgressRepo(db *pgxpool.Pool) *ProgressRepo { return &ProgressRepo{db:
 
 // MarkRead is idempotent.
 func (r *ProgressRepo) MarkRead(ctx context.Context, userID, materialID, groupID uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into material_reads(user_id, material_id, group_id)
 		values ($1,$2,$3)
 		on conflict (user_id, material_id) do update set read_at=now()
@@ -24,7 +24,30 @@ func (r *ProgressRepo) MarkRead(ctx context.Context, userID, materialID, groupID
 }
 
 func (r *ProgressRepo) IsRead(ctx context.Context, userID, materialID uuid.UUID) (bool, error) {
-	row := r.db.QueryRow(ctx, `select exists(select 1 from material_reads where user_id=$1 and material_id=$2)`, userID, materialID)
+	row := conn(ctx, r.db).QueryRow(ctx, `select exists(select 1 from material_reads where user_id=$1 and material_id=$2)`, userID, materialID)
 	var ok bool
 	return ok, row.Scan(&ok)
 }
+
+type ProgressSummary struct {
+	MaterialsTotal      int
+	MaterialsRead       int
+	AssignmentsTotal    int
+	SubmissionsTotal    int
+	SubmissionsReviewed int
+}
+
+func (r *ProgressRepo) Summary(ctx context.Context, userID, groupID uuid.UUID) (ProgressSummary, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select
+			(select count(*) from materials where group_id=$2),
+			(select count(*) from material_reads where group_id=$2 and user_id=$1),
+			(select count(*) from assignments where group_id=$2),
+			(select count(*) from submissions where group_id=$2 and student_user_id=$1),
+			(select count(*) from submissions where group_id=$2 and student_user_id=$1 and status='reviewed')
+	`, userID, groupID)
+
+	var p ProgressSummary
+	err := row.Scan(&p.MaterialsTotal, &p.MaterialsRead, &p.AssignmentsTotal, &p.SubmissionsTotal, &p.SubmissionsReviewed)
+	return p, err
+}
@@ -0,0 +1,175 @@
+// internal/repo/rule_repo.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type RuleRepo struct{ db *pgxpool.Pool }
+
+func NewRuleRepo(db *pgxpool.Pool) *RuleRepo { return &RuleRepo{db: db} }
+
+const ruleColumns = `id, name, event_type, conditions, action, action_params, is_enabled, created_by, created_at, updated_at`
+
+func scanRule(row pgx.Row) (domain.Rule, error) {
+	var ru domain.Rule
+	var conds, params []byte
+	var action string
+	if err := row.Scan(&ru.ID, &ru.Name, &ru.EventType, &conds, &action, &params, &ru.IsEnabled, &ru.CreatedBy, &ru.CreatedAt, &ru.UpdatedAt); err != nil {
+		return domain.Rule{}, err
+	}
+	ru.Action = domain.RuleAction(action)
+	if err := json.Unmarshal(conds, &ru.Conditions); err != nil {
+		return domain.Rule{}, err
+	}
+	if err := json.Unmarshal(params, &ru.ActionParams); err != nil {
+		return domain.Rule{}, err
+	}
+	return ru, nil
+}
+
+func (r *RuleRepo) Create(ctx context.Context, ru domain.Rule) error {
+	conds, _ := json.Marshal(ru.Conditions)
+	params, _ := json.Marshal(ru.ActionParams)
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into automation_rules(id, name, event_type, conditions, action, action_params, is_enabled, created_by)
+		values ($1,$2,$3,$4,$5,$6,$7,$8)
+	`, ru.ID, ru.Name, ru.EventType, conds, string(ru.Action), params, ru.IsEnabled, ru.CreatedBy)
+	return err
+}
+
+// Update: false — правила нет
+func (r *RuleRepo) Update(ctx context.Context, ru domain.Rule) (bool, error) {
+	conds, _ := json.Marshal(ru.Conditions)
+	params, _ := json.Marshal(ru.ActionParams)
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update automation_rules
+		set name=$2, event_type=$3, conditions=$4, action=$5, action_params=$6, is_enabled=$7, updated_at=now()
+		where id=$1
+	`, ru.ID, ru.Name, ru.EventType, conds, string(ru.Action), params, ru.IsEnabled)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *RuleRepo) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `delete from automation_rules where id=$1`, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *RuleRepo) Get(ctx context.Context, id uuid.UUID) (domain.Rule, bool, error) {
+	ru, err := scanRule(conn(ctx, r.db).QueryRow(ctx, `
+		select `+ruleColumns+`
+		from automation_rules
+		where id=$1
+	`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Rule{}, false, nil
+		}
+		return domain.Rule{}, false, err
+	}
+	return ru, true, nil
+}
+
+func (r *RuleRepo) List(ctx context.Context) ([]domain.Rule, error) {
+	return r.list(ctx, `
+		select `+ruleColumns+`
+		from automation_rules
+		order by created_at desc
+	`)
+}
+
+// ListEnabledByEvent — для движка правил
+func (r *RuleRepo) ListEnabledByEvent(ctx context.Context, eventType string) ([]domain.Rule, error) {
+	return r.list(ctx, `
+		select `+ruleColumns+`
+		from automation_rules
+		where event_type=$1 and is_enabled
+		order by created_at
+	`, eventType)
+}
+
+func (r *RuleRepo) list(ctx context.Context, sql string, args ...any) ([]domain.Rule, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.Rule, 0)
+	for rows.Next() {
+		ru, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ru)
+	}
+	return res, rows.Err()
+}
+
+// -------- Executions --------
+
+func (r *RuleRepo) ExecutionSucceeded(ctx context.Context, ruleID, eventID uuid.UUID) (bool, error) {
+	var ok bool
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select exists(
+			select 1 from rule_executions
+			where rule_id=$1 and event_id=$2 and status='success'
+		)
+	`, ruleID, eventID).Scan(&ok)
+	return ok, err
+}
+
+// RecordExecution: повтор того же события обновляет строку и увеличивает attempts
+func (r *RuleRepo) RecordExecution(ctx context.Context, ruleID, eventID uuid.UUID, eventType string, status domain.RuleExecutionStatus, execErr *string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into rule_executions(id, rule_id, event_id, event_type, status, error)
+		values ($1,$2,$3,$4,$5,$6)
+		on conflict (rule_id, event_id) do update set
+			status=excluded.status,
+			error=excluded.error,
+			attempts=rule_executions.attempts+1,
+			updated_at=now()
+	`, uuid.New(), ruleID, eventID, eventType, string(status), execErr)
+	return err
+}
+
+func (r *RuleRepo) ListExecutions(ctx context.Context, ruleID uuid.UUID, limit int) ([]domain.RuleExecution, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select id, rule_id, event_id, event_type, status, attempts, error, created_at, updated_at
+		from rule_executions
+		where rule_id=$1
+		order by created_at desc
+		limit $2
+	`, ruleID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.RuleExecution, 0)
+	for rows.Next() {
+		var e domain.RuleExecution
+		var st string
+		if err := rows.Scan(&e.ID, &e.RuleID, &e.EventID, &e.EventType, &st, &e.Attempts, &e.Error, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		e.Status = domain.RuleExecutionStatus(st)
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
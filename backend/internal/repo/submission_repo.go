@@ -17,7 +17,7 @@ type SubmissionRepo struct{ db *pgxpool.Pool }
 func NewSubmissionRepo(db *pgxpool.Pool) *SubmissionRepo { return &SubmissionRepo{db: db} }
 
 func (r *SubmissionRepo) Upsert(ctx context.Context, s domain.Submission) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into submissions(id, assignment_id, group_id, student_user_id, content_type, content, status)
 		values ($1,$2,$3,$4,$5,$6,$7)
 		on conflict (assignment_id, student_user_id) do update set
@@ -30,7 +30,7 @@ func (r *SubmissionRepo) Upsert(ctx context.Context, s domain.Submission) error
 }
 
 func (r *SubmissionRepo) GetByAssignmentAndStudent(ctx context.Context, assignmentID, studentID uuid.UUID) (domain.Submission, bool, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select id, assignment_id, group_id, student_user_id, content_type, content, status, created_at, updated_at
 		from submissions
 		where assignment_id=$1 and student_user_id=$2
@@ -59,7 +59,7 @@ func (r *SubmissionRepo) ListByGroup(ctx context.Context, groupID uuid.UUID, sta
 	}
 	q += ` order by created_at desc`
 
-	rows, err := r.db.Query(ctx, q, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +79,7 @@ func (r *SubmissionRepo) ListByGroup(ctx context.Context, groupID uuid.UUID, sta
 }
 
 func (r *SubmissionRepo) AddReview(ctx context.Context, rv domain.SubmissionReview) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		insert into submission_reviews(id, submission_id, reviewer_user_id, grade, comment)
 		values ($1,$2,$3,$4,$5)
 	`, rv.ID, rv.SubmissionID, rv.ReviewerID, rv.Grade, rv.Comment)
@@ -87,7 +87,7 @@ func (r *SubmissionRepo) AddReview(ctx context.Context, rv domain.SubmissionRevi
 }
 
 func (r *SubmissionRepo) LatestReview(ctx context.Context, submissionID uuid.UUID) (domain.SubmissionReview, bool, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select id, submission_id, reviewer_user_id, grade, comment, created_at
 		from submission_reviews
 		where submission_id=$1
@@ -106,14 +106,14 @@ func (r *SubmissionRepo) LatestReview(ctx context.Context, submissionID uuid.UUI
 }
 
 func (r *SubmissionRepo) SetStatus(ctx context.Context, submissionID uuid.UUID, st domain.SubmissionStatus) error {
-	_, err := r.db.Exec(ctx, `update submissions set status=$2, updated_at=now() where id=$1`, submissionID, string(st))
+	_, err := conn(ctx, r.db).Exec(ctx, `update submissions set status=$2, updated_at=now() where id=$1`, submissionID, string(st))
 	return err
 }
 
 var _ = time.Now
 
 func (r *SubmissionRepo) Get(ctx context.Context, id uuid.UUID) (domain.Submission, error) {
-	row := r.db.QueryRow(ctx, `
+	row := conn(ctx, r.db).QueryRow(ctx, `
 		select id, assignment_id, group_id, student_user_id, content_type, content, status, created_at, updated_at
 		from submissions
 		where id=$1
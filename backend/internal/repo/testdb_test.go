@@ -0,0 +1,88 @@
+// internal/repo/testdb_test.go
+
+package repo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testPool: чистая схема с применёнными миграциями в базе TEST_DATABASE_URL.
+// Без переменной тесты с БД пропускаются.
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := admin.Exec(ctx, "create schema "+schema); err != nil {
+		admin.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "drop schema "+schema+" cascade")
+		admin.Close()
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ",public"
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	files, err := filepath.Glob(filepath.Join("..", "migrations", "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		if strings.HasSuffix(f, ".down.sql") {
+			continue
+		}
+		b, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pool.Exec(ctx, string(b)); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(f), err)
+		}
+	}
+	return pool
+}
+
+// testGroup: программа, поток и группа на capacity мест
+func testGroup(t *testing.T, pool *pgxpool.Pool, capacity int) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+	pid, cid, gid := uuid.New(), uuid.New(), uuid.New()
+	if _, err := pool.Exec(ctx, `insert into programs(id, title) values ($1, 'Python')`, pid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Exec(ctx, `insert into cohorts(id, program_id, year) values ($1, $2, 2026)`, cid, pid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Exec(ctx, `insert into groups(id, program_id, cohort_id, title, capacity) values ($1, $2, $3, 'Группа 1', $4)`,
+		gid, pid, cid, capacity); err != nil {
+		t.Fatal(err)
+	}
+	return gid
+}
@@ -0,0 +1,115 @@
+// internal/repo/user_repo.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+var ErrEmailTaken = errors.New("email already registered")
+
+type UserRepo struct{ db *pgxpool.Pool }
+
+func NewUserRepo(db *pgxpool.Pool) *UserRepo { return &UserRepo{db: db} }
+
+func (r *UserRepo) Create(ctx context.Context, u domain.User) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into users(id, email, password_hash, role)
+		values ($1,$2,$3,$4)
+	`, u.ID, u.Email, u.PasswordHash, u.Role)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+func (r *UserRepo) Get(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select id, email, password_hash, role, created_at, updated_at
+		from users
+		where id=$1
+	`, id)
+
+	var u domain.User
+	if err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+func (r *UserRepo) GetByEmail(ctx context.Context, email string) (domain.User, bool, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select id, email, password_hash, role, created_at, updated_at
+		from users
+		where lower(email)=lower($1)
+	`, email)
+
+	var u domain.User
+	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, false, nil
+		}
+		return domain.User{}, false, err
+	}
+	return u, true, nil
+}
+
+// -------- Refresh tokens --------
+
+func (r *UserRepo) CreateRefreshToken(ctx context.Context, t domain.RefreshToken) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into refresh_tokens(id, user_id, family_id, token_hash, expires_at)
+		values ($1,$2,$3,$4,$5)
+	`, t.ID, t.UserID, t.FamilyID, t.TokenHash, t.ExpiresAt)
+	return err
+}
+
+func (r *UserRepo) GetRefreshTokenByHash(ctx context.Context, hash string) (domain.RefreshToken, bool, error) {
+	row := conn(ctx, r.db).QueryRow(ctx, `
+		select id, user_id, family_id, token_hash, expires_at, revoked_at, created_at
+		from refresh_tokens
+		where token_hash=$1
+	`, hash)
+
+	var t domain.RefreshToken
+	err := row.Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.RevokedAt, &t.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.RefreshToken{}, false, nil
+		}
+		return domain.RefreshToken{}, false, err
+	}
+	return t, true, nil
+}
+
+// RevokeRefreshToken: true только если токен был активен (защита от двойного refresh одним токеном)
+func (r *UserRepo) RevokeRefreshToken(ctx context.Context, id uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update refresh_tokens
+		set revoked_at=now()
+		where id=$1 and revoked_at is null
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *UserRepo) RevokeRefreshFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		update refresh_tokens
+		set revoked_at=now()
+		where family_id=$1 and revoked_at is null
+	`, familyID)
+	return err
+}
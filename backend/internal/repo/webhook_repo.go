@@ -0,0 +1,270 @@
+// internal/repo/webhook_repo.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+type WebhookRepo struct{ db *pgxpool.Pool }
+
+func NewWebhookRepo(db *pgxpool.Pool) *WebhookRepo { return &WebhookRepo{db: db} }
+
+// -------- Subscriptions --------
+
+const webhookSubColumns = `id, url, event_types, secret, description, is_enabled, created_by, created_at, updated_at`
+
+func scanWebhookSub(row pgx.Row) (domain.WebhookSubscription, error) {
+	var s domain.WebhookSubscription
+	err := row.Scan(&s.ID, &s.URL, &s.EventTypes, &s.Secret, &s.Description, &s.IsEnabled, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, s domain.WebhookSubscription) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into webhook_subscriptions(id, url, event_types, secret, description, is_enabled, created_by)
+		values ($1,$2,$3,$4,$5,$6,$7)
+	`, s.ID, s.URL, s.EventTypes, s.Secret, s.Description, s.IsEnabled, s.CreatedBy)
+	return err
+}
+
+// UpdateSubscription: пустой Secret — оставить прежний
+func (r *WebhookRepo) UpdateSubscription(ctx context.Context, s domain.WebhookSubscription) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update webhook_subscriptions
+		set url=$2, event_types=$3, secret=coalesce(nullif($4,''), secret), description=$5, is_enabled=$6, updated_at=now()
+		where id=$1
+	`, s.ID, s.URL, s.EventTypes, s.Secret, s.Description, s.IsEnabled)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `delete from webhook_subscriptions where id=$1`, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *WebhookRepo) GetSubscription(ctx context.Context, id uuid.UUID) (domain.WebhookSubscription, bool, error) {
+	s, err := scanWebhookSub(conn(ctx, r.db).QueryRow(ctx, `
+		select `+webhookSubColumns+`
+		from webhook_subscriptions
+		where id=$1
+	`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WebhookSubscription{}, false, nil
+		}
+		return domain.WebhookSubscription{}, false, err
+	}
+	return s, true, nil
+}
+
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.listSubs(ctx, `
+		select `+webhookSubColumns+`
+		from webhook_subscriptions
+		order by created_at desc
+	`)
+}
+
+// ListSubscriptionsForEvent: включённые подписки, у которых фильтр пуст или содержит тип события
+func (r *WebhookRepo) ListSubscriptionsForEvent(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error) {
+	return r.listSubs(ctx, `
+		select `+webhookSubColumns+`
+		from webhook_subscriptions
+		where is_enabled and (cardinality(event_types)=0 or $1 = any(event_types))
+	`, eventType)
+}
+
+func (r *WebhookRepo) listSubs(ctx context.Context, sql string, args ...any) ([]domain.WebhookSubscription, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.WebhookSubscription, 0)
+	for rows.Next() {
+		s, err := scanWebhookSub(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+// -------- Deliveries --------
+
+// EnqueueDelivery: (subscription, event) уникальны, повтор — no-op
+func (r *WebhookRepo) EnqueueDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into webhook_deliveries(id, subscription_id, event_id, event_type, payload)
+		values ($1,$2,$3,$4,$5)
+		on conflict (subscription_id, event_id) do nothing
+	`, d.ID, d.SubscriptionID, d.EventID, d.EventType, d.Payload)
+	return err
+}
+
+// EnqueueLeased: доставка, которую отправляют сразу (тестовое событие) — next_attempt_at уже
+// сдвинут на lease, чтобы фоновый Deliverer не взял её параллельно
+func (r *WebhookRepo) EnqueueLeased(ctx context.Context, d domain.WebhookDelivery, lease time.Duration) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into webhook_deliveries(id, subscription_id, event_id, event_type, payload, next_attempt_at)
+		values ($1,$2,$3,$4,$5, now() + make_interval(secs => $6))
+	`, d.ID, d.SubscriptionID, d.EventID, d.EventType, d.Payload, lease.Seconds())
+	return err
+}
+
+// WebhookJob: доставка + куда и чем подписывать
+type WebhookJob struct {
+	Delivery domain.WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+const webhookJobSelect = `
+	select d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+	       d.last_status_code, d.created_at, d.delivered_at, s.url, s.secret
+	from webhook_deliveries d
+	join webhook_subscriptions s on s.id = d.subscription_id
+`
+
+func scanWebhookJob(row pgx.Row) (WebhookJob, error) {
+	var j WebhookJob
+	var st string
+	d := &j.Delivery
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &st, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatusCode, &d.CreatedAt, &d.DeliveredAt, &j.URL, &j.Secret)
+	d.Status = domain.WebhookDeliveryStatus(st)
+	return j, err
+}
+
+// ClaimDeliveries: как outbox.Repo.Claim — SKIP LOCKED + lease на next_attempt_at
+func (r *WebhookRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]WebhookJob, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		with c as (
+			update webhook_deliveries
+			set next_attempt_at = now() + make_interval(secs => $2)
+			where id in (
+				select d.id from webhook_deliveries d
+				join webhook_subscriptions s on s.id = d.subscription_id
+				where d.status='pending' and d.next_attempt_at <= now() and s.is_enabled
+				order by d.created_at
+				limit $1
+				for update of d skip locked
+			)
+			returning id
+		)
+		`+webhookJobSelect+`
+		where d.id in (select id from c)
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []WebhookJob
+	for rows.Next() {
+		j, err := scanWebhookJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, j)
+	}
+	return res, rows.Err()
+}
+
+func (r *WebhookRepo) GetJob(ctx context.Context, deliveryID uuid.UUID) (WebhookJob, error) {
+	return scanWebhookJob(conn(ctx, r.db).QueryRow(ctx, webhookJobSelect+`where d.id=$1`, deliveryID))
+}
+
+// RecordAttempt пишет попытку и обновляет доставку: delivered / pending с next / failed
+func (r *WebhookRepo) RecordAttempt(ctx context.Context, a domain.WebhookAttempt, status domain.WebhookDeliveryStatus, next time.Time) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into webhook_attempts(id, delivery_id, attempt_no, status_code, error, response_body, duration_ms)
+		values ($1,$2,$3,$4,$5,$6,$7)
+	`, a.ID, a.DeliveryID, a.AttemptNo, a.StatusCode, a.Error, a.ResponseBody, a.DurationMs)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).Exec(ctx, `
+		update webhook_deliveries
+		set attempts=$2, status=$3, next_attempt_at=$4, last_status_code=$5,
+		    delivered_at = case when $3='delivered' then now() else delivered_at end
+		where id=$1
+	`, a.DeliveryID, a.AttemptNo, string(status), next, a.StatusCode)
+	return err
+}
+
+// RetryDelivery: снова в очередь (в т.ч. failed), счётчик попыток сохраняется
+func (r *WebhookRepo) RetryDelivery(ctx context.Context, id uuid.UUID) (bool, error) {
+	ct, err := conn(ctx, r.db).Exec(ctx, `
+		update webhook_deliveries
+		set status='pending', next_attempt_at=now()
+		where id=$1 and status<>'delivered'
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, webhookJobSelect+`
+		where d.subscription_id=$1
+		order by d.created_at desc
+		limit $2
+	`, subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		j, err := scanWebhookJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, j.Delivery)
+	}
+	return res, rows.Err()
+}
+
+func (r *WebhookRepo) ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]domain.WebhookAttempt, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select id, delivery_id, attempt_no, status_code, error, response_body, duration_ms, created_at
+		from webhook_attempts
+		where delivery_id=$1
+		order by attempt_no
+	`, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.WebhookAttempt, 0)
+	for rows.Next() {
+		var a domain.WebhookAttempt
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.AttemptNo, &a.StatusCode, &a.Error, &a.ResponseBody, &a.DurationMs, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
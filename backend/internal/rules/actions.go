@@ -0,0 +1,144 @@
+// internal/rules/actions.go
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/service"
+)
+
+// Action выполняет действие правила. Ошибка с outbox.ErrPermanent не повторяется.
+type Action interface {
+	Execute(ctx context.Context, ru domain.Rule, e outbox.Event, payload map[string]any) error
+}
+
+// -------- notify --------
+
+// NotifyAction не шлёт сам, а ставит notification.requested в outbox — его разбирает доставка уведомлений.
+// params: template (обяз.), user_field (поле payload с получателем, по умолчанию user_id)
+type NotifyAction struct {
+	outbox *outbox.Repo
+}
+
+func NewNotifyAction(outbox *outbox.Repo) *NotifyAction { return &NotifyAction{outbox: outbox} }
+
+func (a *NotifyAction) Execute(ctx context.Context, ru domain.Rule, e outbox.Event, payload map[string]any) error {
+	field := paramString(ru.ActionParams, "user_field")
+	if field == "" {
+		field = "user_id"
+	}
+	v, _ := lookup(payload, field)
+	s, _ := v.(string)
+	userID, err := uuid.Parse(s)
+	if err != nil {
+		return fmt.Errorf("%w: payload has no user id in %q", outbox.ErrPermanent, field)
+	}
+
+	return a.outbox.Add(ctx, "user", userID, outbox.NotificationRequestedV1{
+		UserID:          userID,
+		Template:        paramString(ru.ActionParams, "template"),
+		RuleID:          ru.ID,
+		SourceEventID:   e.ID,
+		SourceEventType: e.EventType,
+		Data:            payload,
+	})
+}
+
+// -------- set_application_status --------
+
+// SetApplicationStatusAction меняет статус заявки из payload.application_id от имени роли system.
+// params: to (обяз.), reason
+type SetApplicationStatusAction struct {
+	apps *service.ApplicationService
+}
+
+func NewSetApplicationStatusAction(apps *service.ApplicationService) *SetApplicationStatusAction {
+	return &SetApplicationStatusAction{apps: apps}
+}
+
+func (a *SetApplicationStatusAction) Execute(ctx context.Context, ru domain.Rule, e outbox.Event, payload map[string]any) error {
+	s, _ := payload["application_id"].(string)
+	appID, err := uuid.Parse(s)
+	if err != nil {
+		return fmt.Errorf("%w: payload has no application_id", outbox.ErrPermanent)
+	}
+
+	reason := paramString(ru.ActionParams, "reason")
+	if reason == "" {
+		reason = "rule: " + ru.Name
+	}
+
+	ctx = auth.WithIdentity(ctx, uuid.Nil, auth.SystemRole)
+	err = a.apps.ChangeStatus(ctx, appID, domain.ApplicationStatus(paramString(ru.ActionParams, "to")), reason)
+	if err != nil && permanentStatusError(err) {
+		return fmt.Errorf("%w: %v", outbox.ErrPermanent, err)
+	}
+	return err
+}
+
+// permanentStatusError: заявка уже ушла дальше или не проходит проверки — повтор не поможет
+func permanentStatusError(err error) bool {
+	for _, target := range []error{
+		domain.ErrInvalidTransition, domain.ErrFinalStatus,
+		service.ErrNoSeats, service.ErrSeatsAvailable, service.ErrInterviewRequired, service.ErrInterviewFailed,
+		service.ErrAdmissionPending, // конкурсная группа: одобряет только Finalize
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// -------- webhook --------
+
+// WebhookAction: POST {event_id, event_type, rule_id, payload} на params.url
+type WebhookAction struct {
+	client *http.Client
+}
+
+func NewWebhookAction() *WebhookAction {
+	return &WebhookAction{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (a *WebhookAction) Execute(ctx context.Context, ru domain.Rule, e outbox.Event, payload map[string]any) error {
+	body, _ := json.Marshal(map[string]any{
+		"event_id":   e.ID.String(),
+		"event_type": e.EventType,
+		"rule_id":    ru.ID.String(),
+		"payload":    payload,
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, paramString(ru.ActionParams, "url"), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", outbox.ErrPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", e.ID.String())
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("webhook: status %d", resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: webhook status %d", outbox.ErrPermanent, resp.StatusCode)
+	}
+	return nil
+}
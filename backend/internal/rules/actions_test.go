@@ -0,0 +1,32 @@
+// internal/rules/actions_test.go
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/service"
+)
+
+func TestPermanentStatusError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{domain.ErrInvalidTransition, true},
+		{domain.ErrFinalStatus, true},
+		{service.ErrNoSeats, true},
+		{service.ErrInterviewFailed, true},
+		{service.ErrAdmissionPending, true},
+		{fmt.Errorf("approve: %w", service.ErrAdmissionPending), true},
+		// сбой БД и т.п. — outbox повторит событие
+		{errors.New("connection reset"), false},
+	} {
+		if got := permanentStatusError(tc.err); got != tc.want {
+			t.Errorf("permanentStatusError(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// internal/rules/conditions.go
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+var ErrInvalidRule = errors.New("invalid rule")
+
+// Validate: проверка правила до сохранения (ручки /admin/rules)
+func Validate(ru domain.Rule) error {
+	if strings.TrimSpace(ru.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRule)
+	}
+	if strings.TrimSpace(ru.EventType) == "" {
+		return fmt.Errorf("%w: event_type is required", ErrInvalidRule)
+	}
+	for _, c := range ru.Conditions {
+		if c.Field == "" {
+			return fmt.Errorf("%w: condition field is required", ErrInvalidRule)
+		}
+		switch c.Op {
+		case "eq", "ne", "exists":
+		case "in":
+			if _, ok := c.Value.([]any); !ok {
+				return fmt.Errorf("%w: op in expects array value", ErrInvalidRule)
+			}
+		default:
+			return fmt.Errorf("%w: unknown op %q", ErrInvalidRule, c.Op)
+		}
+	}
+
+	switch ru.Action {
+	case domain.ActionNotify:
+		if paramString(ru.ActionParams, "template") == "" {
+			return fmt.Errorf("%w: notify needs action_params.template", ErrInvalidRule)
+		}
+	case domain.ActionSetApplicationStatus:
+		switch domain.ApplicationStatus(paramString(ru.ActionParams, "to")) {
+		case domain.AppInReview, domain.AppApproved, domain.AppRejected:
+		default:
+			return fmt.Errorf("%w: set_application_status needs action_params.to (in_review|approved|rejected)", ErrInvalidRule)
+		}
+	case domain.ActionWebhook:
+		u, err := url.Parse(paramString(ru.ActionParams, "url"))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: webhook needs absolute http(s) action_params.url", ErrInvalidRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidRule, ru.Action)
+	}
+	return nil
+}
+
+// Match: все условия выполняются на payload события
+func Match(conds []domain.RuleCondition, payload map[string]any) bool {
+	for _, c := range conds {
+		v, found := lookup(payload, c.Field)
+		switch c.Op {
+		case "exists":
+			if !found {
+				return false
+			}
+		case "eq":
+			if !found || !equal(v, c.Value) {
+				return false
+			}
+		case "ne":
+			if found && equal(v, c.Value) {
+				return false
+			}
+		case "in":
+			list, _ := c.Value.([]any)
+			hit := false
+			for _, x := range list {
+				if found && equal(v, x) {
+					hit = true
+					break
+				}
+			}
+			if !hit {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// lookup: "a.b.c" по вложенным объектам
+func lookup(m map[string]any, path string) (any, bool) {
+	var cur any = m
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur, ok = obj[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// equal: payload и условия приходят из JSON, поэтому числа — float64; сравниваем через fmt
+func equal(a, b any) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func paramString(params map[string]any, key string) string {
+	s, _ := params[key].(string)
+	return s
+}
@@ -0,0 +1,86 @@
+// internal/rules/engine.go
+
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+// Engine — outbox.Sink: на каждое событие находит включённые правила и выполняет их действия.
+// Успешные срабатывания пишутся в rule_executions и при повторе события пропускаются.
+type Engine struct {
+	rules   *repo.RuleRepo
+	actions map[domain.RuleAction]Action
+}
+
+func NewEngine(rules *repo.RuleRepo, actions map[domain.RuleAction]Action) *Engine {
+	return &Engine{rules: rules, actions: actions}
+}
+
+func (en *Engine) Name() string { return "rules" }
+
+func (en *Engine) Handle(ctx context.Context, e outbox.Event) error {
+	rs, err := en.rules.ListEnabledByEvent(ctx, e.EventType)
+	if err != nil {
+		return err
+	}
+	if len(rs) == 0 {
+		return nil
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: %v", outbox.ErrPermanent, err)
+	}
+
+	// временные ошибки копим: остальные правила всё равно выполняем
+	var retry []error
+	for _, ru := range rs {
+		if !Match(ru.Conditions, payload) {
+			continue
+		}
+		done, err := en.rules.ExecutionSucceeded(ctx, ru.ID, e.ID)
+		if err != nil {
+			return err
+		}
+		if done {
+			continue
+		}
+
+		execErr := en.execute(ctx, ru, e, payload)
+
+		status := domain.RuleExecSuccess
+		var msg *string
+		if execErr != nil {
+			status = domain.RuleExecFailed
+			s := execErr.Error()
+			msg = &s
+			slog.Warn("rule failed", "rule", ru.ID, "event", e.ID, "err", execErr)
+		}
+		if err := en.rules.RecordExecution(ctx, ru.ID, e.ID, e.EventType, status, msg); err != nil {
+			return err
+		}
+
+		// постоянная ошибка остаётся только в журнале правила, событие не задерживаем
+		if execErr != nil && !errors.Is(execErr, outbox.ErrPermanent) {
+			retry = append(retry, fmt.Errorf("rule %s: %w", ru.ID, execErr))
+		}
+	}
+	return errors.Join(retry...)
+}
+
+func (en *Engine) execute(ctx context.Context, ru domain.Rule, e outbox.Event, payload map[string]any) error {
+	a, ok := en.actions[ru.Action]
+	if !ok {
+		return fmt.Errorf("%w: no handler for action %q", outbox.ErrPermanent, ru.Action)
+	}
+	return a.Execute(ctx, ru, e, payload)
+}
@@ -0,0 +1,320 @@
+// internal/service/admission_service.go
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+var (
+	ErrNotCompetitive     = errors.New("group does not use competitive admission")
+	ErrAdmissionFinalized = errors.New("admission is already finalized")
+	ErrInterviewsPending  = errors.New("some candidates have no final interview result")
+	ErrInvalidRest        = errors.New("rest must be waitlisted or rejected")
+	ErrInvalidPoints      = errors.New("invalid points")
+)
+
+// причина в аудите, если staff её не указал
+const reasonAdmissionFinalized = "admission finalized"
+
+// AdmissionService: конкурсный приём в группу.
+// Заявки in_review/waitlisted ранжируются по баллам (собеседование + анкета + ручные баллы staff),
+// Finalize одобряет первых по числу свободных мест, остальных — в лист ожидания или отказ.
+type AdmissionService struct {
+	apps      *repo.ApplicationRepo
+	catalog   *repo.CatalogRepo
+	admission *repo.AdmissionRepo
+	outbox    *outbox.Repo
+	tx        *db.TxManager
+	az        *authz.Authorizer
+}
+
+func NewAdmissionService(apps *repo.ApplicationRepo, catalog *repo.CatalogRepo, admission *repo.AdmissionRepo, outboxRepo *outbox.Repo, tx *db.TxManager, az *authz.Authorizer) *AdmissionService {
+	return &AdmissionService{apps: apps, catalog: catalog, admission: admission, outbox: outboxRepo, tx: tx, az: az}
+}
+
+type AdmissionRanking struct {
+	GroupID     uuid.UUID
+	Mode        domain.AdmissionMode
+	Capacity    int
+	Enrolled    int
+	Seats       int // свободные места: столько заявок одобрит Finalize
+	Unreviewed  int // заявки submitted — в рейтинг не попадают, пока их не взяли в работу
+	FinalizedAt *time.Time
+	Items       []domain.RankedApplication
+}
+
+type AdmissionResult struct {
+	GroupID    uuid.UUID
+	Seats      int
+	Approved   int
+	Waitlisted int
+	Rejected   int
+	Items      []domain.RankedApplication // Status — после подведения
+}
+
+func (s *AdmissionService) Get(ctx context.Context, groupID uuid.UUID) (domain.GroupAdmission, error) {
+	return s.admission.Get(ctx, groupID)
+}
+
+// Update: режим приёма и правила баллов; после подведения конкурса не меняются
+func (s *AdmissionService) Update(ctx context.Context, groupID uuid.UUID, mode domain.AdmissionMode, rules domain.ScoringRules) error {
+	if !mode.Valid() {
+		return fmt.Errorf("%w: unknown mode %q", domain.ErrInvalidScoring, mode)
+	}
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	ok, err := s.admission.Set(ctx, groupID, mode, rules)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// группы нет (pgx.ErrNoRows) или конкурс подведён
+		if _, err := s.admission.Get(ctx, groupID); err != nil {
+			return err
+		}
+		return ErrAdmissionFinalized
+	}
+	return nil
+}
+
+// SetScore: ручные баллы заявки (модератор/админ), пока конкурс не подведён
+func (s *AdmissionService) SetScore(ctx context.Context, appID uuid.UUID, points float64, comment string) (domain.ApplicationScore, error) {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return domain.ApplicationScore{}, errors.New("unauthorized")
+	}
+	if math.IsNaN(points) || math.IsInf(points, 0) {
+		return domain.ApplicationScore{}, ErrInvalidPoints
+	}
+	app, err := s.apps.Get(ctx, appID)
+	if err != nil {
+		return domain.ApplicationScore{}, err
+	}
+	if err := s.az.RequireInGroup(ctx, authz.ApplicationReview, app.GroupID); err != nil {
+		return domain.ApplicationScore{}, err
+	}
+	if app.Status.IsFinal() {
+		return domain.ApplicationScore{}, domain.ErrFinalStatus
+	}
+	adm, err := s.admission.Get(ctx, app.GroupID)
+	if err != nil {
+		return domain.ApplicationScore{}, err
+	}
+	if err := checkCompetitive(adm); err != nil {
+		return domain.ApplicationScore{}, err
+	}
+
+	return s.admission.UpsertScore(ctx, domain.ApplicationScore{
+		ApplicationID: appID,
+		ManualPoints:  points,
+		Comment:       strings.TrimSpace(comment),
+		UpdatedBy:     actorID,
+	})
+}
+
+// Ranking: текущий рейтинг группы (staff или преподаватель группы)
+func (s *AdmissionService) Ranking(ctx context.Context, groupID uuid.UUID) (AdmissionRanking, error) {
+	if err := s.az.RequireInGroup(ctx, authz.ApplicationList, groupID); err != nil {
+		return AdmissionRanking{}, err
+	}
+	adm, err := s.admission.Get(ctx, groupID)
+	if err != nil {
+		return AdmissionRanking{}, err
+	}
+	capacity, err := s.apps.GroupCapacity(ctx, groupID)
+	if err != nil {
+		return AdmissionRanking{}, err
+	}
+	enrolled, err := s.apps.CountEnrollmentsByGroup(ctx, groupID)
+	if err != nil {
+		return AdmissionRanking{}, err
+	}
+	unreviewed, err := s.admission.CountUnreviewed(ctx, groupID)
+	if err != nil {
+		return AdmissionRanking{}, err
+	}
+	items, err := s.rank(ctx, adm, false)
+	if err != nil {
+		return AdmissionRanking{}, err
+	}
+	return AdmissionRanking{
+		GroupID:     groupID,
+		Mode:        adm.Mode,
+		Capacity:    capacity,
+		Enrolled:    enrolled,
+		Seats:       max(capacity-enrolled, 0),
+		Unreviewed:  unreviewed,
+		FinalizedAt: adm.FinalizedAt,
+		Items:       items,
+	}, nil
+}
+
+// rank: кандидаты группы с баллами, причинами недопуска и местами
+func (s *AdmissionService) rank(ctx context.Context, adm domain.GroupAdmission, forUpdate bool) ([]domain.RankedApplication, error) {
+	requiresInterview, err := s.catalog.GroupRequiresInterview(ctx, adm.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.admission.ListCandidates(ctx, adm.GroupID, forUpdate)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		adm.Scoring.Score(&items[i])
+		items[i].Blocker = domain.InterviewBlocker(requiresInterview, items[i].InterviewResult)
+	}
+	domain.RankApplications(items)
+	return items, nil
+}
+
+// Finalize: одной транзакцией одобряет первые места рейтинга по свободным местам,
+// остальных переводит в rest (waitlisted — очередь по месту в рейтинге, или rejected);
+// не прошедшие собеседование отклоняются. Каждая смена статуса — с аудитом и событием.
+func (s *AdmissionService) Finalize(ctx context.Context, groupID uuid.UUID, rest domain.ApplicationStatus, reason string) (AdmissionResult, error) {
+	actorRole := auth.Role(ctx)
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return AdmissionResult{}, errors.New("unauthorized")
+	}
+	if err := authz.Require(ctx, authz.ApplicationReview); err != nil {
+		return AdmissionResult{}, err
+	}
+	if rest != domain.AppWaitlisted && rest != domain.AppRejected {
+		return AdmissionResult{}, ErrInvalidRest
+	}
+	if strings.TrimSpace(reason) == "" {
+		reason = reasonAdmissionFinalized
+	}
+
+	var res AdmissionResult
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		res = AdmissionResult{GroupID: groupID}
+
+		// блокировка группы: параллельные одобрения и второй Finalize ждут
+		capacity, err := s.apps.LockGroupCapacity(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		adm, err := s.admission.Get(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if err := checkCompetitive(adm); err != nil {
+			return err
+		}
+		enrolled, err := s.apps.CountEnrollmentsByGroup(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		items, err := s.rank(ctx, adm, true)
+		if err != nil {
+			return err
+		}
+		for _, it := range items {
+			if it.Blocker == domain.BlockerInterviewRequired {
+				return ErrInterviewsPending
+			}
+		}
+		res.Seats = max(capacity-enrolled, 0)
+
+		for i := range items {
+			it := &items[i]
+			to := rest
+			switch {
+			case it.Blocker != "":
+				to = domain.AppRejected
+			case it.Rank <= res.Seats:
+				to = domain.AppApproved
+			}
+			if it.Rank > 0 {
+				if err := s.admission.SetRank(ctx, it.ApplicationID, it.Rank); err != nil {
+					return err
+				}
+			}
+			if to != it.Status {
+				if err := s.apply(ctx, groupID, *it, to, reason, actorID, actorRole); err != nil {
+					return err
+				}
+				it.Status = to
+			}
+			switch to {
+			case domain.AppApproved:
+				res.Approved++
+			case domain.AppWaitlisted:
+				res.Waitlisted++
+			case domain.AppRejected:
+				res.Rejected++
+			}
+		}
+		res.Items = items
+
+		if err := s.admission.MarkFinalized(ctx, groupID); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, "group", groupID, outbox.AdmissionFinalizedV1{
+			GroupID:    groupID,
+			Seats:      res.Seats,
+			Approved:   res.Approved,
+			Waitlisted: res.Waitlisted,
+			Rejected:   res.Rejected,
+			ActorRole:  actorRole,
+		})
+	})
+	if err != nil {
+		return AdmissionResult{}, err
+	}
+	return res, nil
+}
+
+// apply: смена статуса одной заявки внутри Finalize (группа и заявки уже заблокированы)
+func (s *AdmissionService) apply(ctx context.Context, groupID uuid.UUID, it domain.RankedApplication, to domain.ApplicationStatus, reason string, actorID uuid.UUID, actorRole string) error {
+	if err := domain.CanTransition(it.Status, to, actorRole); err != nil {
+		return err
+	}
+	if err := s.apps.UpdateStatus(ctx, it.ApplicationID, to); err != nil {
+		return err
+	}
+	if err := s.apps.InsertAudit(ctx, it.ApplicationID, actorID, actorRole, it.Status, to, reason); err != nil {
+		return err
+	}
+	if to == domain.AppApproved {
+		if err := s.apps.CreateEnrollment(ctx, it.UserID, groupID, actorID, actorRole, reason); err != nil {
+			return err
+		}
+	}
+	return s.outbox.Add(ctx, "enrollment_application", it.ApplicationID, outbox.ApplicationStatusChangedV1{
+		ApplicationID: it.ApplicationID,
+		UserID:        it.UserID,
+		GroupID:       groupID,
+		From:          string(it.Status),
+		To:            string(to),
+		ActorRole:     actorRole,
+		Reason:        reason,
+	})
+}
+
+func checkCompetitive(adm domain.GroupAdmission) error {
+	if adm.Mode != domain.AdmissionCompetitive {
+		return ErrNotCompetitive
+	}
+	if adm.FinalizedAt != nil {
+		return ErrAdmissionFinalized
+	}
+	return nil
+}
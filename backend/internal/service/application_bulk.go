@@ -0,0 +1,225 @@
+// internal/service/application_bulk.go
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+// сколько заявок можно обработать одним запросом
+const BulkStatusLimit = 500
+
+var (
+	ErrBulkEmpty    = errors.New("no applications selected")
+	ErrBulkTooLarge = errors.New("too many applications selected")
+)
+
+type BulkMode string
+
+const (
+	BulkAllOrNothing BulkMode = "all_or_nothing" // одна транзакция: любая ошибка откатывает всё
+	BulkBestEffort   BulkMode = "best_effort"    // каждая заявка отдельно, ошибки не мешают остальным
+)
+
+// BulkStatusFilter: те же фильтры, что у списка заявок (пустой фильтр не допускается)
+type BulkStatusFilter struct {
+	ProgramID *uuid.UUID
+	Status    *string
+	Year      *int
+}
+
+func (f BulkStatusFilter) IsEmpty() bool {
+	return f.ProgramID == nil && f.Status == nil && f.Year == nil
+}
+
+// BulkStatusItem: результат по заявке.
+// Result: changed — статус сменён, failed — ошибка (Code/Error), rolled_back — прошла бы, но откатилась из-за других.
+type BulkStatusItem struct {
+	ApplicationID uuid.UUID
+	Result        string
+	Code          string // invalid_transition|final_status|no_seats|seats_available|interview_required|interview_failed|admission_pending|not_found
+	Error         string
+}
+
+type BulkStatusReport struct {
+	Mode    BulkMode
+	To      domain.ApplicationStatus
+	Applied bool // false — в all_or_nothing были ошибки и ничего не изменилось
+	Total   int
+	Changed int
+	Failed  int
+	Items   []BulkStatusItem
+}
+
+// errRollbackBulk: откат транзакции all_or_nothing (наружу не уходит)
+var errRollbackBulk = errors.New("bulk status rollback")
+
+// BulkChangeStatus: смена статуса списку заявок с теми же проверками, что и по одной
+// (CanTransition, собеседование, места). best_effort обрабатывает заявки в переданном порядке,
+// all_or_nothing — по возрастанию id (порядок блокировок); отчёт всегда в порядке запроса.
+func (s *ApplicationService) BulkChangeStatus(ctx context.Context, ids []uuid.UUID, to domain.ApplicationStatus, reason string, mode BulkMode) (BulkStatusReport, error) {
+	actorRole := auth.Role(ctx)
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return BulkStatusReport{}, errors.New("unauthorized")
+	}
+	if err := authz.Require(ctx, authz.ApplicationReview); err != nil {
+		return BulkStatusReport{}, err
+	}
+
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return BulkStatusReport{}, ErrBulkEmpty
+	}
+	if len(ids) > BulkStatusLimit {
+		return BulkStatusReport{}, ErrBulkTooLarge
+	}
+
+	rep := BulkStatusReport{Mode: mode, To: to, Total: len(ids), Items: make([]BulkStatusItem, len(ids))}
+
+	if mode == BulkBestEffort {
+		for i, id := range ids {
+			err := s.changeStatus(ctx, id, to, reason, actorID, actorRole)
+			rep.Items[i] = bulkItem(id, err)
+		}
+		rep.count()
+		rep.Applied = rep.Changed > 0
+		return rep, nil
+	}
+
+	pos := make(map[uuid.UUID]int, len(ids))
+	for i, id := range ids {
+		pos[id] = i
+	}
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		// все блокировки транзакции берутся в одном порядке: группы, затем заявки — по возрастанию id.
+		// Иначе две встречные массовые операции взаимоблокируются (как встречные переводы в Transfer).
+		if to == domain.AppApproved || to == domain.AppWaitlisted {
+			groupIDs, err := s.appRepo.GroupIDsOf(ctx, ids)
+			if err != nil {
+				return err
+			}
+			for _, gid := range sortedIDs(groupIDs) {
+				if _, err := s.appRepo.LockGroupCapacity(ctx, gid); err != nil {
+					return err
+				}
+			}
+		}
+		for _, id := range sortedIDs(ids) {
+			i := pos[id]
+			err := s.changeStatus(ctx, id, to, reason, actorID, actorRole)
+			if err != nil && bulkErrorCode(err) == "" {
+				// ошибка БД: транзакция уже не годится, дальше не идём
+				return err
+			}
+			rep.Items[i] = bulkItem(id, err)
+		}
+		rep.count()
+		if rep.Failed > 0 {
+			return errRollbackBulk
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errRollbackBulk):
+		for i := range rep.Items {
+			if rep.Items[i].Result == "changed" {
+				rep.Items[i].Result = "rolled_back"
+			}
+		}
+		rep.Changed = 0
+		return rep, nil
+	case err != nil:
+		return BulkStatusReport{}, err
+	}
+	rep.Applied = true
+	return rep, nil
+}
+
+// BulkIDsByFilter: заявки по фильтру списка (не больше BulkStatusLimit)
+func (s *ApplicationService) BulkIDsByFilter(ctx context.Context, f BulkStatusFilter) ([]uuid.UUID, error) {
+	if err := authz.Require(ctx, authz.ApplicationReview); err != nil {
+		return nil, err
+	}
+	if f.IsEmpty() {
+		return nil, ErrBulkEmpty
+	}
+	ids, err := s.appRepo.ListIDsByFilter(ctx, f.ProgramID, f.Status, f.Year, BulkStatusLimit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > BulkStatusLimit {
+		return nil, ErrBulkTooLarge
+	}
+	return ids, nil
+}
+
+func (r *BulkStatusReport) count() {
+	r.Changed, r.Failed = 0, 0
+	for _, it := range r.Items {
+		if it.Result == "changed" {
+			r.Changed++
+		} else {
+			r.Failed++
+		}
+	}
+}
+
+func bulkItem(id uuid.UUID, err error) BulkStatusItem {
+	if err == nil {
+		return BulkStatusItem{ApplicationID: id, Result: "changed"}
+	}
+	return BulkStatusItem{ApplicationID: id, Result: "failed", Code: bulkErrorCode(err), Error: err.Error()}
+}
+
+// bulkErrorCode: код для отчёта; "" — не бизнес-ошибка
+func bulkErrorCode(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return "invalid_transition"
+	case errors.Is(err, domain.ErrFinalStatus):
+		return "final_status"
+	case errors.Is(err, ErrNoSeats):
+		return "no_seats"
+	case errors.Is(err, ErrSeatsAvailable):
+		return "seats_available"
+	case errors.Is(err, ErrInterviewRequired):
+		return "interview_required"
+	case errors.Is(err, ErrInterviewFailed):
+		return "interview_failed"
+	case errors.Is(err, ErrAdmissionPending):
+		return "admission_pending"
+	case errors.Is(err, pgx.ErrNoRows):
+		return "not_found"
+	default:
+		return ""
+	}
+}
+
+// sortedIDs: копия по возрастанию — тот же порядок, что в Transfer (сравнение строк)
+func sortedIDs(ids []uuid.UUID) []uuid.UUID {
+	res := append([]uuid.UUID(nil), ids...)
+	sort.Slice(res, func(i, j int) bool { return res[i].String() < res[j].String() })
+	return res
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	res := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+	return res
+}
@@ -0,0 +1,63 @@
+// internal/service/application_bulk_test.go
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+)
+
+func TestSortedIDsIsStableLockOrder(t *testing.T) {
+	a := uuid.MustParse("0a000000-0000-0000-0000-000000000000")
+	b := uuid.MustParse("b0000000-0000-0000-0000-000000000000")
+	c := uuid.MustParse("f0000000-0000-0000-0000-000000000000")
+
+	// две массовые операции с одними заявками в разном порядке блокируют их одинаково
+	in1 := []uuid.UUID{c, a, b}
+	in2 := []uuid.UUID{b, c, a}
+	got1, got2 := sortedIDs(in1), sortedIDs(in2)
+	for i, want := range []uuid.UUID{a, b, c} {
+		if got1[i] != want || got2[i] != want {
+			t.Fatalf("sortedIDs = %v / %v, want %v", got1, got2, []uuid.UUID{a, b, c})
+		}
+	}
+	if in1[0] != c {
+		t.Error("sortedIDs modified its argument: the report must keep request order")
+	}
+}
+
+func TestUniqueIDsKeepsFirstOccurrence(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	got := uniqueIDs([]uuid.UUID{b, a, b, a})
+	if len(got) != 2 || got[0] != b || got[1] != a {
+		t.Errorf("uniqueIDs = %v, want [%s %s]", got, b, a)
+	}
+}
+
+func TestBulkErrorCode(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{domain.ErrInvalidTransition, "invalid_transition"},
+		{domain.ErrFinalStatus, "final_status"},
+		{ErrNoSeats, "no_seats"},
+		{ErrSeatsAvailable, "seats_available"},
+		{ErrInterviewRequired, "interview_required"},
+		{fmt.Errorf("check: %w", ErrInterviewFailed), "interview_failed"},
+		{ErrAdmissionPending, "admission_pending"},
+		{pgx.ErrNoRows, "not_found"},
+		// не бизнес-ошибка: all_or_nothing прерывает транзакцию
+		{errors.New("conn reset"), ""},
+	} {
+		if got := bulkErrorCode(tc.err); got != tc.want {
+			t.Errorf("bulkErrorCode(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// internal/service/application_message_service.go
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+var ErrEmptyMessage = errors.New("message is empty")
+
+// ApplicationMessageService: переписка по заявке.
+// Сторона заявителя — сам заявитель или его guardian (как Cancel/WaitlistPosition),
+// сторона staff — модератор/админ или преподаватель группы заявки (как список заявок).
+type ApplicationMessageService struct {
+	appRepo   *repo.ApplicationRepo
+	messages  *repo.ApplicationMessageRepo
+	guardians *repo.GuardianRepo
+	outbox    *outbox.Repo
+	tx        *db.TxManager
+	az        *authz.Authorizer
+}
+
+func NewApplicationMessageService(appRepo *repo.ApplicationRepo, messages *repo.ApplicationMessageRepo, guardians *repo.GuardianRepo, outboxRepo *outbox.Repo, tx *db.TxManager, az *authz.Authorizer) *ApplicationMessageService {
+	return &ApplicationMessageService{appRepo: appRepo, messages: messages, guardians: guardians, outbox: outboxRepo, tx: tx, az: az}
+}
+
+// ListForApplicant: переписка без внутренних заметок
+func (s *ApplicationMessageService) ListForApplicant(ctx context.Context, appID uuid.UUID) ([]domain.ApplicationMessage, error) {
+	if _, _, err := applicantSide(ctx, s.appRepo, s.guardians, appID); err != nil {
+		return nil, err
+	}
+	return s.messages.List(ctx, appID, false)
+}
+
+func (s *ApplicationMessageService) PostAsApplicant(ctx context.Context, appID uuid.UUID, body string) (domain.ApplicationMessage, error) {
+	app, actorID, err := applicantSide(ctx, s.appRepo, s.guardians, appID)
+	if err != nil {
+		return domain.ApplicationMessage{}, err
+	}
+	return s.post(ctx, app, domain.ApplicationMessage{
+		AuthorUserID:  actorID,
+		AuthorRole:    actorRoleFor(ctx, actorID, app.UserID),
+		FromApplicant: true,
+		Body:          body,
+	})
+}
+
+// ListForStaff: вся переписка, включая внутренние заметки
+func (s *ApplicationMessageService) ListForStaff(ctx context.Context, appID uuid.UUID) ([]domain.ApplicationMessage, error) {
+	if _, _, err := staffSide(ctx, s.appRepo, s.az, appID); err != nil {
+		return nil, err
+	}
+	return s.messages.List(ctx, appID, true)
+}
+
+func (s *ApplicationMessageService) PostAsStaff(ctx context.Context, appID uuid.UUID, body string, internal bool) (domain.ApplicationMessage, error) {
+	app, actorID, err := staffSide(ctx, s.appRepo, s.az, appID)
+	if err != nil {
+		return domain.ApplicationMessage{}, err
+	}
+	role := auth.Role(ctx)
+	if !authz.Can(ctx, authz.ApplicationList) {
+		// доступ через назначение в группу
+		role = "teacher"
+	}
+	return s.post(ctx, app, domain.ApplicationMessage{
+		AuthorUserID: actorID,
+		AuthorRole:   role,
+		Body:         body,
+		Internal:     internal,
+	})
+}
+
+// post: сообщение + outbox-событие одной транзакцией
+func (s *ApplicationMessageService) post(ctx context.Context, app domain.EnrollmentApplication, m domain.ApplicationMessage) (domain.ApplicationMessage, error) {
+	m.Body = strings.TrimSpace(m.Body)
+	if m.Body == "" {
+		return domain.ApplicationMessage{}, ErrEmptyMessage
+	}
+	m.ID = uuid.New()
+	m.ApplicationID = app.ID
+
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if m, err = s.messages.Create(ctx, m); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, "enrollment_application", app.ID, outbox.ApplicationMessagePostedV1{
+			MessageID:     m.ID,
+			ApplicationID: app.ID,
+			UserID:        app.UserID,
+			GroupID:       app.GroupID,
+			AuthorID:      m.AuthorUserID,
+			AuthorRole:    m.AuthorRole,
+			FromApplicant: m.FromApplicant,
+			Internal:      m.Internal,
+			Body:          m.Body,
+		})
+	})
+	if err != nil {
+		return domain.ApplicationMessage{}, err
+	}
+	return m, nil
+}
@@ -4,11 +4,14 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/google/uuid"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
 	"github.com/Pavlushechko/itcube-education/internal/domain"
 	"github.com/Pavlushechko/itcube-education/internal/outbox"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
@@ -16,28 +19,114 @@ import (
 
 var (
 	ErrNoSeats           = errors.New("group is full")
+	ErrSeatsAvailable    = errors.New("group has free seats: approve instead of waitlisting")
 	ErrProgramNotVisible = errors.New("program is not published")
 	ErrGroupClosed       = errors.New("group is closed for applications")
 	ErrInterviewRequired = errors.New("interview result is required before approval")
 	ErrInterviewFailed   = errors.New("interview is not recommended")
+	ErrNotGuardian       = errors.New("not a guardian of this learner")
+	ErrNotWaitlisted     = errors.New("application is not waitlisted")
+	ErrAdmissionPending  = errors.New("group uses competitive admission: approve via finalize")
 )
 
+// причина в аудите при автоматическом зачислении из листа ожидания
+const reasonWaitlistPromotion = "promoted from waitlist"
+
 type ApplicationService struct {
 	appRepo     *repo.ApplicationRepo
 	catalogRepo *repo.CatalogRepo
 	interviews  *repo.InterviewRepo
+	guardians   *repo.GuardianRepo
+	forms       *repo.FormRepo
+	eligibility *EligibilityService
 	outbox      *outbox.Repo
+	tx          *db.TxManager
 }
 
-func NewApplicationService(appRepo *repo.ApplicationRepo, catalogRepo *repo.CatalogRepo, interviewRepo *repo.InterviewRepo, outboxRepo *outbox.Repo) *ApplicationService {
-	return &ApplicationService{appRepo: appRepo, catalogRepo: catalogRepo, interviews: interviewRepo, outbox: outboxRepo}
+func NewApplicationService(appRepo *repo.ApplicationRepo, catalogRepo *repo.CatalogRepo, interviewRepo *repo.InterviewRepo, guardianRepo *repo.GuardianRepo, formRepo *repo.FormRepo, eligibility *EligibilityService, outboxRepo *outbox.Repo, tx *db.TxManager) *ApplicationService {
+	return &ApplicationService{appRepo: appRepo, catalogRepo: catalogRepo, interviews: interviewRepo, guardians: guardianRepo, forms: formRepo, eligibility: eligibility, outbox: outboxRepo, tx: tx}
 }
 
-func (s *ApplicationService) Create(ctx context.Context, groupID uuid.UUID, comment string) (uuid.UUID, error) {
+// Create: заявка от своего имени
+func (s *ApplicationService) Create(ctx context.Context, groupID uuid.UUID, comment string, answers map[string]any) (uuid.UUID, error) {
 	userID, ok := auth.UserID(ctx)
 	if !ok {
 		return uuid.Nil, errors.New("unauthorized")
 	}
+	return s.create(ctx, userID, userID, groupID, comment, answers)
+}
+
+// CreateForChild: guardian подаёт заявку за привязанного ребёнка
+func (s *ApplicationService) CreateForChild(ctx context.Context, childID, groupID uuid.UUID, comment string, answers map[string]any) (uuid.UUID, error) {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return uuid.Nil, errors.New("unauthorized")
+	}
+	if err := s.ensureGuardian(ctx, actorID, childID); err != nil {
+		return uuid.Nil, err
+	}
+	return s.create(ctx, childID, actorID, groupID, comment, answers)
+}
+
+func (s *ApplicationService) ensureGuardian(ctx context.Context, guardianID, childID uuid.UUID) error {
+	ok, err := s.guardians.IsGuardianOf(ctx, guardianID, childID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotGuardian
+	}
+	return nil
+}
+
+// actorRoleFor: в аудите guardian отличаем от самого заявителя
+func actorRoleFor(ctx context.Context, actorID, applicantID uuid.UUID) string {
+	if actorID != applicantID {
+		return "guardian"
+	}
+	return auth.Role(ctx)
+}
+
+// applicantSide: заявка, если текущий пользователь — заявитель или его guardian
+// (сторона заявителя в переписке, вложениях, записи на собеседование)
+func applicantSide(ctx context.Context, apps *repo.ApplicationRepo, guardians *repo.GuardianRepo, appID uuid.UUID) (domain.EnrollmentApplication, uuid.UUID, error) {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return domain.EnrollmentApplication{}, uuid.Nil, errors.New("unauthorized")
+	}
+	app, err := apps.Get(ctx, appID)
+	if err != nil {
+		return domain.EnrollmentApplication{}, uuid.Nil, err
+	}
+	if app.UserID != actorID {
+		ok, err := guardians.IsGuardianOf(ctx, actorID, app.UserID)
+		if err != nil {
+			return domain.EnrollmentApplication{}, uuid.Nil, err
+		}
+		if !ok {
+			return domain.EnrollmentApplication{}, uuid.Nil, ErrNotGuardian
+		}
+	}
+	return app, actorID, nil
+}
+
+// staffSide: заявка, если текущий пользователь видит заявки её группы (staff или преподаватель группы)
+func staffSide(ctx context.Context, apps *repo.ApplicationRepo, az *authz.Authorizer, appID uuid.UUID) (domain.EnrollmentApplication, uuid.UUID, error) {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return domain.EnrollmentApplication{}, uuid.Nil, authz.ErrUnauthorized
+	}
+	app, err := apps.Get(ctx, appID)
+	if err != nil {
+		return domain.EnrollmentApplication{}, uuid.Nil, err
+	}
+	if err := az.RequireInGroup(ctx, authz.ApplicationList, app.GroupID); err != nil {
+		return domain.EnrollmentApplication{}, uuid.Nil, err
+	}
+	return app, actorID, nil
+}
+
+func (s *ApplicationService) create(ctx context.Context, userID, actorID, groupID uuid.UUID, comment string, answers map[string]any) (uuid.UUID, error) {
 
 	// ✅ запрет: учитель не может подавать заявку на СВОЙ курс
 	pid, err := s.catalogRepo.GetGroupProgramID(ctx, groupID)
@@ -72,7 +161,7 @@ func (s *ApplicationService) Create(ctx context.Context, groupID uuid.UUID, comm
 			// ok, разрешаем создать новую
 		case domain.AppRejected:
 			return uuid.Nil, errors.New("cannot reapply to this group after rejection")
-		case domain.AppSubmitted, domain.AppInReview, domain.AppApproved:
+		case domain.AppSubmitted, domain.AppInReview, domain.AppWaitlisted, domain.AppApproved:
 			return uuid.Nil, errors.New("application already exists")
 		default:
 			// на всякий случай: не плодим дублей
@@ -91,26 +180,70 @@ func (s *ApplicationService) Create(ctx context.Context, groupID uuid.UUID, comm
 		return uuid.Nil, ErrGroupClosed
 	}
 
-	app := domain.EnrollmentApplication{
-		ID:      uuid.New(),
-		UserID:  userID,
-		GroupID: groupID,
-		Status:  domain.AppSubmitted,
-		Comment: comment,
+	// возраст, пройденные программы, лимит зачислений — причины отдаются клиенту
+	reasons, err := s.eligibility.Check(ctx, userID, groupID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(reasons) > 0 {
+		return uuid.Nil, &domain.EligibilityError{Reasons: reasons}
 	}
-	if err := s.appRepo.Create(ctx, app); err != nil {
+
+	answersJSON, err := s.checkAnswers(ctx, groupID, answers)
+	if err != nil {
 		return uuid.Nil, err
 	}
 
-	_ = s.outbox.Add(ctx, "enrollment_application", app.ID, "application.created", map[string]any{
-		"application_id": app.ID.String(),
-		"user_id":        userID.String(),
-		"group_id":       groupID.String(),
-	})
+	app := domain.EnrollmentApplication{
+		ID:          uuid.New(),
+		UserID:      userID,
+		GroupID:     groupID,
+		Status:      domain.AppSubmitted,
+		Comment:     comment,
+		SubmittedBy: actorID,
+		Answers:     answersJSON,
+	}
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.appRepo.Create(ctx, app); err != nil {
+			return err
+		}
+
+		// аудит подачи: from="" -> submitted (видно, если подал guardian)
+		if err := s.appRepo.InsertAudit(ctx, app.ID, actorID, actorRoleFor(ctx, actorID, userID), "", domain.AppSubmitted, ""); err != nil {
+			return err
+		}
 
+		return s.outbox.Add(ctx, "enrollment_application", app.ID, outbox.ApplicationCreatedV1{
+			ApplicationID: app.ID,
+			UserID:        userID,
+			GroupID:       groupID,
+			SubmittedBy:   actorID,
+		})
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
 	return app.ID, nil
 }
 
+// checkAnswers: ответы проверяются по анкете группы (или программы); без анкеты ответов быть не должно
+func (s *ApplicationService) checkAnswers(ctx context.Context, groupID uuid.UUID, answers map[string]any) ([]byte, error) {
+	form, ok, err := s.forms.GetEffective(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		form = domain.ApplicationForm{}
+	}
+	norm, err := form.ValidateAnswers(answers)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(norm)
+}
+
+// ChangeStatus: статус + аудит + зачисление + outbox-событие — одной транзакцией.
+// При одобрении строка группы блокируется, поэтому параллельные одобрения не переполнят группу.
 func (s *ApplicationService) ChangeStatus(ctx context.Context, appID uuid.UUID, to domain.ApplicationStatus, reason string) error {
 	actorRole := auth.Role(ctx)
 
@@ -119,103 +252,316 @@ func (s *ApplicationService) ChangeStatus(ctx context.Context, appID uuid.UUID,
 		return errors.New("unauthorized")
 	}
 
-	app, err := s.appRepo.Get(ctx, appID)
-	if err != nil {
+	// RBAC на уровне сервиса (пользователь отменяет свою заявку через Cancel)
+	if err := authz.Require(ctx, authz.ApplicationReview); err != nil {
 		return err
 	}
+	return s.changeStatus(ctx, appID, to, reason, actorID, actorRole)
+}
 
-	// RBAC на уровне сервиса:
-	if actorRole == "user" {
-		if app.UserID != actorID {
-			return errors.New("forbidden")
-		}
-	}
-
-	if err := domain.CanTransition(app.Status, to, actorRole); err != nil {
+// changeStatus: смена статуса без проверки прав (ChangeStatus, BulkChangeStatus).
+// Внутри внешней транзакции (tx.Do) выполняется в ней же.
+func (s *ApplicationService) changeStatus(ctx context.Context, appID uuid.UUID, to domain.ApplicationStatus, reason string, actorID uuid.UUID, actorRole string) error {
+	// группа нужна до блокировок: порядок всегда group -> application
+	cur, err := s.appRepo.Get(ctx, appID)
+	if err != nil {
 		return err
 	}
 
-	// Если одобряем — проверяем места
-	if to == domain.AppApproved {
-		cap, err := s.appRepo.GroupCapacity(ctx, app.GroupID)
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		var cap int
+		if to == domain.AppApproved || to == domain.AppWaitlisted {
+			c, err := s.appRepo.LockGroupCapacity(ctx, cur.GroupID)
+			if err != nil {
+				return err
+			}
+			cap = c
+		}
+
+		// перечитываем под блокировкой: статус мог смениться параллельно
+		app, err := s.appRepo.GetForUpdate(ctx, appID)
 		if err != nil {
 			return err
 		}
 
-		req, err := s.catalogRepo.GroupRequiresInterview(ctx, app.GroupID)
-		if err != nil {
+		if err := domain.CanTransition(app.Status, to, actorRole); err != nil {
 			return err
 		}
-		if req {
-			inv, ok, err := s.interviews.GetByApplication(ctx, appID)
+
+		// в лист ожидания — только те, кого можно одобрить, кроме мест:
+		// из него зачисляют автоматически
+		if to == domain.AppApproved || to == domain.AppWaitlisted {
+			if err := s.checkInterview(ctx, app); err != nil {
+				return err
+			}
+			// конкурс: одобрение и лист ожидания — только по итогам рейтинга
+			pending, err := s.catalogRepo.IsAdmissionPending(ctx, app.GroupID)
 			if err != nil {
 				return err
 			}
-			if !ok {
-				return ErrInterviewRequired
+			if pending {
+				return ErrAdmissionPending
 			}
-			if inv.Result != domain.InterviewRecommended {
-				return ErrInterviewFailed
+		}
+
+		// Если одобряем — проверяем места; в лист ожидания — только когда мест нет
+		// (из очереди зачисляют, лишь когда место освобождается)
+		if to == domain.AppApproved || to == domain.AppWaitlisted {
+			cnt, err := s.appRepo.CountEnrollmentsByGroup(ctx, app.GroupID)
+			if err != nil {
+				return err
+			}
+			if to == domain.AppApproved && cnt >= cap {
+				return ErrNoSeats
+			}
+			if to == domain.AppWaitlisted && cnt < cap {
+				return ErrSeatsAvailable
 			}
 		}
 
-		cnt, err := s.appRepo.CountEnrollmentsByGroup(ctx, app.GroupID)
-		if err != nil {
+		from := app.Status
+
+		// меняем статус
+		if err := s.appRepo.UpdateStatus(ctx, appID, to); err != nil {
 			return err
 		}
-		if cnt >= cap {
-			return ErrNoSeats
+
+		// аудит
+		if err := s.appRepo.InsertAudit(ctx, appID, actorID, actorRole, from, to, reason); err != nil {
+			return err
+		}
+
+		// side-effect: enrollment
+		if to == domain.AppApproved {
+			if err := s.appRepo.CreateEnrollment(ctx, app.UserID, app.GroupID, actorID, actorRole, reason); err != nil {
+				return err
+			}
 		}
-	}
 
-	from := app.Status
+		return s.outbox.Add(ctx, "enrollment_application", appID, outbox.ApplicationStatusChangedV1{
+			ApplicationID: appID,
+			UserID:        app.UserID,
+			GroupID:       app.GroupID,
+			From:          string(from),
+			To:            string(to),
+			ActorRole:     actorRole,
+			Reason:        reason,
+		})
+	})
+}
 
-	// меняем статус
-	if err := s.appRepo.UpdateStatus(ctx, appID, to); err != nil {
+func (s *ApplicationService) checkInterview(ctx context.Context, app domain.EnrollmentApplication) error {
+	req, err := s.catalogRepo.GroupRequiresInterview(ctx, app.GroupID)
+	if err != nil {
 		return err
 	}
-
-	// аудит
-	if err := s.appRepo.InsertAudit(ctx, appID, actorID, actorRole, from, to, reason); err != nil {
+	if !req {
+		return nil
+	}
+	inv, ok, err := s.interviews.GetByApplication(ctx, app.ID)
+	if err != nil {
 		return err
 	}
+	// pending — собеседование назначено (бронь слота), но результата ещё нет
+	if !ok || inv.Result == domain.InterviewPending {
+		return ErrInterviewRequired
+	}
+	if inv.Result != domain.InterviewRecommended {
+		return ErrInterviewFailed
+	}
+	return nil
+}
+
+// PromoteGroup: зачисляет заявки из листа ожидания по очереди, пока в группе есть места.
+// Вызывается, когда места освобождаются (увеличили capacity, ученик ушёл). Возвращает число зачисленных.
+func (s *ApplicationService) PromoteGroup(ctx context.Context, groupID uuid.UUID) (int, error) {
+	promoted := 0
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		promoted = 0
 
-	// side-effect: enrollment
-	if to == domain.AppApproved {
-		if err := s.appRepo.CreateEnrollment(ctx, app.UserID, app.GroupID); err != nil {
+		cap, err := s.appRepo.LockGroupCapacity(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		cnt, err := s.appRepo.CountEnrollmentsByGroup(ctx, groupID)
+		if err != nil {
 			return err
 		}
+
+		for ; cnt < cap; cnt++ {
+			app, ok, err := s.appRepo.NextWaitlisted(ctx, groupID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+			if err := s.promote(ctx, app); err != nil {
+				return err
+			}
+			promoted++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
+	return promoted, nil
+}
 
-	_ = s.outbox.Add(ctx, "enrollment_application", appID, "application.status_changed", map[string]any{
-		"application_id": appID.String(),
-		"from":           string(from),
-		"to":             string(to),
-		"actor_role":     actorRole,
+// promote: waitlisted -> approved от имени system (внутри транзакции PromoteGroup)
+func (s *ApplicationService) promote(ctx context.Context, app domain.EnrollmentApplication) error {
+	const role = "system"
+	if err := domain.CanTransition(app.Status, domain.AppApproved, role); err != nil {
+		return err
+	}
+	if err := s.appRepo.UpdateStatus(ctx, app.ID, domain.AppApproved); err != nil {
+		return err
+	}
+	if err := s.appRepo.InsertAudit(ctx, app.ID, uuid.Nil, role, app.Status, domain.AppApproved, reasonWaitlistPromotion); err != nil {
+		return err
+	}
+	if err := s.appRepo.CreateEnrollment(ctx, app.UserID, app.GroupID, uuid.Nil, role, reasonWaitlistPromotion); err != nil {
+		return err
+	}
+	if err := s.outbox.Add(ctx, "enrollment_application", app.ID, outbox.ApplicationStatusChangedV1{
+		ApplicationID: app.ID,
+		UserID:        app.UserID,
+		GroupID:       app.GroupID,
+		From:          string(app.Status),
+		To:            string(domain.AppApproved),
+		ActorRole:     role,
+		Reason:        reasonWaitlistPromotion,
+	}); err != nil {
+		return err
+	}
+	return s.outbox.Add(ctx, "enrollment_application", app.ID, outbox.ApplicationPromotedV1{
+		ApplicationID: app.ID,
+		UserID:        app.UserID,
+		GroupID:       app.GroupID,
 	})
+}
 
-	return nil
+type WaitlistPosition struct {
+	ApplicationID uuid.UUID
+	GroupID       uuid.UUID
+	Position      int // с 1
+	Total         int // всего в очереди группы
+}
+
+// WaitlistPosition: место в очереди — заявителю или его guardian
+func (s *ApplicationService) WaitlistPosition(ctx context.Context, appID uuid.UUID) (WaitlistPosition, error) {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return WaitlistPosition{}, errors.New("unauthorized")
+	}
+
+	app, err := s.appRepo.Get(ctx, appID)
+	if err != nil {
+		return WaitlistPosition{}, err
+	}
+	if app.UserID != actorID {
+		if err := s.ensureGuardian(ctx, actorID, app.UserID); err != nil {
+			return WaitlistPosition{}, err
+		}
+	}
+
+	pos, total, ok, err := s.appRepo.WaitlistPosition(ctx, appID)
+	if err != nil {
+		return WaitlistPosition{}, err
+	}
+	if !ok {
+		return WaitlistPosition{}, ErrNotWaitlisted
+	}
+	return WaitlistPosition{ApplicationID: app.ID, GroupID: app.GroupID, Position: pos, Total: total}, nil
+}
+
+// History: заявка, все переходы статусов и собеседование (для staff/преподавателя группы — проверка в handler)
+func (s *ApplicationService) History(ctx context.Context, appID uuid.UUID) (domain.ApplicationHistory, error) {
+	app, err := s.appRepo.Get(ctx, appID)
+	if err != nil {
+		return domain.ApplicationHistory{}, err
+	}
+	timeline, err := s.appRepo.ListAudit(ctx, appID)
+	if err != nil {
+		return domain.ApplicationHistory{}, err
+	}
+	h := domain.ApplicationHistory{Application: app, Timeline: timeline}
+	if iv, ok, err := s.interviews.GetByApplication(ctx, appID); err != nil {
+		return domain.ApplicationHistory{}, err
+	} else if ok {
+		h.Interview = &iv
+	}
+	return h, nil
+}
+
+// MyHistory: история своей заявки (или заявки ребёнка). Кто из staff менял статус
+// и комментарий собеседующего заявителю не показываются — только роль, причина и результат.
+func (s *ApplicationService) MyHistory(ctx context.Context, appID uuid.UUID) (domain.ApplicationHistory, error) {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return domain.ApplicationHistory{}, errors.New("unauthorized")
+	}
+
+	h, err := s.History(ctx, appID)
+	if err != nil {
+		return domain.ApplicationHistory{}, err
+	}
+	app := h.Application
+	if app.UserID != actorID {
+		if err := s.ensureGuardian(ctx, actorID, app.UserID); err != nil {
+			return domain.ApplicationHistory{}, err
+		}
+	}
+
+	for i, a := range h.Timeline {
+		if a.ActorUserID != nil && *a.ActorUserID != app.UserID && *a.ActorUserID != app.SubmittedBy && *a.ActorUserID != actorID {
+			h.Timeline[i].ActorUserID = nil
+		}
+	}
+	if h.Interview != nil {
+		h.Interview.InterviewerUserID = uuid.Nil
+		h.Interview.Comment = ""
+	}
+	return h, nil
 }
 
+// Cancel: сам заявитель или его guardian
 func (s *ApplicationService) Cancel(ctx context.Context, appID uuid.UUID) error {
-	userID, ok := auth.UserID(ctx)
+	actorID, ok := auth.UserID(ctx)
 	if !ok {
 		return errors.New("unauthorized")
 	}
 
-	ok2, err := s.appRepo.CancelByUser(ctx, appID, userID)
+	app, err := s.appRepo.Get(ctx, appID)
 	if err != nil {
 		return err
 	}
-	if !ok2 {
-		// либо не твоя заявка, либо уже не тот статус
-		return errors.New("cannot cancel application")
+	if app.UserID != actorID {
+		if err := s.ensureGuardian(ctx, actorID, app.UserID); err != nil {
+			return err
+		}
 	}
 
-	_ = s.outbox.Add(ctx, "enrollment_application", appID, "application.cancelled", map[string]any{
-		"application_id": appID.String(),
-		"user_id":        userID.String(),
-	})
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		ok2, err := s.appRepo.CancelByUser(ctx, appID, app.UserID)
+		if err != nil {
+			return err
+		}
+		if !ok2 {
+			// уже не тот статус
+			return errors.New("cannot cancel application")
+		}
 
-	return nil
+		if err := s.appRepo.InsertAudit(ctx, appID, actorID, actorRoleFor(ctx, actorID, app.UserID), app.Status, domain.AppCancelled, ""); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, "enrollment_application", appID, outbox.ApplicationCancelledV1{
+			ApplicationID: appID,
+			UserID:        app.UserID,
+			GroupID:       app.GroupID,
+			CancelledBy:   actorID,
+		})
+	})
 }
@@ -0,0 +1,33 @@
+// internal/service/application_window.go
+
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+// RunApplicationWindows раз в interval открывает/закрывает приём в группы по opens_at/closes_at.
+// Крутится до отмены ctx. Заявки проверяются по окну и без неё (IsGroupAvailableForApply).
+func RunApplicationWindows(ctx context.Context, catalog *repo.CatalogRepo, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		opened, closed, err := catalog.SyncGroupWindows(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("application windows", "err", err)
+		} else if opened > 0 || closed > 0 {
+			slog.Info("application windows", "opened", opened, "closed", closed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -10,18 +10,22 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
 	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
 )
 
 type AssignmentService struct {
-	catalog *repo.CatalogRepo
-	appRepo *repo.ApplicationRepo
 	asgRepo *repo.AssignmentRepo
+	outbox  *outbox.Repo
+	tx      *db.TxManager
+	az      *authz.Authorizer
 }
 
-func NewAssignmentService(catalog *repo.CatalogRepo, appRepo *repo.ApplicationRepo, asgRepo *repo.AssignmentRepo) *AssignmentService {
-	return &AssignmentService{catalog: catalog, appRepo: appRepo, asgRepo: asgRepo}
+func NewAssignmentService(asgRepo *repo.AssignmentRepo, outboxRepo *outbox.Repo, tx *db.TxManager, az *authz.Authorizer) *AssignmentService {
+	return &AssignmentService{asgRepo: asgRepo, outbox: outboxRepo, tx: tx, az: az}
 }
 
 // Create: admin OR assigned teacher (not a global role)
@@ -30,16 +34,8 @@ func (s *AssignmentService) Create(ctx context.Context, groupID uuid.UUID, title
 	if !ok {
 		return uuid.Nil, errors.New("unauthorized")
 	}
-	role := auth.Role(ctx)
-
-	if role != "admin" {
-		assigned, err := s.catalog.IsTeacherInGroup(ctx, groupID, actorID)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		if !assigned {
-			return uuid.Nil, errors.New("forbidden")
-		}
+	if err := s.az.RequireInGroup(ctx, authz.AssignmentCreate, groupID); err != nil {
+		return uuid.Nil, err
 	}
 
 	a := domain.Assignment{
@@ -50,7 +46,18 @@ func (s *AssignmentService) Create(ctx context.Context, groupID uuid.UUID, title
 		DueAt:       dueAt,
 		CreatedBy:   actorID,
 	}
-	if err := s.asgRepo.Create(ctx, a); err != nil {
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.asgRepo.Create(ctx, a); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, "assignment", a.ID, outbox.AssignmentCreatedV1{
+			AssignmentID: a.ID,
+			GroupID:      groupID,
+			Title:        title,
+			DueAt:        dueAt,
+		})
+	})
+	if err != nil {
 		return uuid.Nil, err
 	}
 	return a.ID, nil
@@ -58,11 +65,10 @@ func (s *AssignmentService) Create(ctx context.Context, groupID uuid.UUID, title
 
 // ListForLearner: only if enrolled
 func (s *AssignmentService) ListForLearner(ctx context.Context, groupID uuid.UUID) ([]domain.Assignment, error) {
-	userID, ok := auth.UserID(ctx)
-	if !ok {
+	if _, ok := auth.LearnerID(ctx); !ok {
 		return nil, errors.New("unauthorized")
 	}
-	has, err := s.appRepo.HasEnrollment(ctx, userID, groupID)
+	has, err := s.az.CanInGroup(ctx, authz.LearnAccess, groupID)
 	if err != nil {
 		return nil, err
 	}
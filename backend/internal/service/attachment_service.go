@@ -0,0 +1,253 @@
+// internal/service/attachment_service.go
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+	"github.com/Pavlushechko/itcube-education/internal/storage"
+)
+
+var (
+	ErrAttachmentsClosed  = errors.New("application is closed for attachments")
+	ErrTooManyAttachments = errors.New("too many attachments")
+	ErrFileEmpty          = errors.New("file is empty")
+	ErrFileTooLarge       = errors.New("file is too large")
+	ErrFileTypeNotAllowed = errors.New("file type is not allowed")
+	ErrFileInfected       = errors.New("file did not pass virus scan")
+)
+
+// AttachmentLimits: ограничения на загружаемые файлы
+type AttachmentLimits struct {
+	MaxSize           int64
+	AllowedTypes      []string // MIME по содержимому файла, не по расширению
+	MaxPerApplication int
+}
+
+// AttachmentService: документы к заявке.
+// Загружает и удаляет заявитель или его guardian, пока заявка не в финальном статусе;
+// скачивание — только staff и преподаватели группы заявки.
+type AttachmentService struct {
+	appRepo     *repo.ApplicationRepo
+	attachments *repo.AttachmentRepo
+	guardians   *repo.GuardianRepo
+	store       storage.Storage
+	scanner     storage.Scanner
+	limits      AttachmentLimits
+	az          *authz.Authorizer
+	tx          *db.TxManager
+}
+
+func NewAttachmentService(appRepo *repo.ApplicationRepo, attachments *repo.AttachmentRepo, guardians *repo.GuardianRepo, store storage.Storage, scanner storage.Scanner, limits AttachmentLimits, az *authz.Authorizer, tx *db.TxManager) *AttachmentService {
+	if scanner == nil {
+		scanner = storage.NoopScanner{}
+	}
+	return &AttachmentService{appRepo: appRepo, attachments: attachments, guardians: guardians, store: store, scanner: scanner, limits: limits, az: az, tx: tx}
+}
+
+func (s *AttachmentService) MaxSize() int64 { return s.limits.MaxSize }
+
+// Upload: проверки размера/типа/антивируса, файл в хранилище, метаданные в БД.
+// Файл читается в память целиком (не больше MaxSize) — его нужно и проверить, и сохранить.
+func (s *AttachmentService) Upload(ctx context.Context, appID uuid.UUID, fileName string, r io.Reader) (domain.ApplicationAttachment, error) {
+	app, actorID, err := applicantSide(ctx, s.appRepo, s.guardians, appID)
+	if err != nil {
+		return domain.ApplicationAttachment{}, err
+	}
+	// быстрый отказ до чтения файла; окончательно проверяется под блокировкой заявки
+	if err := s.canAttach(ctx, app); err != nil {
+		return domain.ApplicationAttachment{}, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.limits.MaxSize+1))
+	if err != nil {
+		return domain.ApplicationAttachment{}, err
+	}
+	if len(data) == 0 {
+		return domain.ApplicationAttachment{}, ErrFileEmpty
+	}
+	if int64(len(data)) > s.limits.MaxSize {
+		return domain.ApplicationAttachment{}, ErrFileTooLarge
+	}
+
+	contentType := detectContentType(data)
+	if !s.allowedType(contentType) {
+		return domain.ApplicationAttachment{}, ErrFileTypeNotAllowed
+	}
+
+	scanStatus := "clean"
+	if _, noop := s.scanner.(storage.NoopScanner); noop {
+		scanStatus = "skipped"
+	} else {
+		clean, signature, err := s.scanner.Scan(ctx, bytes.NewReader(data))
+		if err != nil {
+			return domain.ApplicationAttachment{}, fmt.Errorf("virus scan: %w", err)
+		}
+		if !clean {
+			slog.Warn("attachment rejected by virus scan", "application_id", appID, "user_id", actorID, "signature", signature)
+			return domain.ApplicationAttachment{}, ErrFileInfected
+		}
+	}
+
+	sum := sha256.Sum256(data)
+	a := domain.ApplicationAttachment{
+		ID:            uuid.New(),
+		ApplicationID: appID,
+		UploadedBy:    actorID,
+		FileName:      cleanFileName(fileName),
+		ContentType:   contentType,
+		SizeBytes:     int64(len(data)),
+		SHA256:        hex.EncodeToString(sum[:]),
+		ScanStatus:    scanStatus,
+	}
+	a.StorageKey = "applications/" + appID.String() + "/" + a.ID.String()
+
+	if err := s.store.Put(ctx, a.StorageKey, bytes.NewReader(data), a.SizeBytes, a.ContentType); err != nil {
+		return domain.ApplicationAttachment{}, fmt.Errorf("store attachment: %w", err)
+	}
+	// строка заявки блокируется: параллельные загрузки не превысят MaxPerApplication,
+	// а заявка не уйдёт в финальный статус между проверкой и записью
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		app, err := s.appRepo.GetForUpdate(ctx, appID)
+		if err != nil {
+			return err
+		}
+		if err := s.canAttach(ctx, app); err != nil {
+			return err
+		}
+		a, err = s.attachments.Create(ctx, a)
+		return err
+	})
+	if err != nil {
+		// лимит исчерпан, заявка закрылась или строка не записалась — файл в хранилище никому не нужен
+		if derr := s.store.Delete(context.WithoutCancel(ctx), a.StorageKey); derr != nil {
+			slog.Error("attachment cleanup", "key", a.StorageKey, "err", derr)
+		}
+		return domain.ApplicationAttachment{}, err
+	}
+	return a, nil
+}
+
+// canAttach: заявка не в финальном статусе и лимит файлов не исчерпан
+func (s *AttachmentService) canAttach(ctx context.Context, app domain.EnrollmentApplication) error {
+	if app.Status.IsFinal() {
+		return ErrAttachmentsClosed
+	}
+	if s.limits.MaxPerApplication <= 0 {
+		return nil
+	}
+	n, err := s.attachments.CountByApplication(ctx, app.ID)
+	if err != nil {
+		return err
+	}
+	if n >= s.limits.MaxPerApplication {
+		return ErrTooManyAttachments
+	}
+	return nil
+}
+
+func (s *AttachmentService) allowedType(contentType string) bool {
+	for _, t := range s.limits.AllowedTypes {
+		if strings.EqualFold(strings.TrimSpace(t), contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContentType: MIME по сигнатуре файла без параметров (charset и т.п.)
+func detectContentType(data []byte) string {
+	ct := http.DetectContentType(data)
+	if mt, _, err := mime.ParseMediaType(ct); err == nil {
+		return mt
+	}
+	return ct
+}
+
+// cleanFileName: только имя без пути (имя из multipart задаёт клиент)
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if r := []rune(name); len(r) > 255 {
+		name = string(r[len(r)-255:])
+	}
+	return name
+}
+
+func (s *AttachmentService) ListForApplicant(ctx context.Context, appID uuid.UUID) ([]domain.ApplicationAttachment, error) {
+	if _, _, err := applicantSide(ctx, s.appRepo, s.guardians, appID); err != nil {
+		return nil, err
+	}
+	return s.attachments.ListByApplication(ctx, appID)
+}
+
+// DeleteAsApplicant: пока заявка не в финальном статусе, файл можно убрать
+func (s *AttachmentService) DeleteAsApplicant(ctx context.Context, appID, attID uuid.UUID) error {
+	app, _, err := applicantSide(ctx, s.appRepo, s.guardians, appID)
+	if err != nil {
+		return err
+	}
+	if app.Status.IsFinal() {
+		return ErrAttachmentsClosed
+	}
+	a, err := s.attachments.Get(ctx, appID, attID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.attachments.Delete(ctx, appID, attID); err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, a.StorageKey); err != nil {
+		slog.Error("attachment delete", "key", a.StorageKey, "err", err)
+	}
+	return nil
+}
+
+func (s *AttachmentService) ListForStaff(ctx context.Context, appID uuid.UUID) ([]domain.ApplicationAttachment, error) {
+	if _, _, err := staffSide(ctx, s.appRepo, s.az, appID); err != nil {
+		return nil, err
+	}
+	return s.attachments.ListByApplication(ctx, appID)
+}
+
+// Download: метаданные и содержимое файла; закрыть reader — на вызывающем
+func (s *AttachmentService) Download(ctx context.Context, appID, attID uuid.UUID) (domain.ApplicationAttachment, io.ReadCloser, error) {
+	if _, _, err := staffSide(ctx, s.appRepo, s.az, appID); err != nil {
+		return domain.ApplicationAttachment{}, nil, err
+	}
+	a, err := s.attachments.Get(ctx, appID, attID)
+	if err != nil {
+		return domain.ApplicationAttachment{}, nil, err
+	}
+	body, err := s.store.Get(ctx, a.StorageKey)
+	if err != nil {
+		return domain.ApplicationAttachment{}, nil, err
+	}
+	return a, body, nil
+}
@@ -0,0 +1,186 @@
+// internal/service/auth_service.go
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrWeakPassword        = errors.New("password must be at least 8 characters")
+	ErrPasswordTooLong     = errors.New("password must be at most 72 bytes")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+)
+
+const minPasswordLen = 8
+
+// bcrypt не принимает пароли длиннее 72 байт
+const maxPasswordLen = 72
+
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	TokenType        string    `json:"token_type"`
+}
+
+type AuthService struct {
+	users      *repo.UserRepo
+	issuer     *auth.Issuer
+	refreshTTL time.Duration
+}
+
+func NewAuthService(users *repo.UserRepo, issuer *auth.Issuer, refreshTTL time.Duration) *AuthService {
+	return &AuthService{users: users, issuer: issuer, refreshTTL: refreshTTL}
+}
+
+// Register: самостоятельная регистрация всегда даёт роль user (staff назначается вручную в БД)
+func (s *AuthService) Register(ctx context.Context, email, password string) (uuid.UUID, error) {
+	if len(password) < minPasswordLen {
+		return uuid.Nil, ErrWeakPassword
+	}
+	if len(password) > maxPasswordLen {
+		return uuid.Nil, ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	u := domain.User{
+		ID:           uuid.New(),
+		Email:        strings.TrimSpace(email),
+		PasswordHash: string(hash),
+		Role:         "user",
+	}
+	if err := s.users.Create(ctx, u); err != nil {
+		return uuid.Nil, err
+	}
+	return u.ID, nil
+}
+
+func (s *AuthService) Login(ctx context.Context, email, password string) (TokenPair, error) {
+	u, ok, err := s.users.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if !ok {
+		// сравниваем с фиктивным хешем, чтобы время ответа не выдавало существование email
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return TokenPair{}, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return TokenPair{}, ErrInvalidCredentials
+	}
+
+	return s.issuePair(ctx, u, uuid.New())
+}
+
+// Refresh: старый refresh-токен отзывается, выдаётся новая пара.
+// Повторное использование уже отозванного токена = утечка -> отзываем всю цепочку.
+func (s *AuthService) Refresh(ctx context.Context, raw string) (TokenPair, error) {
+	t, ok, err := s.users.GetRefreshTokenByHash(ctx, hashToken(raw))
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if !ok {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+	if t.RevokedAt != nil {
+		if err := s.users.RevokeRefreshFamily(ctx, t.FamilyID); err != nil {
+			return TokenPair{}, err
+		}
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+	if time.Now().After(t.ExpiresAt) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	revoked, err := s.users.RevokeRefreshToken(ctx, t.ID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if !revoked {
+		// параллельный refresh тем же токеном
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	u, err := s.users.Get(ctx, t.UserID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return s.issuePair(ctx, u, t.FamilyID)
+}
+
+// Logout: отзываем цепочку, к которой принадлежит токен (идемпотентно)
+func (s *AuthService) Logout(ctx context.Context, raw string) error {
+	t, ok, err := s.users.GetRefreshTokenByHash(ctx, hashToken(raw))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	return s.users.RevokeRefreshFamily(ctx, t.FamilyID)
+}
+
+func (s *AuthService) issuePair(ctx context.Context, u domain.User, familyID uuid.UUID) (TokenPair, error) {
+	access, accessExp, err := s.issuer.Issue(u.ID, []string{u.Role})
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	raw, err := newRefreshToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+	rt := domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    u.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}
+	if err := s.users.CreateRefreshToken(ctx, rt); err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:      access,
+		AccessExpiresAt:  accessExp,
+		RefreshToken:     raw,
+		RefreshExpiresAt: rt.ExpiresAt,
+		TokenType:        "Bearer",
+	}, nil
+}
+
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,95 @@
+// internal/service/eligibility_service.go
+
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+type EligibilityService struct {
+	rules       *repo.EligibilityRepo
+	catalogRepo *repo.CatalogRepo
+}
+
+func NewEligibilityService(rules *repo.EligibilityRepo, catalogRepo *repo.CatalogRepo) *EligibilityService {
+	return &EligibilityService{rules: rules, catalogRepo: catalogRepo}
+}
+
+type GroupEligibility struct {
+	GroupID  uuid.UUID
+	Title    string
+	Eligible bool
+	Reasons  []domain.IneligibilityReason
+}
+
+// ProgramEligibility: допуск к программе в целом и к каждой её группе
+// (у группы может быть своё правило вместо правила программы)
+type ProgramEligibility struct {
+	ProgramID uuid.UUID
+	Eligible  bool
+	Reasons   []domain.IneligibilityReason
+	Groups    []GroupEligibility
+}
+
+// Check: причины, по которым ученик не может подать заявку в группу (nil — может)
+func (s *EligibilityService) Check(ctx context.Context, userID, groupID uuid.UUID) ([]domain.IneligibilityReason, error) {
+	rule, ok, err := s.rules.GetEffective(ctx, groupID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	facts, err := s.rules.Facts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return rule.Evaluate(facts, time.Now()), nil
+}
+
+// ForProgram: допуск текущего ученика (или ребёнка guardian) к опубликованной программе
+func (s *EligibilityService) ForProgram(ctx context.Context, programID uuid.UUID) (ProgramEligibility, error) {
+	learnerID, ok := auth.LearnerID(ctx)
+	if !ok {
+		return ProgramEligibility{}, errors.New("unauthorized")
+	}
+
+	pg, err := s.catalogRepo.GetPublishedProgramWithGroups(ctx, programID)
+	if err != nil {
+		return ProgramEligibility{}, err
+	}
+	facts, err := s.rules.Facts(ctx, learnerID)
+	if err != nil {
+		return ProgramEligibility{}, err
+	}
+	now := time.Now()
+
+	res := ProgramEligibility{ProgramID: programID, Reasons: []domain.IneligibilityReason{}, Groups: []GroupEligibility{}}
+	rule, ok, err := s.rules.GetByProgram(ctx, programID)
+	if err != nil {
+		return ProgramEligibility{}, err
+	}
+	if ok {
+		res.Reasons = append(res.Reasons, rule.Evaluate(facts, now)...)
+	}
+	res.Eligible = len(res.Reasons) == 0
+
+	for _, g := range pg.Groups {
+		ge := GroupEligibility{GroupID: g.ID, Title: g.Title, Reasons: []domain.IneligibilityReason{}}
+		rule, ok, err := s.rules.GetEffective(ctx, g.ID)
+		if err != nil {
+			return ProgramEligibility{}, err
+		}
+		if ok {
+			ge.Reasons = append(ge.Reasons, rule.Evaluate(facts, now)...)
+		}
+		ge.Eligible = len(ge.Reasons) == 0
+		res.Groups = append(res.Groups, ge)
+	}
+	return res, nil
+}
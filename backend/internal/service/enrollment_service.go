@@ -0,0 +1,340 @@
+// internal/service/enrollment_service.go
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+var (
+	ErrEnrollmentNotFound = errors.New("enrollment not found")
+	ErrSameGroup          = errors.New("target group is the current group")
+	ErrOtherProgram       = errors.New("target group belongs to another program")
+	ErrAlreadyEnrolled    = errors.New("already enrolled in target group")
+)
+
+type EnrollmentService struct {
+	enrollments *repo.EnrollmentRepo
+	appRepo     *repo.ApplicationRepo
+	catalogRepo *repo.CatalogRepo
+	apps        *ApplicationService // освободившееся место -> лист ожидания
+	guardians   *repo.GuardianRepo
+	outbox      *outbox.Repo
+	tx          *db.TxManager
+}
+
+func NewEnrollmentService(enrollments *repo.EnrollmentRepo, appRepo *repo.ApplicationRepo, catalogRepo *repo.CatalogRepo, apps *ApplicationService, guardians *repo.GuardianRepo, outboxRepo *outbox.Repo, tx *db.TxManager) *EnrollmentService {
+	return &EnrollmentService{enrollments: enrollments, appRepo: appRepo, catalogRepo: catalogRepo, apps: apps, guardians: guardians, outbox: outboxRepo, tx: tx}
+}
+
+// ListMine: зачисления текущего ученика (или ребёнка — learnerID из auth.LearnerID)
+func (s *EnrollmentService) ListMine(ctx context.Context) ([]domain.Enrollment, error) {
+	learnerID, ok := auth.LearnerID(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	return s.enrollments.ListByUser(ctx, learnerID)
+}
+
+// Withdraw: ученик уходит сам (или его guardian)
+func (s *EnrollmentService) Withdraw(ctx context.Context, enrollmentID uuid.UUID, reason string) error {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("unauthorized")
+	}
+
+	e, found, err := s.enrollments.Get(ctx, enrollmentID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrEnrollmentNotFound
+	}
+	role := "user"
+	if e.UserID != actorID {
+		linked, err := s.guardians.IsGuardianOf(ctx, actorID, e.UserID)
+		if err != nil {
+			return err
+		}
+		if !linked {
+			// чужое зачисление не показываем
+			return ErrEnrollmentNotFound
+		}
+		role = "guardian"
+	}
+	return s.changeStatus(ctx, e, domain.EnrollmentWithdrawn, actorID, role, reason)
+}
+
+// ChangeStatus: staff — отчисление, завершение курса, вывод по заявлению
+func (s *EnrollmentService) ChangeStatus(ctx context.Context, enrollmentID uuid.UUID, to domain.EnrollmentStatus, reason string) error {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("unauthorized")
+	}
+	if err := authz.Require(ctx, authz.EnrollmentManage); err != nil {
+		return err
+	}
+
+	e, found, err := s.enrollments.Get(ctx, enrollmentID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrEnrollmentNotFound
+	}
+	return s.changeStatus(ctx, e, to, actorID, auth.Role(ctx), reason)
+}
+
+// changeStatus: статус + аудит + outbox и (при withdrawn/expelled) зачисление из листа ожидания на освободившееся место — одной транзакцией.
+// Порядок блокировок как в ApplicationService.ChangeStatus: group -> строка.
+func (s *EnrollmentService) changeStatus(ctx context.Context, cur domain.Enrollment, to domain.EnrollmentStatus, actorID uuid.UUID, actorRole, reason string) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.appRepo.LockGroupCapacity(ctx, cur.GroupID); err != nil {
+			return err
+		}
+
+		e, err := s.enrollments.GetForUpdate(ctx, cur.ID)
+		if err != nil {
+			return err
+		}
+		if err := domain.CanTransitionEnrollment(e.Status, to, actorRole, reason); err != nil {
+			return err
+		}
+
+		if err := s.enrollments.UpdateStatus(ctx, e.ID, to, reason); err != nil {
+			return err
+		}
+		if err := s.enrollments.InsertAudit(ctx, e.ID, actorID, actorRole, e.Status, to, reason); err != nil {
+			return err
+		}
+		if err := s.outbox.Add(ctx, "enrollment", e.ID, outbox.EnrollmentStatusChangedV1{
+			EnrollmentID: e.ID,
+			UserID:       e.UserID,
+			GroupID:      e.GroupID,
+			From:         string(e.Status),
+			To:           string(to),
+			ActorRole:    actorRole,
+			Reason:       reason,
+		}); err != nil {
+			return err
+		}
+
+		// место освобождается только при уходе; completed — курс закончен, из очереди не зачисляем
+		if to != domain.EnrollmentWithdrawn && to != domain.EnrollmentExpelled {
+			return nil
+		}
+		_, err = s.apps.PromoteGroup(ctx, e.GroupID)
+		return err
+	})
+}
+
+type TransferInput struct {
+	ToGroupID     uuid.UUID
+	CarryProgress bool // перенести прочитанные материалы и сданные работы
+	Reason        string
+	Readmit       bool // вернуть в группу, откуда ученик был отчислен
+}
+
+// Transfer: перевод в другую группу той же программы (staff).
+// Старое зачисление -> transferred, в целевой группе — новое active (или прежнее снова active),
+// освободившееся место отдаётся листу ожидания исходной группы. Всё одной транзакцией.
+func (s *EnrollmentService) Transfer(ctx context.Context, enrollmentID uuid.UUID, in TransferInput) (domain.EnrollmentTransfer, error) {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return domain.EnrollmentTransfer{}, errors.New("unauthorized")
+	}
+	if err := authz.Require(ctx, authz.EnrollmentManage); err != nil {
+		return domain.EnrollmentTransfer{}, err
+	}
+	actorRole := auth.Role(ctx)
+
+	cur, found, err := s.enrollments.Get(ctx, enrollmentID)
+	if err != nil {
+		return domain.EnrollmentTransfer{}, err
+	}
+	if !found {
+		return domain.EnrollmentTransfer{}, ErrEnrollmentNotFound
+	}
+	if cur.GroupID == in.ToGroupID {
+		return domain.EnrollmentTransfer{}, ErrSameGroup
+	}
+
+	fromPID, err := s.catalogRepo.GetGroupProgramID(ctx, cur.GroupID)
+	if err != nil {
+		return domain.EnrollmentTransfer{}, err
+	}
+	toPID, err := s.catalogRepo.GetGroupProgramID(ctx, in.ToGroupID)
+	if err != nil {
+		return domain.EnrollmentTransfer{}, err
+	}
+	if fromPID != toPID {
+		return domain.EnrollmentTransfer{}, ErrOtherProgram
+	}
+
+	t := domain.EnrollmentTransfer{
+		ID:               uuid.New(),
+		UserID:           cur.UserID,
+		FromEnrollmentID: cur.ID,
+		FromGroupID:      cur.GroupID,
+		ToGroupID:        in.ToGroupID,
+		ActorUserID:      actorID,
+		ActorRole:        actorRole,
+		Reason:           in.Reason,
+		CarryProgress:    in.CarryProgress,
+	}
+
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		// обе группы блокируем в одном порядке, чтобы встречные переводы не взаимоблокировались
+		first, second := cur.GroupID, in.ToGroupID
+		if first.String() > second.String() {
+			first, second = second, first
+		}
+		caps := map[uuid.UUID]int{}
+		for _, gid := range []uuid.UUID{first, second} {
+			c, err := s.appRepo.LockGroupCapacity(ctx, gid)
+			if err != nil {
+				return err
+			}
+			caps[gid] = c
+		}
+
+		e, err := s.enrollments.GetForUpdate(ctx, cur.ID)
+		if err != nil {
+			return err
+		}
+		if e.Status != domain.EnrollmentActive {
+			return domain.ErrInvalidTransition
+		}
+
+		// не IsGroupAvailableForApply: после закрытия окна приёма переводить в группу можно
+		open, err := s.catalogRepo.IsGroupOpenForTransfer(ctx, in.ToGroupID)
+		if err != nil {
+			return err
+		}
+		if !open {
+			return ErrGroupClosed
+		}
+		cnt, err := s.appRepo.CountEnrollmentsByGroup(ctx, in.ToGroupID)
+		if err != nil {
+			return err
+		}
+		if cnt >= caps[in.ToGroupID] {
+			return ErrNoSeats
+		}
+
+		// зачисление в целевую группу: новое или прежнее (ушёл раньше) снова active
+		prev, exists, err := s.enrollments.GetByUserGroup(ctx, e.UserID, in.ToGroupID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case exists && prev.Status == domain.EnrollmentActive:
+			return ErrAlreadyEnrolled
+		case exists:
+			if err := domain.CanReadmit(prev.Status, in.Readmit); err != nil {
+				return err
+			}
+			t.ToEnrollmentID = prev.ID
+			if err := s.enrollments.UpdateStatus(ctx, prev.ID, domain.EnrollmentActive, in.Reason); err != nil {
+				return err
+			}
+			if err := s.enrollments.InsertAudit(ctx, prev.ID, actorID, actorRole, prev.Status, domain.EnrollmentActive, in.Reason); err != nil {
+				return err
+			}
+		default:
+			t.ToEnrollmentID = uuid.New()
+			if err := s.enrollments.Create(ctx, domain.Enrollment{
+				ID:      t.ToEnrollmentID,
+				UserID:  e.UserID,
+				GroupID: in.ToGroupID,
+				Status:  domain.EnrollmentActive,
+				Reason:  in.Reason,
+			}); err != nil {
+				return err
+			}
+			if err := s.enrollments.InsertAudit(ctx, t.ToEnrollmentID, actorID, actorRole, "", domain.EnrollmentActive, in.Reason); err != nil {
+				return err
+			}
+		}
+
+		if err := s.enrollments.UpdateStatus(ctx, e.ID, domain.EnrollmentTransferred, in.Reason); err != nil {
+			return err
+		}
+		if err := s.enrollments.InsertAudit(ctx, e.ID, actorID, actorRole, e.Status, domain.EnrollmentTransferred, in.Reason); err != nil {
+			return err
+		}
+
+		if in.CarryProgress {
+			if t.CarriedReads, err = s.enrollments.CopyMaterialReads(ctx, e.UserID, e.GroupID, in.ToGroupID); err != nil {
+				return err
+			}
+			if t.CarriedSubmissions, err = s.enrollments.CopySubmissions(ctx, e.UserID, e.GroupID, in.ToGroupID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.enrollments.InsertTransfer(ctx, t); err != nil {
+			return err
+		}
+		if err := s.outbox.Add(ctx, "enrollment", t.ToEnrollmentID, outbox.EnrollmentTransferredV1{
+			TransferID:         t.ID,
+			UserID:             t.UserID,
+			FromEnrollmentID:   t.FromEnrollmentID,
+			ToEnrollmentID:     t.ToEnrollmentID,
+			FromGroupID:        t.FromGroupID,
+			GroupID:            t.ToGroupID,
+			ActorRole:          actorRole,
+			Reason:             in.Reason,
+			CarriedReads:       t.CarriedReads,
+			CarriedSubmissions: t.CarriedSubmissions,
+		}); err != nil {
+			return err
+		}
+
+		_, err = s.apps.PromoteGroup(ctx, e.GroupID)
+		return err
+	})
+	if err != nil {
+		return domain.EnrollmentTransfer{}, err
+	}
+	return t, nil
+}
+
+// Transfers: переводы, связанные с зачислением (staff)
+func (s *EnrollmentService) Transfers(ctx context.Context, enrollmentID uuid.UUID) ([]domain.EnrollmentTransfer, error) {
+	if err := authz.Require(ctx, authz.EnrollmentManage); err != nil {
+		return nil, err
+	}
+	return s.enrollments.ListTransfers(ctx, enrollmentID)
+}
+
+// History: аудит статусов зачисления (staff)
+func (s *EnrollmentService) History(ctx context.Context, enrollmentID uuid.UUID) ([]domain.EnrollmentAudit, error) {
+	if err := authz.Require(ctx, authz.EnrollmentManage); err != nil {
+		return nil, err
+	}
+	if _, found, err := s.enrollments.Get(ctx, enrollmentID); err != nil {
+		return nil, err
+	} else if !found {
+		return nil, ErrEnrollmentNotFound
+	}
+	return s.enrollments.ListAudit(ctx, enrollmentID)
+}
+
+// ListByGroup: зачисления группы с любым статусом (staff)
+func (s *EnrollmentService) ListByGroup(ctx context.Context, groupID uuid.UUID, status *string) ([]domain.Enrollment, error) {
+	if err := authz.Require(ctx, authz.EnrollmentManage); err != nil {
+		return nil, err
+	}
+	return s.enrollments.ListByGroup(ctx, groupID, status)
+}
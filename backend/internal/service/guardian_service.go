@@ -0,0 +1,109 @@
+// internal/service/guardian_service.go
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+// ErrLastGuardian: у ребёнка нет своего логина — без guardian аккаунт станет недоступен
+var ErrLastGuardian = errors.New("child has no login of its own: link another guardian first")
+
+type GuardianService struct {
+	guardians *repo.GuardianRepo
+	profiles  *repo.ProfileRepo
+	appRepo   *repo.ApplicationRepo
+	tx        *db.TxManager
+}
+
+func NewGuardianService(guardians *repo.GuardianRepo, profiles *repo.ProfileRepo, appRepo *repo.ApplicationRepo, tx *db.TxManager) *GuardianService {
+	return &GuardianService{guardians: guardians, profiles: profiles, appRepo: appRepo, tx: tx}
+}
+
+// CreateChild: профиль ребёнка без собственного логина, сразу привязан к guardian
+func (s *GuardianService) CreateChild(ctx context.Context, p domain.Profile) (uuid.UUID, error) {
+	guardianID, ok := auth.UserID(ctx)
+	if !ok {
+		return uuid.Nil, errors.New("unauthorized")
+	}
+
+	p.UserID = uuid.New()
+	// профиль без связи никому не виден — создаём вместе
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.profiles.Upsert(ctx, p); err != nil {
+			return err
+		}
+		return s.guardians.Link(ctx, guardianID, p.UserID)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return p.UserID, nil
+}
+
+func (s *GuardianService) ListChildren(ctx context.Context) ([]repo.ChildView, error) {
+	guardianID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	return s.guardians.ListChildren(ctx, guardianID)
+}
+
+func (s *GuardianService) Unlink(ctx context.Context, childID uuid.UUID) error {
+	guardianID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("unauthorized")
+	}
+	deleted, err := s.guardians.Unlink(ctx, guardianID, childID)
+	if err != nil || deleted {
+		return err
+	}
+	// не удалили: либо связи и не было (повтор — no-op), либо это последний guardian
+	linked, err := s.guardians.IsGuardianOf(ctx, guardianID, childID)
+	if err != nil {
+		return err
+	}
+	if linked {
+		return ErrLastGuardian
+	}
+	return nil
+}
+
+// LinkExisting: admin связывает два существующих аккаунта (у ребёнка свой логин)
+func (s *GuardianService) LinkExisting(ctx context.Context, guardianID, childID uuid.UUID) error {
+	if err := authz.Require(ctx, authz.GuardianManage); err != nil {
+		return err
+	}
+	return s.guardians.Link(ctx, guardianID, childID)
+}
+
+func (s *GuardianService) ChildApplications(ctx context.Context, childID uuid.UUID) ([]domain.EnrollmentApplication, error) {
+	if err := s.EnsureGuardian(ctx, childID); err != nil {
+		return nil, err
+	}
+	return s.appRepo.ListByUser(ctx, childID)
+}
+
+func (s *GuardianService) EnsureGuardian(ctx context.Context, childID uuid.UUID) error {
+	guardianID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("unauthorized")
+	}
+	linked, err := s.guardians.IsGuardianOf(ctx, guardianID, childID)
+	if err != nil {
+		return err
+	}
+	if !linked {
+		return ErrNotGuardian
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// internal/service/interview_reminders.go
+
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RunInterviewReminders раз в interval ставит в outbox напоминания о собеседованиях,
+// до которых осталось меньше before. Крутится до отмены ctx.
+func RunInterviewReminders(ctx context.Context, slots *InterviewSlotService, interval, before time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		n, err := slots.SendReminders(ctx, before)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("interview reminders", "err", err)
+		} else if n > 0 {
+			slog.Info("interview reminders", "sent", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -9,22 +9,23 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
 	"github.com/Pavlushechko/itcube-education/internal/domain"
 	"github.com/Pavlushechko/itcube-education/internal/outbox"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
 )
 
-var ErrNotAssignedTeacher = errors.New("teacher is not assigned to this group")
-
 type InterviewService struct {
-	appRepo     *repo.ApplicationRepo
-	catalogRepo *repo.CatalogRepo
-	interviews  *repo.InterviewRepo
-	outbox      *outbox.Repo
+	appRepo    *repo.ApplicationRepo
+	interviews *repo.InterviewRepo
+	outbox     *outbox.Repo
+	tx         *db.TxManager
+	az         *authz.Authorizer
 }
 
-func NewInterviewService(appRepo *repo.ApplicationRepo, catalogRepo *repo.CatalogRepo, interviewRepo *repo.InterviewRepo, outboxRepo *outbox.Repo) *InterviewService {
-	return &InterviewService{appRepo: appRepo, catalogRepo: catalogRepo, interviews: interviewRepo, outbox: outboxRepo}
+func NewInterviewService(appRepo *repo.ApplicationRepo, interviewRepo *repo.InterviewRepo, outboxRepo *outbox.Repo, tx *db.TxManager, az *authz.Authorizer) *InterviewService {
+	return &InterviewService{appRepo: appRepo, interviews: interviewRepo, outbox: outboxRepo, tx: tx, az: az}
 }
 
 func (s *InterviewService) Record(ctx context.Context, appID uuid.UUID, result domain.InterviewResult, comment string) error {
@@ -43,15 +44,9 @@ func (s *InterviewService) Record(ctx context.Context, appID uuid.UUID, result d
 		return errors.New("interview can be recorded only when application is in_review")
 	}
 
-	// admin/moderator always; otherwise must be assigned teacher
-	if role != "admin" && role != "moderator" {
-		assigned, err := s.catalogRepo.IsTeacherInGroup(ctx, app.GroupID, actorID)
-		if err != nil {
-			return err
-		}
-		if !assigned {
-			return ErrNotAssignedTeacher
-		}
+	// staff always; otherwise must be assigned teacher
+	if err := s.az.RequireInGroup(ctx, authz.InterviewRecord, app.GroupID); err != nil {
+		return err
 	}
 
 	inv := domain.Interview{
@@ -64,17 +59,17 @@ func (s *InterviewService) Record(ctx context.Context, appID uuid.UUID, result d
 		Comment:           comment,
 	}
 
-	if err := s.interviews.Upsert(ctx, inv); err != nil {
-		return err
-	}
-
-	_ = s.outbox.Add(ctx, "interview", appID, "interview.recorded", map[string]any{
-		"application_id": appID.String(),
-		"group_id":       app.GroupID.String(),
-		"candidate_id":   app.UserID.String(),
-		"result":         string(result),
-		"actor_role":     role,
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.interviews.Upsert(ctx, inv); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, "interview", appID, outbox.InterviewRecordedV1{
+			ApplicationID: appID,
+			GroupID:       app.GroupID,
+			CandidateID:   app.UserID,
+			UserID:        app.UserID,
+			Result:        string(result),
+			ActorRole:     role,
+		})
 	})
-
-	return nil
 }
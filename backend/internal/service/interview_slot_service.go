@@ -0,0 +1,359 @@
+// internal/service/interview_slot_service.go
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+var (
+	ErrSlotInPast        = errors.New("interview slot must start in the future")
+	ErrSlotOverlap       = errors.New("interview slot overlaps another slot of this teacher")
+	ErrSlotTaken         = errors.New("interview slot is already booked")
+	ErrSlotStarted       = errors.New("interview slot has already started")
+	ErrSlotBookingClosed = errors.New("interview can be scheduled only when application is in_review")
+	ErrInterviewDecided  = errors.New("interview result is already recorded")
+)
+
+// сколько напоминаний отправлять за один проход
+const reminderBatch = 100
+
+// InterviewSlotService: расписание собеседований.
+// Преподаватель группы (или staff) публикует слоты, заявитель или его guardian бронирует один слот
+// на заявку in_review, переносит или отменяет бронь. Бронь создаёт собеседование с результатом pending —
+// результат вносит преподаватель (InterviewService.Record).
+type InterviewSlotService struct {
+	appRepo    *repo.ApplicationRepo
+	slots      *repo.InterviewSlotRepo
+	interviews *repo.InterviewRepo
+	guardians  *repo.GuardianRepo
+	outbox     *outbox.Repo
+	tx         *db.TxManager
+	az         *authz.Authorizer
+}
+
+func NewInterviewSlotService(appRepo *repo.ApplicationRepo, slots *repo.InterviewSlotRepo, interviews *repo.InterviewRepo, guardians *repo.GuardianRepo, outboxRepo *outbox.Repo, tx *db.TxManager, az *authz.Authorizer) *InterviewSlotService {
+	return &InterviewSlotService{appRepo: appRepo, slots: slots, interviews: interviews, guardians: guardians, outbox: outboxRepo, tx: tx, az: az}
+}
+
+// ApplicantSlots: бронь заявки и свободные слоты её группы
+type ApplicantSlots struct {
+	Booked    *domain.InterviewSlot
+	Available []domain.InterviewSlot
+}
+
+// CreateSlot: новый слот преподавателя (текущего пользователя) в группе
+func (s *InterviewSlotService) CreateSlot(ctx context.Context, groupID uuid.UUID, in domain.InterviewSlot) (domain.InterviewSlot, error) {
+	actorID, ok := auth.UserID(ctx)
+	if !ok {
+		return domain.InterviewSlot{}, errors.New("unauthorized")
+	}
+	if err := s.az.RequireInGroup(ctx, authz.InterviewRecord, groupID); err != nil {
+		return domain.InterviewSlot{}, err
+	}
+
+	slot := domain.InterviewSlot{
+		ID:            uuid.New(),
+		GroupID:       groupID,
+		TeacherUserID: actorID,
+		StartsAt:      in.StartsAt.UTC(),
+		EndsAt:        in.EndsAt.UTC(),
+		Location:      strings.TrimSpace(in.Location),
+		OnlineURL:     strings.TrimSpace(in.OnlineURL),
+	}
+	if err := slot.Validate(); err != nil {
+		return domain.InterviewSlot{}, err
+	}
+	if !slot.StartsAt.After(time.Now()) {
+		return domain.InterviewSlot{}, ErrSlotInPast
+	}
+
+	var res domain.InterviewSlot
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		// два параллельных запроса одного преподавателя не создадут пересекающиеся слоты
+		if err := s.slots.LockTeacher(ctx, actorID); err != nil {
+			return err
+		}
+		overlap, err := s.slots.HasOverlap(ctx, actorID, slot.StartsAt, slot.EndsAt)
+		if err != nil {
+			return err
+		}
+		if overlap {
+			return ErrSlotOverlap
+		}
+		res, err = s.slots.Create(ctx, slot)
+		return err
+	})
+	if err != nil {
+		return domain.InterviewSlot{}, err
+	}
+	return res, nil
+}
+
+// ListForGroup: слоты группы с бронями (staff или преподаватель группы)
+func (s *InterviewSlotService) ListForGroup(ctx context.Context, groupID uuid.UUID, from time.Time) ([]domain.InterviewSlot, error) {
+	if err := s.az.RequireInGroup(ctx, authz.InterviewRecord, groupID); err != nil {
+		return nil, err
+	}
+	return s.slots.ListByGroup(ctx, groupID, from)
+}
+
+// DeleteSlot: снять слот с расписания; если он забронирован — заявителю уходит отмена
+func (s *InterviewSlotService) DeleteSlot(ctx context.Context, slotID uuid.UUID) error {
+	role := auth.Role(ctx)
+	if _, ok := auth.UserID(ctx); !ok {
+		return errors.New("unauthorized")
+	}
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		slot, err := s.slots.GetForUpdate(ctx, slotID)
+		if err != nil {
+			return err
+		}
+		if err := s.az.RequireInGroup(ctx, authz.InterviewRecord, slot.GroupID); err != nil {
+			return err
+		}
+		held := false
+		if slot.ApplicationID != nil && slot.StartsAt.After(time.Now()) {
+			if held, err = s.slots.IsHeld(ctx, slot.ID); err != nil {
+				return err
+			}
+		}
+		if _, err := s.slots.Delete(ctx, slot.ID); err != nil {
+			return err
+		}
+		if !held {
+			return nil
+		}
+		app, err := s.appRepo.Get(ctx, *slot.ApplicationID)
+		if err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, "interview", app.ID, slotCancelled(slot, app, "teacher", role))
+	})
+}
+
+// ListForApplicant: текущая бронь и свободные слоты (свободные — только пока заявка in_review)
+func (s *InterviewSlotService) ListForApplicant(ctx context.Context, appID uuid.UUID) (ApplicantSlots, error) {
+	app, _, err := applicantSide(ctx, s.appRepo, s.guardians, appID)
+	if err != nil {
+		return ApplicantSlots{}, err
+	}
+	res := ApplicantSlots{Available: []domain.InterviewSlot{}}
+	booked, ok, err := s.slots.GetByApplication(ctx, appID)
+	if err != nil {
+		return ApplicantSlots{}, err
+	}
+	if ok {
+		res.Booked = &booked
+	}
+	if app.Status != domain.AppInReview {
+		return res, nil
+	}
+	res.Available, err = s.slots.ListOpen(ctx, app.GroupID, time.Now())
+	if err != nil {
+		return ApplicantSlots{}, err
+	}
+	return res, nil
+}
+
+// Book: забронировать слот группы заявки. Если бронь уже есть — перенос (старый слот освобождается
+// в той же транзакции). Занятый слот повторно не бронируется.
+func (s *InterviewSlotService) Book(ctx context.Context, appID, slotID uuid.UUID) (domain.InterviewSlot, error) {
+	_, actorID, err := applicantSide(ctx, s.appRepo, s.guardians, appID)
+	if err != nil {
+		return domain.InterviewSlot{}, err
+	}
+
+	var res domain.InterviewSlot
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		// блокировка заявки: параллельные брони одной заявки идут по очереди
+		app, err := s.appRepo.GetForUpdate(ctx, appID)
+		if err != nil {
+			return err
+		}
+		if app.Status != domain.AppInReview {
+			return ErrSlotBookingClosed
+		}
+		if err := s.checkNotDecided(ctx, appID); err != nil {
+			return err
+		}
+
+		slot, err := s.slots.Get(ctx, slotID)
+		if err != nil {
+			return err
+		}
+		if slot.GroupID != app.GroupID {
+			return pgx.ErrNoRows
+		}
+
+		prev, hasPrev, err := s.slots.GetByApplication(ctx, appID)
+		if err != nil {
+			return err
+		}
+		if hasPrev && prev.ID == slot.ID {
+			res = prev
+			return nil
+		}
+		var prevID *uuid.UUID
+		if hasPrev {
+			// прошедший слот (результат так и не внесли) освобождаем, идущий сейчас — нет
+			if now := time.Now(); !prev.StartsAt.After(now) && prev.EndsAt.After(now) {
+				return ErrSlotStarted
+			}
+			if _, err := s.slots.Release(ctx, prev.ID, appID); err != nil {
+				return err
+			}
+			prevID = &prev.ID
+		}
+
+		if !slot.StartsAt.After(time.Now()) {
+			return ErrSlotStarted
+		}
+		ok, err := s.slots.Book(ctx, slot.ID, appID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrSlotTaken
+		}
+
+		if err := s.interviews.EnsurePending(ctx, domain.Interview{
+			ApplicationID:     appID,
+			GroupID:           app.GroupID,
+			CandidateUserID:   app.UserID,
+			InterviewerUserID: slot.TeacherUserID,
+			InterviewerRole:   "teacher",
+		}); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		slot.ApplicationID, slot.BookedAt, slot.ReminderSentAt = &appID, &now, nil
+		res = slot
+		return s.outbox.Add(ctx, "interview", appID, outbox.InterviewSlotBookedV1{
+			SlotID:         slot.ID,
+			ApplicationID:  appID,
+			UserID:         app.UserID,
+			GroupID:        app.GroupID,
+			TeacherID:      slot.TeacherUserID,
+			StartsAt:       slot.StartsAt,
+			EndsAt:         slot.EndsAt,
+			Location:       slot.Location,
+			OnlineURL:      slot.OnlineURL,
+			PreviousSlotID: prevID,
+			ActorRole:      actorRoleFor(ctx, actorID, app.UserID),
+		})
+	})
+	if err != nil {
+		return domain.InterviewSlot{}, err
+	}
+	return res, nil
+}
+
+// Cancel: снять бронь заявки (до начала собеседования). Собеседование остаётся pending.
+func (s *InterviewSlotService) Cancel(ctx context.Context, appID uuid.UUID) error {
+	_, actorID, err := applicantSide(ctx, s.appRepo, s.guardians, appID)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		app, err := s.appRepo.GetForUpdate(ctx, appID)
+		if err != nil {
+			return err
+		}
+		if app.Status != domain.AppInReview {
+			return ErrSlotBookingClosed
+		}
+		slot, ok, err := s.slots.GetByApplication(ctx, appID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return pgx.ErrNoRows
+		}
+		if !slot.StartsAt.After(time.Now()) {
+			return ErrSlotStarted
+		}
+		if _, err := s.slots.Release(ctx, slot.ID, appID); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, "interview", appID, slotCancelled(slot, app, "applicant", actorRoleFor(ctx, actorID, app.UserID)))
+	})
+}
+
+// SendReminders: событие interview.reminder по броням, до которых осталось меньше before.
+// Отметка и событие пишутся одной транзакцией — напоминание уходит один раз.
+func (s *InterviewSlotService) SendReminders(ctx context.Context, before time.Duration) (int, error) {
+	n := 0
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		due, err := s.slots.DueReminders(ctx, time.Now().Add(before), reminderBatch)
+		if err != nil {
+			return err
+		}
+		for _, slot := range due {
+			app, err := s.appRepo.Get(ctx, *slot.ApplicationID)
+			if err != nil {
+				return err
+			}
+			if err := s.slots.MarkReminded(ctx, slot.ID); err != nil {
+				return err
+			}
+			if err := s.outbox.Add(ctx, "interview", app.ID, outbox.InterviewReminderV1{
+				SlotID:        slot.ID,
+				ApplicationID: app.ID,
+				UserID:        app.UserID,
+				GroupID:       app.GroupID,
+				TeacherID:     slot.TeacherUserID,
+				StartsAt:      slot.StartsAt,
+				EndsAt:        slot.EndsAt,
+				Location:      slot.Location,
+				OnlineURL:     slot.OnlineURL,
+			}); err != nil {
+				return err
+			}
+		}
+		n = len(due)
+		return nil
+	})
+	return n, err
+}
+
+// checkNotDecided: после окончательного результата собеседование не переназначается
+func (s *InterviewSlotService) checkNotDecided(ctx context.Context, appID uuid.UUID) error {
+	inv, ok, err := s.interviews.GetByApplication(ctx, appID)
+	if err != nil {
+		return err
+	}
+	if ok && inv.Result != domain.InterviewPending && inv.Result != domain.InterviewNeedsMore {
+		return ErrInterviewDecided
+	}
+	return nil
+}
+
+func slotCancelled(slot domain.InterviewSlot, app domain.EnrollmentApplication, by, actorRole string) outbox.InterviewSlotCancelledV1 {
+	return outbox.InterviewSlotCancelledV1{
+		SlotID:        slot.ID,
+		ApplicationID: app.ID,
+		UserID:        app.UserID,
+		GroupID:       app.GroupID,
+		TeacherID:     slot.TeacherUserID,
+		StartsAt:      slot.StartsAt,
+		EndsAt:        slot.EndsAt,
+		CancelledBy:   by,
+		ActorRole:     actorRole,
+	}
+}
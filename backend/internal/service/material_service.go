@@ -9,7 +9,10 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
 	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
 )
 
@@ -18,22 +21,22 @@ var (
 )
 
 type MaterialService struct {
-	matRepo     *repo.MaterialRepo
-	appRepo     *repo.ApplicationRepo
-	catalogRepo *repo.CatalogRepo
+	matRepo *repo.MaterialRepo
+	outbox  *outbox.Repo
+	tx      *db.TxManager
+	az      *authz.Authorizer
 }
 
-func NewMaterialService(matRepo *repo.MaterialRepo, appRepo *repo.ApplicationRepo, catalogRepo *repo.CatalogRepo) *MaterialService {
-	return &MaterialService{matRepo: matRepo, appRepo: appRepo, catalogRepo: catalogRepo}
+func NewMaterialService(matRepo *repo.MaterialRepo, outboxRepo *outbox.Repo, tx *db.TxManager, az *authz.Authorizer) *MaterialService {
+	return &MaterialService{matRepo: matRepo, outbox: outboxRepo, tx: tx, az: az}
 }
 
 // learner: only if enrolled
 func (s *MaterialService) ListForLearner(ctx context.Context, groupID uuid.UUID) ([]domain.Material, error) {
-	userID, ok := auth.UserID(ctx)
-	if !ok {
+	if _, ok := auth.LearnerID(ctx); !ok {
 		return nil, errors.New("unauthorized")
 	}
-	has, err := s.appRepo.HasEnrollment(ctx, userID, groupID)
+	has, err := s.az.CanInGroup(ctx, authz.LearnAccess, groupID)
 	if err != nil {
 		return nil, err
 	}
@@ -45,21 +48,12 @@ func (s *MaterialService) ListForLearner(ctx context.Context, groupID uuid.UUID)
 
 // teacher/admin: can create
 func (s *MaterialService) CreateForGroup(ctx context.Context, groupID uuid.UUID, typ domain.MaterialType, title, content string) (uuid.UUID, error) {
-	role := auth.Role(ctx)
 	actorID, ok := auth.UserID(ctx)
 	if !ok {
 		return uuid.Nil, errors.New("unauthorized")
 	}
-
-	// admin always
-	if role != "admin" {
-		assigned, err := s.catalogRepo.IsTeacherInGroup(ctx, groupID, actorID)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		if !assigned {
-			return uuid.Nil, errors.New("forbidden")
-		}
+	if err := s.az.RequireInGroup(ctx, authz.MaterialCreate, groupID); err != nil {
+		return uuid.Nil, err
 	}
 
 	m := domain.Material{
@@ -70,7 +64,18 @@ func (s *MaterialService) CreateForGroup(ctx context.Context, groupID uuid.UUID,
 		Content:   content,
 		CreatedBy: actorID,
 	}
-	if err := s.matRepo.Create(ctx, m); err != nil {
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.matRepo.Create(ctx, m); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, "material", m.ID, outbox.MaterialCreatedV1{
+			MaterialID: m.ID,
+			GroupID:    groupID,
+			Title:      title,
+			Type:       string(typ),
+		})
+	})
+	if err != nil {
 		return uuid.Nil, err
 	}
 	return m.ID, nil
@@ -9,17 +9,18 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
 )
 
 type ProgressService struct {
 	progress *repo.ProgressRepo
 	matRepo  *repo.MaterialRepo
-	appRepo  *repo.ApplicationRepo
+	az       *authz.Authorizer
 }
 
-func NewProgressService(progress *repo.ProgressRepo, matRepo *repo.MaterialRepo, appRepo *repo.ApplicationRepo) *ProgressService {
-	return &ProgressService{progress: progress, matRepo: matRepo, appRepo: appRepo}
+func NewProgressService(progress *repo.ProgressRepo, matRepo *repo.MaterialRepo, az *authz.Authorizer) *ProgressService {
+	return &ProgressService{progress: progress, matRepo: matRepo, az: az}
 }
 
 func (s *ProgressService) MarkMaterialRead(ctx context.Context, materialID uuid.UUID) error {
@@ -36,7 +37,7 @@ func (s *ProgressService) MarkMaterialRead(ctx context.Context, materialID uuid.
 		return err
 	}
 
-	has, err := s.appRepo.HasEnrollment(ctx, userID, m.GroupID)
+	has, err := s.az.CanInGroup(ctx, authz.LearnAccess, m.GroupID)
 	if err != nil {
 		return err
 	}
@@ -46,3 +47,19 @@ func (s *ProgressService) MarkMaterialRead(ctx context.Context, materialID uuid.
 
 	return s.progress.MarkRead(ctx, userID, materialID, m.GroupID)
 }
+
+// GroupProgress: сводка для ученика (или guardian, смотрящего за ребёнком)
+func (s *ProgressService) GroupProgress(ctx context.Context, groupID uuid.UUID) (repo.ProgressSummary, error) {
+	userID, ok := auth.LearnerID(ctx)
+	if !ok {
+		return repo.ProgressSummary{}, errors.New("unauthorized")
+	}
+	has, err := s.az.CanInGroup(ctx, authz.LearnAccess, groupID)
+	if err != nil {
+		return repo.ProgressSummary{}, err
+	}
+	if !has {
+		return repo.ProgressSummary{}, ErrNoAccessToGroup
+	}
+	return s.progress.Summary(ctx, userID, groupID)
+}
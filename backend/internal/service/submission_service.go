@@ -10,19 +10,23 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Pavlushechko/itcube-education/internal/auth"
+	"github.com/Pavlushechko/itcube-education/internal/authz"
+	"github.com/Pavlushechko/itcube-education/internal/db"
 	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
 	"github.com/Pavlushechko/itcube-education/internal/repo"
 )
 
 type SubmissionService struct {
-	catalog *repo.CatalogRepo
-	appRepo *repo.ApplicationRepo
 	asgRepo *repo.AssignmentRepo
 	subRepo *repo.SubmissionRepo
+	outbox  *outbox.Repo
+	tx      *db.TxManager
+	az      *authz.Authorizer
 }
 
-func NewSubmissionService(catalog *repo.CatalogRepo, appRepo *repo.ApplicationRepo, asgRepo *repo.AssignmentRepo, subRepo *repo.SubmissionRepo) *SubmissionService {
-	return &SubmissionService{catalog: catalog, appRepo: appRepo, asgRepo: asgRepo, subRepo: subRepo}
+func NewSubmissionService(asgRepo *repo.AssignmentRepo, subRepo *repo.SubmissionRepo, outboxRepo *outbox.Repo, tx *db.TxManager, az *authz.Authorizer) *SubmissionService {
+	return &SubmissionService{asgRepo: asgRepo, subRepo: subRepo, outbox: outboxRepo, tx: tx, az: az}
 }
 
 // Student submits result (MVP: upsert single submission)
@@ -38,7 +42,7 @@ func (s *SubmissionService) Submit(ctx context.Context, assignmentID uuid.UUID,
 	}
 
 	// access: must be enrolled in group
-	has, err := s.appRepo.HasEnrollment(ctx, userID, asg.GroupID)
+	has, err := s.az.CanInGroup(ctx, authz.LearnAccess, asg.GroupID)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -67,7 +71,7 @@ func (s *SubmissionService) Submit(ctx context.Context, assignmentID uuid.UUID,
 
 // Student views own submission + latest review
 func (s *SubmissionService) MySubmission(ctx context.Context, assignmentID uuid.UUID) (domain.Submission, *domain.SubmissionReview, error) {
-	userID, ok := auth.UserID(ctx)
+	userID, ok := auth.LearnerID(ctx)
 	if !ok {
 		return domain.Submission{}, nil, errors.New("unauthorized")
 	}
@@ -92,20 +96,8 @@ func (s *SubmissionService) MySubmission(ctx context.Context, assignmentID uuid.
 
 // Teacher/Admin lists submissions for group
 func (s *SubmissionService) ListForTeacher(ctx context.Context, groupID uuid.UUID, status *string) ([]domain.Submission, error) {
-	actorID, ok := auth.UserID(ctx)
-	if !ok {
-		return nil, errors.New("unauthorized")
-	}
-	role := auth.Role(ctx)
-
-	if role != "admin" {
-		assigned, err := s.catalog.IsTeacherInGroup(ctx, groupID, actorID)
-		if err != nil {
-			return nil, err
-		}
-		if !assigned {
-			return nil, errors.New("forbidden")
-		}
+	if err := s.az.RequireInGroup(ctx, authz.SubmissionList, groupID); err != nil {
+		return nil, err
 	}
 
 	return s.subRepo.ListByGroup(ctx, groupID, status)
@@ -117,7 +109,6 @@ func (s *SubmissionService) Review(ctx context.Context, submissionID uuid.UUID,
 	if !ok {
 		return errors.New("unauthorized")
 	}
-	role := auth.Role(ctx)
 
 	// We need submission's group to check teacher assignment.
 	// MVP shortcut: query submission row here via SQL in repo:
@@ -126,14 +117,13 @@ func (s *SubmissionService) Review(ctx context.Context, submissionID uuid.UUID,
 		return err
 	}
 
-	if role != "admin" {
-		assigned, err := s.catalog.IsTeacherInGroup(ctx, sub.GroupID, actorID)
-		if err != nil {
-			return err
-		}
-		if !assigned {
-			return errors.New("forbidden")
-		}
+	if err := s.az.RequireInGroup(ctx, authz.SubmissionReview, sub.GroupID); err != nil {
+		return err
+	}
+
+	asg, err := s.asgRepo.Get(ctx, sub.AssignmentID)
+	if err != nil {
+		return err
 	}
 
 	rv := domain.SubmissionReview{
@@ -143,10 +133,25 @@ func (s *SubmissionService) Review(ctx context.Context, submissionID uuid.UUID,
 		Grade:        grade,
 		Comment:      comment,
 	}
-	if err := s.subRepo.AddReview(ctx, rv); err != nil {
-		return err
-	}
-	return s.subRepo.SetStatus(ctx, submissionID, domain.SubmissionReviewed)
+
+	// review + статус + событие (уведомление ученику) — одной транзакцией
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.subRepo.AddReview(ctx, rv); err != nil {
+			return err
+		}
+		if err := s.subRepo.SetStatus(ctx, submissionID, domain.SubmissionReviewed); err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, "submission", submissionID, outbox.SubmissionReviewedV1{
+			SubmissionID:    submissionID,
+			AssignmentID:    asg.ID,
+			AssignmentTitle: asg.Title,
+			GroupID:         sub.GroupID,
+			UserID:          sub.StudentUserID,
+			Grade:           grade,
+			Comment:         comment,
+		})
+	})
 }
 
 // small helper (keep MVP simple)
@@ -0,0 +1,80 @@
+// internal/storage/local_test.go
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalPathStaysInRoot(t *testing.T) {
+	l, err := NewLocal(filepath.Join(t.TempDir(), "files"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{
+		"",
+		".",
+		"..",
+		"../secret",
+		"apps/../../secret",
+		"../files2/a", // соседний каталог с тем же префиксом имени
+	} {
+		if p, err := l.path(key); err == nil {
+			t.Errorf("path(%q) = %q, want error", key, p)
+		}
+	}
+
+	for _, key := range []string{"a", "apps/42/doc.pdf", "apps/../b"} {
+		p, err := l.path(key)
+		if err != nil {
+			t.Errorf("path(%q): %v", key, err)
+			continue
+		}
+		if !strings.HasPrefix(p, l.root+string(filepath.Separator)) {
+			t.Errorf("path(%q) = %q is outside %q", key, p, l.root)
+		}
+	}
+}
+
+func TestLocalRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	l, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "apps/42/doc.pdf"
+	data := []byte("%PDF-1.4 test")
+
+	if err := l.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := l.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if !bytes.Equal(got, data) {
+		t.Errorf("Get = %q, want %q", got, data)
+	}
+
+	if err := l.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: %v, want ErrNotFound", err)
+	}
+	if err := l.Delete(ctx, key); err != nil {
+		t.Errorf("second Delete: %v", err)
+	}
+	if err := l.Put(ctx, "../escape", bytes.NewReader(data), int64(len(data)), ""); err == nil {
+		t.Error("Put outside root succeeded")
+	}
+}
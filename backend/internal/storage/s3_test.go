@@ -0,0 +1,198 @@
+// internal/storage/s3_test.go
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// s3Stub — бакет в памяти: проверяет path-style адрес и заголовки подписи
+type s3Stub struct {
+	t      *testing.T
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string][]byte // escaped path -> тело
+	types   map[string]string
+	paths   []string
+	fail    int // != 0 — ответить этим кодом
+}
+
+var authRe = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=AKID/(\d{8})/eu-central-1/s3/aws4_request, ` +
+	`SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`)
+
+func (s *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.EscapedPath()
+	if !strings.HasPrefix(p, "/"+s.bucket+"/") {
+		s.t.Errorf("%s %s: not a path-style URL for bucket %q", r.Method, p, s.bucket)
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	ts, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil || time.Since(ts).Abs() > time.Minute {
+		s.t.Errorf("X-Amz-Date = %q", amzDate)
+	}
+	if h := r.Header.Get("X-Amz-Content-Sha256"); h != unsignedPayload {
+		s.t.Errorf("X-Amz-Content-Sha256 = %q", h)
+	}
+	if m := authRe.FindStringSubmatch(r.Header.Get("Authorization")); m == nil || m[1] != amzDate[:8] {
+		s.t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths = append(s.paths, p)
+	if s.fail != 0 {
+		http.Error(w, "<Error><Code>InternalError</Code></Error>", s.fail)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		b, _ := io.ReadAll(r.Body)
+		s.objects[p] = b
+		s.types[p] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		b, ok := s.objects[p]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(b)
+	case http.MethodDelete:
+		if _, ok := s.objects[p]; !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		delete(s.objects, p)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.t.Errorf("unexpected method %s", r.Method)
+	}
+}
+
+func newS3Stub(t *testing.T) (*s3Stub, *S3) {
+	t.Helper()
+	stub := &s3Stub{t: t, bucket: "attachments", objects: map[string][]byte{}, types: map[string]string{}}
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+
+	s, err := NewS3(S3Config{
+		Endpoint:  srv.URL + "/",
+		Region:    "eu-central-1",
+		Bucket:    stub.bucket,
+		AccessKey: "AKID",
+		SecretKey: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stub, s
+}
+
+func TestS3RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	stub, s := newS3Stub(t)
+	key := "apps/42/отчёт 1.pdf"
+	data := []byte("%PDF-1.4 test")
+
+	if err := s.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+	want := "/attachments/apps/42/" + url.PathEscape("отчёт 1.pdf")
+	if stub.paths[0] != want {
+		t.Errorf("PUT path = %q, want %q", stub.paths[0], want)
+	}
+	if stub.types[want] != "application/pdf" {
+		t.Errorf("Content-Type = %q", stub.types[want])
+	}
+
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if !bytes.Equal(got, data) {
+		t.Errorf("Get = %q, want %q", got, data)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: %v, want ErrNotFound", err)
+	}
+	// удалить отсутствующий объект — не ошибка
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("second Delete: %v", err)
+	}
+}
+
+func TestS3ServerError(t *testing.T) {
+	stub, s := newS3Stub(t)
+	stub.fail = http.StatusInternalServerError
+
+	_, err := s.Get(context.Background(), "apps/1/a.txt")
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Get with 500: %v", err)
+	}
+}
+
+// Подпись для фиксированного запроса, канонический запрос выписан вручную
+func TestS3Sign(t *testing.T) {
+	s, err := NewS3(S3Config{
+		Endpoint:  "http://minio:9000",
+		Region:    "eu-central-1",
+		Bucket:    "attachments",
+		AccessKey: "AKID",
+		SecretKey: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := s.request(context.Background(), http.MethodGet, "apps/42/a b.pdf", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.URL.String(); got != "http://minio:9000/attachments/apps/42/a%20b.pdf" {
+		t.Fatalf("URL = %q", got)
+	}
+
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	s.sign(req, now)
+
+	canonical := "GET\n" +
+		"/attachments/apps/42/a%20b.pdf\n" +
+		"\n" +
+		"host:minio:9000\n" +
+		"x-amz-content-sha256:UNSIGNED-PAYLOAD\n" +
+		"x-amz-date:20260301T123000Z\n" +
+		"\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" +
+		"UNSIGNED-PAYLOAD"
+	stringToSign := "AWS4-HMAC-SHA256\n20260301T123000Z\n20260301/eu-central-1/s3/aws4_request\n" + sha256Hex(canonical)
+	k := hmacSHA256([]byte("AWS4secret"), "20260301")
+	k = hmacSHA256(k, "eu-central-1")
+	k = hmacSHA256(k, "s3")
+	k = hmacSHA256(k, "aws4_request")
+
+	want := "AWS4-HMAC-SHA256 Credential=AKID/20260301/eu-central-1/s3/aws4_request, " +
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=" + hex.EncodeToString(hmacSHA256(k, stringToSign))
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+	if req.Header.Get("X-Amz-Date") != "20260301T123000Z" {
+		t.Errorf("X-Amz-Date = %q", req.Header.Get("X-Amz-Date"))
+	}
+}
@@ -0,0 +1,98 @@
+// internal/storage/scanner.go
+
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Scanner: антивирусная проверка файла до сохранения.
+// clean=false — файл заражён, signature — имя найденной сигнатуры.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (clean bool, signature string, err error)
+}
+
+// NoopScanner: проверка отключена, все файлы считаются чистыми
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(context.Context, io.Reader) (bool, string, error) { return true, "", nil }
+
+// ClamdScanner: проверка через clamd (протокол INSTREAM по TCP)
+type ClamdScanner struct {
+	addr    string
+	timeout time.Duration
+}
+
+func NewClamdScanner(addr string, timeout time.Duration) *ClamdScanner {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &ClamdScanner{addr: addr, timeout: timeout}
+}
+
+const clamdChunk = 64 << 10
+
+func (c *ClamdScanner) Scan(ctx context.Context, r io.Reader) (bool, string, error) {
+	var d net.Dialer
+	cn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return false, "", fmt.Errorf("clamd dial: %w", err)
+	}
+	defer cn.Close()
+	_ = cn.SetDeadline(time.Now().Add(c.timeout))
+
+	if _, err := io.WriteString(cn, "zINSTREAM\x00"); err != nil {
+		return false, "", err
+	}
+	// поток чанков: 4 байта длины (big endian) + данные, завершает чанк нулевой длины
+	buf := make([]byte, clamdChunk)
+	var size [4]byte
+	for {
+		n, rerr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, err := cn.Write(size[:]); err != nil {
+				return false, "", err
+			}
+			if _, err := cn.Write(buf[:n]); err != nil {
+				return false, "", err
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return false, "", rerr
+		}
+	}
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err := cn.Write(size[:]); err != nil {
+		return false, "", err
+	}
+
+	// ответ: "stream: OK" или "stream: <signature> FOUND"
+	reply, err := bufio.NewReader(cn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return false, "", err
+	}
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	switch {
+	case strings.HasSuffix(reply, " OK"):
+		return true, "", nil
+	case strings.HasSuffix(reply, " FOUND"):
+		sig := strings.TrimSuffix(reply, " FOUND")
+		if i := strings.Index(sig, ": "); i >= 0 {
+			sig = sig[i+2:]
+		}
+		return false, sig, nil
+	default:
+		return false, "", fmt.Errorf("clamd: %s", reply)
+	}
+}
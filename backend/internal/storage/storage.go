@@ -0,0 +1,19 @@
+// internal/storage/storage.go
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// Storage — хранилище файлов (вложения заявок). Ключи — пути через "/",
+// без ".." и ведущего "/" (их собирает сервис, не пользователь).
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,212 @@
+// internal/webhook/deliverer.go
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+type DelivererConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Lease        time.Duration
+	Timeout      time.Duration // на один HTTP запрос
+}
+
+// deliveryStore — то, что Deliverer берёт из repo.WebhookRepo (в тестах — фейк)
+type deliveryStore interface {
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]repo.WebhookJob, error)
+	RecordAttempt(ctx context.Context, a domain.WebhookAttempt, status domain.WebhookDeliveryStatus, next time.Time) error
+}
+
+// txRunner — db.TxManager
+type txRunner interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Deliverer отправляет webhook_deliveries и пишет историю попыток
+type Deliverer struct {
+	webhooks deliveryStore
+	tx       txRunner
+	client   *http.Client
+	cfg      DelivererConfig
+}
+
+func NewDeliverer(webhooks deliveryStore, tx txRunner, cfg DelivererConfig) *Deliverer {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 20
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 10 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 6 * time.Hour
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = cfg.Timeout + time.Minute
+	}
+	return &Deliverer{webhooks: webhooks, tx: tx, client: &http.Client{Timeout: cfg.Timeout}, cfg: cfg}
+}
+
+// WithClient — подменить http.Client (например, httptest.Server.Client())
+func (d *Deliverer) WithClient(c *http.Client) *Deliverer {
+	d.client = c
+	return d
+}
+
+func (d *Deliverer) Run(ctx context.Context) {
+	t := time.NewTicker(d.cfg.PollInterval)
+	defer t.Stop()
+
+	for {
+		n, err := d.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("webhook deliver", "err", err)
+		}
+		// после ошибки ждём тика, чтобы не крутиться вхолостую на больной БД
+		if err == nil && n == d.cfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (d *Deliverer) RunOnce(ctx context.Context) (int, error) {
+	jobs, err := d.webhooks.ClaimDeliveries(ctx, d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+	// ошибка одной доставки не останавливает пачку: остальные уже взяты в lease
+	failed := 0
+	for _, j := range jobs {
+		if _, err := d.Deliver(ctx, j); err != nil {
+			slog.Error("webhook deliver job", "delivery_id", j.Delivery.ID, "err", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return len(jobs), fmt.Errorf("%d of %d deliveries not recorded", failed, len(jobs))
+	}
+	return len(jobs), nil
+}
+
+// Lease: на сколько доставка уходит из выборки ClaimDeliveries, пока идёт попытка
+func (d *Deliverer) Lease() time.Duration { return d.cfg.Lease }
+
+// Deliver: одна попытка отправки; ошибка — только если не удалось записать результат
+func (d *Deliverer) Deliver(ctx context.Context, j repo.WebhookJob) (domain.WebhookAttempt, error) {
+	a := d.send(ctx, j)
+
+	status := domain.DeliveryDelivered
+	next := time.Now()
+	if a.StatusCode == nil || *a.StatusCode < 200 || *a.StatusCode >= 300 {
+		status = domain.DeliveryPending
+		next = next.Add(d.backoff(a.AttemptNo))
+		if a.AttemptNo >= d.cfg.MaxAttempts {
+			status = domain.DeliveryFailed
+		}
+	}
+
+	err := d.tx.Do(ctx, func(ctx context.Context) error {
+		return d.webhooks.RecordAttempt(ctx, a, status, next)
+	})
+	return a, err
+}
+
+// Body запроса; event_id — ключ идемпотентности для получателя
+type message struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func (d *Deliverer) send(ctx context.Context, j repo.WebhookJob) domain.WebhookAttempt {
+	a := domain.WebhookAttempt{
+		ID:         uuid.New(),
+		DeliveryID: j.Delivery.ID,
+		AttemptNo:  j.Delivery.Attempts + 1,
+	}
+	fail := func(err error) domain.WebhookAttempt {
+		s := err.Error()
+		a.Error = &s
+		return a
+	}
+
+	body, err := json.Marshal(message{
+		EventID:   j.Delivery.EventID.String(),
+		EventType: j.Delivery.EventType,
+		CreatedAt: j.Delivery.CreatedAt,
+		Data:      j.Delivery.Payload,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.URL, bytes.NewReader(body))
+	if err != nil {
+		return fail(err)
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, j.Delivery.EventType)
+	req.Header.Set(HeaderDelivery, j.Delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(j.Secret, ts, body))
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	a.DurationMs = int(time.Since(start).Milliseconds())
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+
+	code := resp.StatusCode
+	a.StatusCode = &code
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	a.ResponseBody = string(snippet)
+	return a
+}
+
+// backoff: base * 2^(attempt-1), не больше MaxBackoff
+func (d *Deliverer) backoff(attempt int) time.Duration {
+	b := d.cfg.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		b *= 2
+		if b >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return b
+}
@@ -0,0 +1,280 @@
+// internal/webhook/deliverer_test.go
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+	"github.com/Pavlushechko/itcube-education/internal/repo"
+)
+
+// fakeStore — webhook_deliveries в памяти, с тем же уникальным ключом (subscription_id, event_id)
+type fakeStore struct {
+	mu         sync.Mutex
+	subs       []domain.WebhookSubscription
+	deliveries []*domain.WebhookDelivery
+	attempts   []domain.WebhookAttempt
+	failRecord int // столько следующих RecordAttempt вернут ошибку
+}
+
+type pair struct{ sub, event uuid.UUID }
+
+func (s *fakeStore) ListSubscriptionsForEvent(_ context.Context, _ string) ([]domain.WebhookSubscription, error) {
+	return s.subs, nil
+}
+
+func (s *fakeStore) EnqueueDelivery(_ context.Context, d domain.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, x := range s.deliveries {
+		if (pair{x.SubscriptionID, x.EventID}) == (pair{d.SubscriptionID, d.EventID}) {
+			return nil // on conflict do nothing
+		}
+	}
+	d.Status = domain.DeliveryPending
+	d.CreatedAt = time.Now()
+	d.NextAttemptAt = d.CreatedAt
+	s.deliveries = append(s.deliveries, &d)
+	return nil
+}
+
+// ClaimDeliveries: все pending, без учёта next_attempt_at — тест сам решает, когда повторять
+func (s *fakeStore) ClaimDeliveries(_ context.Context, limit int, _ time.Duration) ([]repo.WebhookJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []repo.WebhookJob
+	for _, d := range s.deliveries {
+		if d.Status != domain.DeliveryPending || len(res) == limit {
+			continue
+		}
+		for _, sub := range s.subs {
+			if sub.ID == d.SubscriptionID {
+				res = append(res, repo.WebhookJob{Delivery: *d, URL: sub.URL, Secret: sub.Secret})
+			}
+		}
+	}
+	return res, nil
+}
+
+func (s *fakeStore) RecordAttempt(_ context.Context, a domain.WebhookAttempt, status domain.WebhookDeliveryStatus, next time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failRecord > 0 {
+		s.failRecord--
+		return errors.New("db is down")
+	}
+	s.attempts = append(s.attempts, a)
+	for _, d := range s.deliveries {
+		if d.ID == a.DeliveryID {
+			d.Attempts, d.Status, d.NextAttemptAt, d.LastStatusCode = a.AttemptNo, status, next, a.StatusCode
+		}
+	}
+	return nil
+}
+
+type noTx struct{}
+
+func (noTx) Do(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }
+
+// receiver — httptest получатель: проверяет подпись, отвечает кодами из codes по очереди (потом 200)
+type receiver struct {
+	t      *testing.T
+	secret string
+
+	mu    sync.Mutex
+	codes []int
+	got   []message
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	ts, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		rc.t.Errorf("bad %s: %q", HeaderTimestamp, r.Header.Get(HeaderTimestamp))
+	}
+	if !Verify(rc.secret, ts, body, r.Header.Get(HeaderSignature)) {
+		rc.t.Errorf("signature %q does not match body", r.Header.Get(HeaderSignature))
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+		rc.t.Errorf("Content-Type = %q", ct)
+	}
+	var m message
+	if err := json.Unmarshal(body, &m); err != nil {
+		rc.t.Errorf("body is not json: %v", err)
+	}
+	if r.Header.Get(HeaderEvent) != m.EventType {
+		rc.t.Errorf("%s = %q, body event_type = %q", HeaderEvent, r.Header.Get(HeaderEvent), m.EventType)
+	}
+
+	rc.mu.Lock()
+	rc.got = append(rc.got, m)
+	code := http.StatusOK
+	if len(rc.codes) > 0 {
+		code, rc.codes = rc.codes[0], rc.codes[1:]
+	}
+	rc.mu.Unlock()
+	w.WriteHeader(code)
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.got)
+}
+
+func setup(t *testing.T, codes ...int) (*fakeStore, *receiver, *Deliverer) {
+	t.Helper()
+	rc := &receiver{t: t, secret: "s3cr3t", codes: codes}
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	st := &fakeStore{subs: []domain.WebhookSubscription{{ID: uuid.New(), URL: srv.URL, Secret: rc.secret, IsEnabled: true}}}
+	d := NewDeliverer(st, noTx{}, DelivererConfig{
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  time.Hour,
+	}).WithClient(srv.Client())
+	return st, rc, d
+}
+
+func event() outbox.Event {
+	return outbox.Event{
+		ID:        uuid.New(),
+		EventType: "application.approved",
+		Payload:   json.RawMessage(`{"application_id":"42"}`),
+	}
+}
+
+func TestDeliverSignsBody(t *testing.T) {
+	st, rc, d := setup(t)
+	ctx := context.Background()
+	e := event()
+
+	if err := NewFanout(st).Handle(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.RunOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("RunOnce = %d, %v", n, err)
+	}
+
+	if rc.count() != 1 {
+		t.Fatalf("receiver got %d requests, want 1", rc.count())
+	}
+	m := rc.got[0]
+	if m.EventID != e.ID.String() || m.EventType != e.EventType || string(m.Data) != string(e.Payload) {
+		t.Errorf("body = %+v", m)
+	}
+	if st.deliveries[0].Status != domain.DeliveryDelivered {
+		t.Errorf("status = %s, want delivered", st.deliveries[0].Status)
+	}
+}
+
+func TestDeliverRetriesWithBackoff(t *testing.T) {
+	st, rc, d := setup(t, http.StatusInternalServerError, http.StatusBadGateway)
+	ctx := context.Background()
+	if err := NewFanout(st).Handle(ctx, event()); err != nil {
+		t.Fatal(err)
+	}
+	dl := st.deliveries[0]
+
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute} {
+		before := time.Now()
+		if _, err := d.RunOnce(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if dl.Status != domain.DeliveryPending || dl.Attempts != i+1 {
+			t.Fatalf("attempt %d: status = %s, attempts = %d", i+1, dl.Status, dl.Attempts)
+		}
+		if got := dl.NextAttemptAt.Sub(before); got < want || got > want+time.Second {
+			t.Errorf("attempt %d: next in %s, want %s", i+1, got, want)
+		}
+		if dl.LastStatusCode == nil || *dl.LastStatusCode < 500 {
+			t.Errorf("attempt %d: last status code = %v", i+1, dl.LastStatusCode)
+		}
+	}
+
+	if _, err := d.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if dl.Status != domain.DeliveryDelivered || dl.Attempts != 3 || rc.count() != 3 {
+		t.Errorf("status = %s, attempts = %d, requests = %d", dl.Status, dl.Attempts, rc.count())
+	}
+}
+
+func TestDeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	st, rc, d := setup(t, 500, 500, 500, 500)
+	ctx := context.Background()
+	if err := NewFanout(st).Handle(ctx, event()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := d.RunOnce(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if st.deliveries[0].Status != domain.DeliveryFailed || rc.count() != 3 {
+		t.Errorf("status = %s, requests = %d; want failed after 3", st.deliveries[0].Status, rc.count())
+	}
+}
+
+func TestSameEventDeliveredOnce(t *testing.T) {
+	st, rc, d := setup(t)
+	ctx := context.Background()
+	e := event()
+
+	// outbox повторил событие (например, после падения до MarkDispatched)
+	for i := 0; i < 2; i++ {
+		if err := NewFanout(st).Handle(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := d.RunOnce(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(st.deliveries) != 1 || rc.count() != 1 {
+		t.Errorf("deliveries = %d, requests = %d; want 1 and 1", len(st.deliveries), rc.count())
+	}
+}
+
+func TestRunOnceContinuesAfterFailedRecord(t *testing.T) {
+	st, rc, d := setup(t)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := NewFanout(st).Handle(ctx, event()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st.failRecord = 1
+	n, err := d.RunOnce(ctx)
+	if err == nil || n != 3 {
+		t.Fatalf("RunOnce = %d, %v; want 3 and an error", n, err)
+	}
+	// первая доставка осталась pending (повторится после lease), остальные ушли в той же пачке
+	if rc.count() != 3 || len(st.attempts) != 2 {
+		t.Errorf("requests = %d, recorded attempts = %d; want 3 and 2", rc.count(), len(st.attempts))
+	}
+	if st.deliveries[0].Status != domain.DeliveryPending {
+		t.Errorf("first delivery status = %s, want pending", st.deliveries[0].Status)
+	}
+	for _, dl := range st.deliveries[1:] {
+		if dl.Status != domain.DeliveryDelivered {
+			t.Errorf("delivery %s status = %s, want delivered", dl.ID, dl.Status)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// internal/webhook/fanout.go
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Pavlushechko/itcube-education/internal/domain"
+	"github.com/Pavlushechko/itcube-education/internal/outbox"
+)
+
+// subscriptionStore — то, что Fanout берёт из repo.WebhookRepo
+type subscriptionStore interface {
+	ListSubscriptionsForEvent(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error)
+	EnqueueDelivery(ctx context.Context, d domain.WebhookDelivery) error
+}
+
+// Fanout — outbox.Sink: раскладывает событие по доставкам подписок.
+// Саму отправку и повторы делает Deliverer, поэтому медленный получатель не держит outbox.
+type Fanout struct {
+	webhooks subscriptionStore
+}
+
+func NewFanout(webhooks subscriptionStore) *Fanout { return &Fanout{webhooks: webhooks} }
+
+func (f *Fanout) Name() string { return "webhooks" }
+
+func (f *Fanout) Handle(ctx context.Context, e outbox.Event) error {
+	subs, err := f.webhooks.ListSubscriptionsForEvent(ctx, e.EventType)
+	if err != nil {
+		return err
+	}
+	for _, s := range subs {
+		if err := f.webhooks.EnqueueDelivery(ctx, domain.WebhookDelivery{
+			ID:             uuid.New(),
+			SubscriptionID: s.ID,
+			EventID:        e.ID,
+			EventType:      e.EventType,
+			Payload:        e.Payload,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
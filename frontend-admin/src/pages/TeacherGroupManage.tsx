@@ -4,9 +4,11 @@ import { useEffect, useState } from 'react'
 import { useParams } from 'react-router-dom'
 import { api } from '../lib/api'
 
+type Student = { UserID: string; FullName: string | null }
+
 export function TeacherGroupManage() {
   const { groupId } = useParams()
-  const [students, setStudents] = useState<string[]>([])
+  const [students, setStudents] = useState<Student[]>([])
   const [err, setErr] = useState('')
 
   useEffect(() => {
@@ -24,7 +26,7 @@ export function TeacherGroupManage() {
 
       <h3>Студенты</h3>
       <ul>
-        {students.map(s => <li key={s}>{s}</li>)}
+        {students.map(s => <li key={s.UserID}>{s.FullName || s.UserID}</li>)}
       </ul>
 
       <div style={{ opacity: 0.75, marginTop: 10 }}>
@@ -4,9 +4,11 @@ import { useEffect, useState } from 'react'
 import { api } from '../lib/api'
 import { useParams } from 'react-router-dom'
 
+type Student = { UserID: string; FullName: string | null }
+
 export function TeacherGroupStudents() {
   const { groupId } = useParams()
-  const [students, setStudents] = useState<string[]>([])
+  const [students, setStudents] = useState<Student[]>([])
   const [err, setErr] = useState('')
 
   useEffect(() => {
@@ -21,7 +23,7 @@ export function TeacherGroupStudents() {
       <h2>Студенты группы {groupId}</h2>
       {err && <div style={{ color: 'crimson' }}>{err}</div>}
       <ul>
-        {students.map(id => <li key={id}>{id}</li>)}
+        {students.map(s => <li key={s.UserID}>{s.FullName || s.UserID}</li>)}
       </ul>
     </div>
   )